@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -37,6 +38,14 @@ type BatchOptions struct {
 	ChunkSize     int // Chunk size for large documents
 	ChunkOverlap  int // Overlap between chunks
 
+	// Rate limiting options
+	RequestsPerMinute int // Maximum model requests per minute, 0 for no limit
+	TokensPerMinute   int // Maximum model tokens per minute, 0 for no limit
+
+	// Budget options
+	MaxCostUSD     float64 // Maximum estimated cost of the batch in USD, 0 for no limit
+	MaxTotalTokens int     // Maximum tokens of the batch, 0 for no limit
+
 	// Output options
 	OutputDir    string // Output directory
 	OutputFormat string // Output format
@@ -55,6 +64,10 @@ type BatchOptions struct {
 	// Validation options
 	ValidateSchema   bool // Validate against schema
 	ValidateExamples bool // Validate examples
+
+	// Replay options
+	Record string // Directory to record provider responses to
+	Replay string // Directory to replay provider responses from
 }
 
 // BatchResult represents the result of processing a single file
@@ -65,6 +78,8 @@ type BatchResult struct {
 	Error      error
 	Duration   time.Duration
 	NumExtracted int
+	TokensUsed int
+	CostUSD    float64
 }
 
 // NewBatchCommand creates the batch command
@@ -105,7 +120,14 @@ Examples:
   langextract batch --schema events.yaml --merge --output results.json docs/
   
   # Continue processing despite errors
-  langextract batch --schema schema.yaml --continue-on-error --max-errors 5 --output results/ files/`,
+  langextract batch --schema schema.yaml --continue-on-error --max-errors 5 --output results/ files/
+
+  # Stop starting new files once the batch has cost $2
+  langextract batch --schema schema.yaml --max-cost-usd 2 --output results/ docs/
+
+  # Record provider responses once, then replay them without network access
+  langextract batch --schema schema.yaml --record cassettes/ --output results/ docs/
+  langextract batch --schema schema.yaml --replay cassettes/ --output results/ docs/`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.Inputs = args
@@ -127,6 +149,10 @@ Examples:
 
 	// Processing flags
 	cmd.Flags().IntVar(&opts.Concurrency, "concurrency", opts.Concurrency, "Number of concurrent extractions")
+	cmd.Flags().IntVar(&opts.RequestsPerMinute, "requests-per-minute", 0, "Maximum model requests per minute (0 for no limit)")
+	cmd.Flags().IntVar(&opts.TokensPerMinute, "tokens-per-minute", 0, "Maximum model tokens per minute (0 for no limit)")
+	cmd.Flags().Float64Var(&opts.MaxCostUSD, "max-cost-usd", 0, "Maximum estimated cost of the batch in USD (0 for no limit)")
+	cmd.Flags().IntVar(&opts.MaxTotalTokens, "max-total-tokens", 0, "Maximum tokens used by the batch (0 for no limit)")
 	cmd.Flags().IntVar(&opts.ContextWindow, "context-window", opts.ContextWindow, "Context window size for chunking")
 	cmd.Flags().IntVar(&opts.ChunkSize, "chunk-size", opts.ChunkSize, "Chunk size for large documents")
 	cmd.Flags().IntVar(&opts.ChunkOverlap, "chunk-overlap", opts.ChunkOverlap, "Overlap between chunks")
@@ -150,6 +176,10 @@ Examples:
 	cmd.Flags().BoolVar(&opts.ValidateSchema, "validate-schema", true, "Validate against schema")
 	cmd.Flags().BoolVar(&opts.ValidateExamples, "validate-examples", false, "Validate examples")
 
+	// Replay flags
+	cmd.Flags().StringVar(&opts.Record, "record", "", "Record provider responses to cassettes in this directory")
+	cmd.Flags().StringVar(&opts.Replay, "replay", "", "Replay provider responses from cassettes in this directory")
+
 	// Mark required flags
 	cmd.MarkFlagRequired("schema")
 	cmd.MarkFlagRequired("output")
@@ -215,6 +245,20 @@ func runBatch(ctx context.Context, opts *BatchOptions, cfg *config.GlobalConfig,
 	}
 	// Note: MaxTokens should be configured via ModelConfig, not directly on options
 
+	// Rate limits are shared by the providers of all concurrent extractions
+	if opts.RequestsPerMinute > 0 || opts.TokensPerMinute > 0 {
+		modelConfig(extractOpts).WithRateLimit(opts.RequestsPerMinute, opts.TokensPerMinute)
+	}
+
+	applyReplay(extractOpts, opts.Record, opts.Replay)
+
+	// A single budget tracks the spend of all files
+	budget := langextract.NewBudget(langextract.BudgetLimits{
+		MaxCostUSD: opts.MaxCostUSD,
+		MaxTokens:  opts.MaxTotalTokens,
+	})
+	extractOpts = extractOpts.WithBudget(budget)
+
 	// Add examples
 	if len(examples) > 0 {
 		// Convert string examples to ExampleData - this would need proper implementation
@@ -303,7 +347,7 @@ func runBatch(ctx context.Context, opts *BatchOptions, cfg *config.GlobalConfig,
 
 	// Show summary
 	if opts.ShowSummary {
-		showBatchSummary(allResults, duration, log)
+		showBatchSummary(allResults, budget, duration, log)
 	}
 
 	log.WithOperation("batch").
@@ -332,6 +376,18 @@ func validateBatchOptions(opts *BatchOptions) error {
 		return fmt.Errorf("concurrency must be between 1 and 100")
 	}
 
+	if opts.RequestsPerMinute < 0 || opts.TokensPerMinute < 0 {
+		return fmt.Errorf("rate limits must not be negative")
+	}
+
+	if opts.MaxCostUSD < 0 || opts.MaxTotalTokens < 0 {
+		return fmt.Errorf("budget limits must not be negative")
+	}
+
+	if opts.Record != "" && opts.Replay != "" {
+		return fmt.Errorf("record and replay cannot be used together")
+	}
+
 	if opts.Temperature < 0 || opts.Temperature > 2.0 {
 		return fmt.Errorf("temperature must be between 0.0 and 2.0")
 	}
@@ -445,6 +501,12 @@ func processSingleFile(ctx context.Context, inputFile string, opts *BatchOptions
 		Success:   false,
 	}
 
+	// Files are not started once the batch budget is spent
+	if extractOpts.Budget.Exceeded() {
+		result.Error = fmt.Errorf("skipped: %w", langextract.ErrBudgetExceeded)
+		return result
+	}
+
 	// Read input file
 	input, err := os.ReadFile(inputFile)
 	if err != nil {
@@ -454,14 +516,17 @@ func processSingleFile(ctx context.Context, inputFile string, opts *BatchOptions
 	}
 
 	// Perform extraction
-	extracted, err := langextract.Extract(string(input), extractOpts)
+	extractResult, err := langextract.ExtractWithMetadata(string(input), extractOpts)
 	if err != nil {
 		result.Error = fmt.Errorf("extraction failed: %w", err)
 		result.Duration = time.Since(startTime)
 		return result
 	}
 
+	extracted := extractResult.Document
 	result.NumExtracted = len(extracted.Extractions)
+	result.TokensUsed = extractResult.Metadata.TokensUsed
+	result.CostUSD = extractResult.Metadata.CostUSD
 
 	// Create visualization options
 	vizOpts := langextract.NewVisualizeOptions().
@@ -526,10 +591,12 @@ func writeMergedResults(results []interface{}, opts *BatchOptions) error {
 	return os.WriteFile(outputFile, []byte(content), 0644)
 }
 
-// showBatchSummary shows a summary of batch processing results
-func showBatchSummary(results []BatchResult, duration time.Duration, log *logger.Logger) {
+// showBatchSummary shows a summary of batch processing results. The spend
+// includes the requests of files that failed.
+func showBatchSummary(results []BatchResult, budget *langextract.Budget, duration time.Duration, log *logger.Logger) {
 	successCount := 0
 	errorCount := 0
+	skippedCount := 0
 	totalExtracted := 0
 
 	for _, result := range results {
@@ -538,15 +605,26 @@ func showBatchSummary(results []BatchResult, duration time.Duration, log *logger
 			totalExtracted += result.NumExtracted
 		} else {
 			errorCount++
+			if errors.Is(result.Error, langextract.ErrBudgetExceeded) {
+				skippedCount++
+			}
 		}
 	}
 
+	spend := budget.Spent()
 	log.WithFields(map[string]interface{}{
 		"total_files": len(results),
 		"successful":  successCount,
 		"failed":      errorCount,
 		"extracted":   totalExtracted,
+		"requests":    spend.Requests,
+		"tokens":      spend.TokensUsed,
+		"cost_usd":    fmt.Sprintf("%.4f", spend.CostUSD),
 		"duration":    duration.String(),
 	}).Info("Batch processing summary")
+
+	if budget.Exceeded() {
+		log.WithOperation("batch").Warningf("Budget of %s reached: %d files were stopped or not started", budget.Limits(), skippedCount)
+	}
 }
 
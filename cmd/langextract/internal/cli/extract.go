@@ -13,7 +13,9 @@ import (
 
 	"github.com/sehwan505/langextract-go/cmd/langextract/internal/config"
 	"github.com/sehwan505/langextract-go/cmd/langextract/internal/logger"
+	"github.com/sehwan505/langextract-go/pkg/document"
 	"github.com/sehwan505/langextract-go/pkg/langextract"
+	"github.com/sehwan505/langextract-go/pkg/providers"
 )
 
 // ExtractOptions contains all options for the extract command
@@ -43,6 +45,10 @@ type ExtractOptions struct {
 	// Validation options
 	ValidateSchema   bool // Validate against schema
 	ValidateExamples bool // Validate examples
+
+	// Replay options
+	Record string // Directory to record provider responses to
+	Replay string // Directory to replay provider responses from
 }
 
 // NewExtractCommand creates the extract command
@@ -118,6 +124,10 @@ Examples:
 	cmd.Flags().BoolVar(&opts.ValidateSchema, "validate-schema", true, "Validate against schema")
 	cmd.Flags().BoolVar(&opts.ValidateExamples, "validate-examples", false, "Validate examples")
 
+	// Replay flags
+	cmd.Flags().StringVar(&opts.Record, "record", "", "Record provider responses to cassettes in this directory")
+	cmd.Flags().StringVar(&opts.Replay, "replay", "", "Replay provider responses from cassettes in this directory")
+
 	// Mark required flags
 	cmd.MarkFlagRequired("schema")
 
@@ -134,7 +144,7 @@ func runExtract(ctx context.Context, opts *ExtractOptions, cfg *config.GlobalCon
 	}
 
 	// Read input
-	input, err := readInput(opts.Input, opts.InputType)
+	doc, err := readInput(ctx, opts.Input, opts.InputType)
 	if err != nil {
 		return fmt.Errorf("failed to read input: %w", err)
 	}
@@ -171,6 +181,8 @@ func runExtract(ctx context.Context, opts *ExtractOptions, cfg *config.GlobalCon
 	}
 	// Note: MaxTokens should be configured via ModelConfig, not directly on options
 
+	applyReplay(extractOpts, opts.Record, opts.Replay)
+
 	// Add examples
 	if len(examples) > 0 {
 		// Convert string examples to ExampleData - this would need proper implementation
@@ -181,7 +193,7 @@ func runExtract(ctx context.Context, opts *ExtractOptions, cfg *config.GlobalCon
 	// Perform extraction
 	log.WithOperation("extract").WithFile(opts.Input).Info("Performing extraction")
 	
-	result, err := langextract.Extract(input, extractOpts)
+	result, err := langextract.Extract(doc, extractOpts)
 	if err != nil {
 		return fmt.Errorf("extraction failed: %w", err)
 	}
@@ -233,19 +245,44 @@ func validateExtractOptions(opts *ExtractOptions) error {
 		return fmt.Errorf("chunk-overlap must be less than chunk-size")
 	}
 
+	if opts.Record != "" && opts.Replay != "" {
+		return fmt.Errorf("record and replay cannot be used together")
+	}
+
 	return nil
 }
 
-// readInput reads input from file, URL, or stdin
-func readInput(input, inputType string) (string, error) {
+// modelConfig returns the model configuration of the extraction options,
+// creating one for the selected model if needed.
+func modelConfig(extractOpts *langextract.ExtractOptions) *providers.ModelConfig {
+	if extractOpts.ModelConfig == nil {
+		extractOpts.WithModelConfig(providers.NewModelConfig(extractOpts.ModelID))
+	}
+	return extractOpts.ModelConfig
+}
+
+// applyReplay configures the provider to record responses to, or replay them
+// from, a cassette directory.
+func applyReplay(extractOpts *langextract.ExtractOptions, record, replay string) {
+	switch {
+	case record != "":
+		modelConfig(extractOpts).WithReplay(providers.ReplayModeRecord, record)
+	case replay != "":
+		modelConfig(extractOpts).WithReplay(providers.ReplayModeReplay, replay)
+	}
+}
+
+// readInput reads the input document from a file, URL, or stdin. Documents
+// fetched from URLs keep their source URL, metadata and source map.
+func readInput(ctx context.Context, input, inputType string) (*document.Document, error) {
 	switch input {
 	case "", "-":
 		// Read from stdin
 		data, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			return "", fmt.Errorf("failed to read from stdin: %w", err)
+			return nil, fmt.Errorf("failed to read from stdin: %w", err)
 		}
-		return string(data), nil
+		return document.NewDocument(string(data)), nil
 		
 	default:
 		// Auto-detect input type if not specified
@@ -263,19 +300,18 @@ func readInput(input, inputType string) (string, error) {
 		case "file":
 			data, err := os.ReadFile(input)
 			if err != nil {
-				return "", fmt.Errorf("failed to read file %s: %w", input, err)
+				return nil, fmt.Errorf("failed to read file %s: %w", input, err)
 			}
-			return string(data), nil
+			return document.NewDocument(string(data)), nil
 
 		case "url":
-			// For now, return an error for URL input - this would need HTTP client implementation
-			return "", fmt.Errorf("URL input not yet implemented")
+			return langextract.NewURLFetcher().Fetch(ctx, input)
 
 		case "text":
-			return input, nil
+			return document.NewDocument(input), nil
 
 		default:
-			return "", fmt.Errorf("unsupported input type: %s", inputType)
+			return nil, fmt.Errorf("unsupported input type: %s", inputType)
 		}
 	}
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/sehwan505/langextract-go/cmd/langextract/internal/config"
 	"github.com/sehwan505/langextract-go/cmd/langextract/internal/logger"
+	"github.com/sehwan505/langextract-go/pkg/providers"
 )
 
 // ProviderInfo represents information about a language model provider
@@ -63,8 +64,10 @@ type ProvidersOptions struct {
 	Interactive bool             // Interactive configuration
 
 	// Installation options
-	InstallDir string // Installation directory for providers
-	Force      bool   // Force installation/uninstallation
+	Force         bool     // Force installation/uninstallation
+	PluginName    string   // Provider name to install a plugin as
+	PluginCommand string   // Plugin executable to install
+	PluginArgs    []string // Arguments of the plugin executable
 }
 
 // NewProvidersCommand creates the providers command with subcommands
@@ -90,8 +93,11 @@ and install/uninstall provider plugins.
 Available providers typically include:
 - openai: OpenAI GPT models
 - gemini: Google Gemini models  
+- anthropic: Anthropic Claude models
 - ollama: Local Ollama models
 
+Provider plugins installed with "providers install" are available as well.
+
 Examples:
   # List all available providers
   langextract providers list
@@ -256,27 +262,37 @@ Examples:
 // NewProvidersInstallCommand creates the providers install subcommand
 func NewProvidersInstallCommand(cfg *config.GlobalConfig, log *logger.Logger, opts *ProvidersOptions) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "install [provider]",
+		Use:   "install <plugin-executable> [-- plugin-args...]",
 		Short: "Install provider plugins",
 		Long: `Install language model provider plugins. This allows adding
 support for additional providers beyond the built-in ones.
 
+A plugin is an executable that speaks the langextract plugin protocol
+(JSON-RPC over stdin and stdout, see docs/plugin-protocol.md). Installing
+it starts the plugin to check its capabilities, and registers it in the
+plugins directory of the configuration directory. Installed plugins are
+available as providers, and the models they report route to them.
+
 Examples:
   # Install a provider plugin
-  langextract providers install custom-provider
+  langextract providers install ./langextract-custom-provider
+  
+  # Install under another name, with plugin arguments
+  langextract providers install --name custom langextract-plugin -- --endpoint http://localhost:8080
   
   # Force reinstall
-  langextract providers install custom-provider --force`,
-		Args: cobra.ExactArgs(1),
+  langextract providers install ./langextract-custom-provider --force`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.Provider = args[0]
+			opts.PluginCommand = args[0]
+			opts.PluginArgs = args[1:]
 			opts.Operation = "install" // Explicitly set operation
 			return runProvidersInstall(cmd.Context(), opts, cfg, log)
 		},
 	}
 
 	// Installation flags
-	cmd.Flags().StringVar(&opts.InstallDir, "install-dir", "", "Installation directory")
+	cmd.Flags().StringVar(&opts.PluginName, "name", "", "Provider name (defaults to the name reported by the plugin)")
 	cmd.Flags().BoolVar(&opts.Force, "force", opts.Force, "Force installation")
 
 	return cmd
@@ -369,7 +385,7 @@ func runProvidersTest(ctx context.Context, opts *ProvidersOptions, cfg *config.G
 	// Determine which providers to test
 	var providersToTest []string
 	if opts.All {
-		providersToTest = []string{"openai", "gemini", "ollama"} // Built-in providers
+		providersToTest = []string{"openai", "gemini", "anthropic", "ollama"} // Built-in providers
 	} else if len(opts.Providers) > 0 {
 		providersToTest = opts.Providers
 	} else if opts.Provider != "" {
@@ -462,44 +478,98 @@ func runProvidersInstall(ctx context.Context, opts *ProvidersOptions, cfg *confi
 		operation = "uninstall"
 	}
 
-	log.WithOperation("providers-"+operation).WithProvider(opts.Provider).Info(fmt.Sprintf("Provider %s", operation))
+	dir := pluginDir(cfg)
+
+	if operation == "uninstall" {
+		log.WithOperation("providers-uninstall").WithProvider(opts.Provider).Info("Provider uninstall")
+		if err := providers.UninstallPlugin(dir, opts.Provider); err != nil {
+			if opts.Force {
+				log.WithProvider(opts.Provider).WithError(err).Warning("Provider plugin not removed")
+				return nil
+			}
+			return fmt.Errorf("failed to uninstall provider: %w", err)
+		}
+		log.WithProvider(opts.Provider).Success("Provider plugin uninstalled")
+		return nil
+	}
 
-	// Placeholder implementation
-	log.WithProvider(opts.Provider).Warning(fmt.Sprintf("Provider %s not yet implemented", operation))
+	log.WithOperation("providers-install").Info(fmt.Sprintf("Provider install from %s", opts.PluginCommand))
+	manifest, err := providers.InstallPlugin(ctx, dir, opts.PluginName, opts.PluginCommand, opts.PluginArgs, opts.Force)
+	if err != nil {
+		return fmt.Errorf("failed to install provider: %w", err)
+	}
 
+	log.WithProvider(manifest.Name).Success(fmt.Sprintf("Provider plugin installed with models: %s", strings.Join(manifest.Models, ", ")))
 	return nil
 }
 
+// pluginDir returns the directory of installed provider plugins, which is
+// where plugins are registered from at startup.
+func pluginDir(cfg *config.GlobalConfig) string {
+	return providers.PluginDir(cfg.ConfigDir)
+}
+
 // getProviderInfo retrieves information about available providers
 func getProviderInfo(opts *ProvidersOptions, cfg *config.GlobalConfig) ([]ProviderInfo, error) {
+	// Invalid plugin manifests are reported when plugins are registered at
+	// startup
+	manifests, _ := providers.LoadPluginManifests(pluginDir(cfg))
+
 	// Built-in providers - in a full implementation, this would query the actual provider registry
 	providers := []ProviderInfo{
 		{
 			Name:      "openai",
 			Available: cfg.HasAPIKey("openai"),
-			Models:    []string{"gpt-4", "gpt-3.5-turbo", "gpt-4-turbo"},
+			Models:    catalogModelIDs("openai"),
 			Aliases:   map[string]string{"gpt-4": "gpt-4", "gpt-3.5": "gpt-3.5-turbo"},
 			Status:    "ready",
 		},
 		{
 			Name:      "gemini",
 			Available: cfg.HasAPIKey("gemini"),
-			Models:    []string{"gemini-pro", "gemini-pro-vision"},
+			Models:    catalogModelIDs("gemini"),
 			Aliases:   map[string]string{"gemini": "gemini-pro"},
 			Status:    "ready",
 		},
+		{
+			Name:      "anthropic",
+			Available: cfg.HasAPIKey("anthropic"),
+			Models:    catalogModelIDs("anthropic"),
+			Aliases:   map[string]string{"claude": "claude-sonnet-4-5"},
+			Status:    "ready",
+		},
 		{
 			Name:      "ollama",
 			Available: cfg.OllamaEndpoint != "",
-			Models:    []string{"llama2", "codellama", "mistral"},
+			Models:    catalogModelIDs("ollama"),
 			Aliases:   map[string]string{"llama": "llama2"},
 			Status:    "ready",
 		},
 	}
 
+	// Installed provider plugins
+	for _, manifest := range manifests {
+		providers = append(providers, ProviderInfo{
+			Name:      manifest.Name,
+			Available: true,
+			Models:    manifest.Models,
+			Config:    map[string]interface{}{"command": manifest.Command, "version": manifest.Version},
+			Status:    "plugin",
+		})
+	}
+
 	return providers, nil
 }
 
+// catalogModelIDs returns the IDs of a provider's models in the model catalog.
+func catalogModelIDs(provider string) []string {
+	var ids []string
+	for _, model := range providers.DefaultCatalog().Models(provider) {
+		ids = append(ids, model.ID)
+	}
+	return ids
+}
+
 // testProvider tests a specific provider
 func testProvider(ctx context.Context, providerName string, opts *ProvidersOptions, 
 	cfg *config.GlobalConfig, log *logger.Logger) (ProviderInfo, error) {
@@ -528,14 +598,19 @@ func testProvider(ctx context.Context, providerName string, opts *ProvidersOptio
 		if !success {
 			testError = fmt.Errorf("Gemini API key not configured")
 		}
+	case "anthropic":
+		success = cfg.HasAPIKey("anthropic")
+		if !success {
+			testError = fmt.Errorf("Anthropic API key not configured")
+		}
 	case "ollama":
 		success = cfg.OllamaEndpoint != ""
 		if !success {
 			testError = fmt.Errorf("Ollama endpoint not configured")
 		}
 	default:
-		success = false
-		testError = fmt.Errorf("unknown provider")
+		testError = testPlugin(ctx, providerName, opts, cfg)
+		success = testError == nil
 	}
 
 	// Create test result
@@ -559,6 +634,41 @@ func testProvider(ctx context.Context, providerName string, opts *ProvidersOptio
 	return info, nil
 }
 
+// testPlugin checks the health of an installed provider plugin.
+func testPlugin(ctx context.Context, name string, opts *ProvidersOptions, cfg *config.GlobalConfig) error {
+	manifests, err := providers.LoadPluginManifests(pluginDir(cfg))
+	if err != nil {
+		return err
+	}
+	for _, manifest := range manifests {
+		if manifest.Name != name {
+			continue
+		}
+
+		modelID := opts.TestModel
+		if modelID == "" && len(manifest.Models) > 0 {
+			modelID = manifest.Models[0]
+		}
+		plugin, err := providers.NewPluginProvider(manifest, providers.NewModelConfig(modelID))
+		if err != nil {
+			return err
+		}
+		defer plugin.Close()
+
+		ctx, cancel := context.WithTimeout(ctx, time.Duration(opts.TestTimeout)*time.Second)
+		defer cancel()
+		health, err := plugin.Health(ctx)
+		if err != nil {
+			return err
+		}
+		if !health.OK {
+			return fmt.Errorf("plugin is not healthy: %s", health.Message)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown provider")
+}
+
 // formatProvidersOutput formats provider information for output
 func formatProvidersOutput(providers []ProviderInfo, opts *ProvidersOptions) (string, error) {
 	switch opts.Format {
@@ -605,9 +715,63 @@ func formatProvidersTable(providers []ProviderInfo, opts *ProvidersOptions) (str
 		}
 	}
 
+	if opts.ShowModels {
+		names := make([]string, len(providers))
+		for i, p := range providers {
+			names[i] = p.Name
+		}
+		formatModelCatalogTable(&output, names)
+	}
+
 	return output.String(), nil
 }
 
+// formatModelCatalogTable writes the model catalog entries of the given
+// providers as a table, with prices in USD per million tokens.
+func formatModelCatalogTable(output *strings.Builder, providerNames []string) {
+	catalog := providers.DefaultCatalog()
+	models := catalog.Models(providerNames...)
+	if len(models) == 0 {
+		return
+	}
+
+	output.WriteString(fmt.Sprintf("\nModel catalog (updated %s)\n", catalog.Updated()))
+	output.WriteString("MODEL\tPROVIDER\tCONTEXT\tMAX OUTPUT\tJSON\tSCHEMA\tSTREAMING\tINPUT $/1M\tOUTPUT $/1M\n")
+	output.WriteString("-----\t--------\t-------\t----------\t----\t------\t---------\t----------\t-----------\n")
+
+	for _, model := range models {
+		output.WriteString(fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			model.ID, model.Provider,
+			formatTokenCount(model.ContextWindow), formatTokenCount(model.MaxOutputTokens),
+			yesNo(model.JSONMode), yesNo(model.Schema), yesNo(model.Streaming),
+			formatPrice(model.InputPrice), formatPrice(model.OutputPrice)))
+	}
+}
+
+// formatTokenCount formats a token count, or "-" if it is unknown.
+func formatTokenCount(tokens int) string {
+	if tokens <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", tokens)
+}
+
+// formatPrice formats a price in USD, or "free" if it is zero.
+func formatPrice(price float64) string {
+	if price == 0 {
+		return "free"
+	}
+	return fmt.Sprintf("%.3f", price)
+}
+
+// yesNo formats a boolean as "Yes" or "No".
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
+
 // formatProvidersJSON formats providers as JSON
 func formatProvidersJSON(providers []ProviderInfo) (string, error) {
 	// Placeholder JSON formatting
@@ -673,7 +837,7 @@ func formatTestResultsYAML(results []ProviderInfo) (string, error) {
 
 // isValidProvider checks if a provider name is valid
 func isValidProvider(provider string) bool {
-	validProviders := []string{"openai", "gemini", "ollama"}
+	validProviders := []string{"openai", "gemini", "anthropic", "ollama"}
 	for _, p := range validProviders {
 		if p == provider {
 			return true
@@ -692,6 +856,8 @@ func showProviderConfig(provider string, cfg *config.GlobalConfig, opts *Provide
 		fmt.Printf("  API Key: %s\n", maskAPIKey(cfg.OpenAIAPIKey))
 	case "gemini":
 		fmt.Printf("  API Key: %s\n", maskAPIKey(cfg.GeminiAPIKey))
+	case "anthropic":
+		fmt.Printf("  API Key: %s\n", maskAPIKey(cfg.AnthropicAPIKey))
 	case "ollama":
 		fmt.Printf("  Endpoint: %s\n", cfg.OllamaEndpoint)
 	}
@@ -3,11 +3,13 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 
 	"github.com/sehwan505/langextract-go/cmd/langextract/internal/config"
 	"github.com/sehwan505/langextract-go/cmd/langextract/internal/logger"
+	"github.com/sehwan505/langextract-go/pkg/providers"
 )
 
 // VersionInfo contains build-time version information
@@ -48,6 +50,16 @@ Examples:
 			if err := applyGlobalFlags(cmd, cfg, log); err != nil {
 				return fmt.Errorf("failed to apply global flags: %w", err)
 			}
+
+			// Load the user's model catalog, which extends the built-in one
+			if err := providers.LoadCatalogFile(filepath.Join(cfg.ConfigDir, "models.yaml")); err != nil {
+				log.WithError(err).Warning("Failed to load model catalog")
+			}
+
+			// Register installed provider plugins
+			if err := providers.RegisterPlugins(providers.PluginDir(cfg.ConfigDir)); err != nil {
+				log.WithError(err).Warning("Failed to load provider plugins")
+			}
 			return nil
 		},
 	}
@@ -80,9 +92,10 @@ func addGlobalFlags(cmd *cobra.Command, cfg *config.GlobalConfig) {
 	flags.String("log-format", cfg.LogFormat, "Set log format (text, json)")
 
 	// Provider flags  
-	flags.String("provider", cfg.DefaultProvider, "Set default provider (openai, gemini, ollama)")
+	flags.String("provider", cfg.DefaultProvider, "Set default provider (openai, gemini, anthropic, ollama)")
 	flags.String("openai-api-key", "", "OpenAI API key (or LANGEXTRACT_OPENAI_API_KEY)")
 	flags.String("gemini-api-key", "", "Gemini API key (or LANGEXTRACT_GEMINI_API_KEY)")
+	flags.String("anthropic-api-key", "", "Anthropic API key (or LANGEXTRACT_ANTHROPIC_API_KEY)")
 	flags.String("ollama-endpoint", cfg.OllamaEndpoint, "Ollama endpoint URL")
 
 	// Request configuration flags
@@ -146,6 +159,11 @@ func applyGlobalFlags(cmd *cobra.Command, cfg *config.GlobalConfig, log *logger.
 			cfg.GeminiAPIKey = key
 		}
 	}
+	if flags.Changed("anthropic-api-key") {
+		if key, err := flags.GetString("anthropic-api-key"); err == nil && key != "" {
+			cfg.AnthropicAPIKey = key
+		}
+	}
 	if flags.Changed("ollama-endpoint") {
 		if endpoint, err := flags.GetString("ollama-endpoint"); err == nil {
 			cfg.OllamaEndpoint = endpoint
@@ -21,6 +21,7 @@ type GlobalConfig struct {
 	// API configuration
 	OpenAIAPIKey    string `mapstructure:"openai_api_key" json:"-"`
 	GeminiAPIKey    string `mapstructure:"gemini_api_key" json:"-"`
+	AnthropicAPIKey string `mapstructure:"anthropic_api_key" json:"-"`
 	OllamaEndpoint  string `mapstructure:"ollama_endpoint" json:"ollama_endpoint"`
 	
 	// Request configuration
@@ -178,7 +179,7 @@ func (c *GlobalConfig) Validate() error {
 	}
 	
 	// Validate provider
-	validProviders := []string{"openai", "gemini", "ollama"}
+	validProviders := []string{"openai", "gemini", "anthropic", "ollama"}
 	if !contains(validProviders, c.DefaultProvider) {
 		return fmt.Errorf("invalid default_provider '%s', must be one of: %v", c.DefaultProvider, validProviders)
 	}
@@ -222,6 +223,8 @@ func (c *GlobalConfig) GetAPIKey(provider string) string {
 		return c.OpenAIAPIKey
 	case "gemini":
 		return c.GeminiAPIKey
+	case "anthropic":
+		return c.AnthropicAPIKey
 	default:
 		return ""
 	}
@@ -241,12 +244,13 @@ func CreateConfigTemplate(path string) error {
 log_level: info          # debug, info, warn, error
 log_format: text         # text, json
 
-# Default provider (openai, gemini, ollama)
+# Default provider (openai, gemini, anthropic, ollama)
 default_provider: openai
 
 # API Keys (can also be set via environment variables)
 # openai_api_key: sk-...
 # gemini_api_key: ...
+# anthropic_api_key: sk-ant-...
 ollama_endpoint: http://localhost:11434
 
 # Request configuration
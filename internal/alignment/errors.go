@@ -75,25 +75,45 @@ func NewAlignmentErrorWithCause(errorType, message string, cause error) *Alignme
 	}
 }
 
-// WithPosition adds position information to the error.
+// Is reports whether target is an alignment error of the same type and message,
+// so that errors derived from the common errors below match them with errors.Is.
+func (e *AlignmentError) Is(target error) bool {
+	t, ok := target.(*AlignmentError)
+	if !ok {
+		return false
+	}
+	return e.Type == t.Type && e.Message == t.Message
+}
+
+// WithPosition returns a copy of the error with position information.
 func (e *AlignmentError) WithPosition(pos int) *AlignmentError {
-	e.Position = pos
-	return e
+	c := e.clone()
+	c.Position = pos
+	return c
 }
 
-// WithMethod adds the alignment method information to the error.
+// WithMethod returns a copy of the error with the alignment method information.
 func (e *AlignmentError) WithMethod(method string) *AlignmentError {
-	e.Method = method
-	return e
+	c := e.clone()
+	c.Method = method
+	return c
 }
 
-// WithDetail adds a detail key-value pair to the error.
+// WithDetail returns a copy of the error with an additional detail key-value pair.
 func (e *AlignmentError) WithDetail(key string, value interface{}) *AlignmentError {
-	if e.Details == nil {
-		e.Details = make(map[string]interface{})
+	c := e.clone()
+	c.Details[key] = value
+	return c
+}
+
+// clone copies the error so the shared common errors are never mutated.
+func (e *AlignmentError) clone() *AlignmentError {
+	c := *e
+	c.Details = make(map[string]interface{}, len(e.Details)+1)
+	for k, v := range e.Details {
+		c.Details[k] = v
 	}
-	e.Details[key] = value
-	return e
+	return &c
 }
 
 // Common error types
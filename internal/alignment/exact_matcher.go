@@ -48,7 +48,7 @@ func (em *ExactMatcher) AlignExtraction(ctx context.Context, extracted, source s
 	
 	// Normalize texts based on options
 	normalizedExtracted := em.normalizeText(extracted, opts)
-	normalizedSource := em.normalizeText(source, opts)
+	normalizedSource := normalizeWithOffsets(source, opts)
 	
 	// Find all exact matches
 	matches := em.findExactMatches(normalizedExtracted, normalizedSource, extracted, source, opts)
@@ -192,34 +192,11 @@ func (em *ExactMatcher) ValidateAlignment(extracted, source string, interval *ty
 
 // normalizeText applies normalization based on alignment options.
 func (em *ExactMatcher) normalizeText(text string, opts AlignmentOptions) string {
-	normalized := text
-	
-	if !opts.CaseSensitive {
-		normalized = strings.ToLower(normalized)
-	}
-	
-	if opts.IgnoreWhitespace {
-		// Normalize whitespace (replace multiple spaces with single space, trim)
-		normalized = strings.TrimSpace(normalized)
-		normalized = strings.Join(strings.Fields(normalized), " ")
-	}
-	
-	if opts.IgnorePunctuation {
-		// Remove common punctuation marks
-		punctuation := ".,!?;:()[]{}\"'-"
-		for _, p := range punctuation {
-			normalized = strings.ReplaceAll(normalized, string(p), "")
-		}
-		// Clean up any extra spaces created by punctuation removal
-		normalized = strings.TrimSpace(normalized)
-		normalized = strings.Join(strings.Fields(normalized), " ")
-	}
-	
-	return normalized
+	return normalizeWithOffsets(text, opts).text
 }
 
 // findExactMatches finds all exact matches in the source text.
-func (em *ExactMatcher) findExactMatches(normalizedExtracted, normalizedSource, originalExtracted, originalSource string, opts AlignmentOptions) []AlignmentCandidate {
+func (em *ExactMatcher) findExactMatches(normalizedExtracted string, normalizedSource normalizedText, originalExtracted, originalSource string, opts AlignmentOptions) []AlignmentCandidate {
 	var candidates []AlignmentCandidate
 	
 	if normalizedExtracted == "" {
@@ -228,7 +205,7 @@ func (em *ExactMatcher) findExactMatches(normalizedExtracted, normalizedSource,
 	
 	// Find all occurrences of the normalized extracted text
 	searchText := normalizedExtracted
-	sourceText := normalizedSource
+	sourceText := normalizedSource.text
 	
 	start := 0
 	for {
@@ -240,8 +217,8 @@ func (em *ExactMatcher) findExactMatches(normalizedExtracted, normalizedSource,
 		actualPos := start + pos
 		
 		// Map back to original text position
-		originalPos := em.mapNormalizedToOriginal(actualPos, normalizedSource, originalSource, opts)
-		originalLength := em.calculateOriginalLength(actualPos, len(searchText), normalizedSource, originalSource, opts)
+		originalPos, originalEnd := normalizedSource.originalSpan(actualPos, len(searchText))
+		originalLength := originalEnd - originalPos
 		
 		// Validate the match in the original text
 		if originalPos >= 0 && originalPos+originalLength <= len(originalSource) {
@@ -334,66 +311,3 @@ func (em *ExactMatcher) calculateExactMatchScore(extracted, matched string, opts
 	
 	return score
 }
-
-// mapNormalizedToOriginal maps a position in normalized text to original text.
-func (em *ExactMatcher) mapNormalizedToOriginal(normalizedPos int, normalizedText, originalText string, opts AlignmentOptions) int {
-	// Simple implementation: assume character-by-character mapping with adjustments
-	// This is a simplified approach; a more sophisticated implementation would track
-	// the exact mapping during normalization
-	
-	if normalizedPos == 0 {
-		return 0
-	}
-	
-	if normalizedPos >= len(normalizedText) {
-		return len(originalText)
-	}
-	
-	// For case-only changes, position mapping is direct
-	if !opts.IgnoreWhitespace && !opts.IgnorePunctuation {
-		if normalizedPos < len(originalText) {
-			return normalizedPos
-		}
-		return len(originalText) - 1
-	}
-	
-	// More complex mapping needed for whitespace/punctuation changes
-	// This is a simplified approximation
-	ratio := float64(normalizedPos) / float64(len(normalizedText))
-	originalPos := int(ratio * float64(len(originalText)))
-	
-	if originalPos >= len(originalText) {
-		originalPos = len(originalText) - 1
-	}
-	
-	return originalPos
-}
-
-// calculateOriginalLength calculates the length in original text corresponding to normalized length.
-func (em *ExactMatcher) calculateOriginalLength(normalizedPos, normalizedLength int, normalizedText, originalText string, opts AlignmentOptions) int {
-	// Simple implementation: estimate based on text length ratios
-	if normalizedLength == 0 {
-		return 0
-	}
-	
-	// For case-only changes, length is preserved
-	if !opts.IgnoreWhitespace && !opts.IgnorePunctuation {
-		return normalizedLength
-	}
-	
-	// Estimate based on text compression ratio
-	if len(normalizedText) == 0 {
-		return normalizedLength
-	}
-	
-	ratio := float64(len(originalText)) / float64(len(normalizedText))
-	originalLength := int(float64(normalizedLength) * ratio)
-	
-	// Ensure we don't exceed bounds
-	startPos := em.mapNormalizedToOriginal(normalizedPos, normalizedText, originalText, opts)
-	if startPos+originalLength > len(originalText) {
-		originalLength = len(originalText) - startPos
-	}
-	
-	return originalLength
-}
\ No newline at end of file
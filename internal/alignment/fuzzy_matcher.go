@@ -3,7 +3,6 @@ package alignment
 import (
 	"context"
 	"math"
-	"strings"
 	"time"
 
 	"github.com/sehwan505/langextract-go/pkg/types"
@@ -65,7 +64,7 @@ func (fm *FuzzyMatcher) AlignExtraction(ctx context.Context, extracted, source s
 	
 	// Normalize texts based on options
 	normalizedExtracted := fm.normalizeText(extracted, opts)
-	normalizedSource := fm.normalizeText(source, opts)
+	normalizedSource := normalizeWithOffsets(source, opts)
 	
 	// Find fuzzy matches
 	matches := fm.findFuzzyMatches(normalizedExtracted, normalizedSource, extracted, source, maxDistance, opts)
@@ -258,31 +257,11 @@ func (fm *FuzzyMatcher) ValidateAlignment(extracted, source string, interval *ty
 
 // normalizeText applies normalization for fuzzy matching.
 func (fm *FuzzyMatcher) normalizeText(text string, opts AlignmentOptions) string {
-	normalized := text
-	
-	if !opts.CaseSensitive {
-		normalized = strings.ToLower(normalized)
-	}
-	
-	if opts.IgnoreWhitespace {
-		normalized = strings.TrimSpace(normalized)
-		normalized = strings.Join(strings.Fields(normalized), " ")
-	}
-	
-	if opts.IgnorePunctuation {
-		punctuation := ".,!?;:()[]{}\"'-"
-		for _, p := range punctuation {
-			normalized = strings.ReplaceAll(normalized, string(p), "")
-		}
-		normalized = strings.TrimSpace(normalized)
-		normalized = strings.Join(strings.Fields(normalized), " ")
-	}
-	
-	return normalized
+	return normalizeWithOffsets(text, opts).text
 }
 
 // findFuzzyMatches finds all fuzzy matches within the specified edit distance.
-func (fm *FuzzyMatcher) findFuzzyMatches(normalizedExtracted, normalizedSource, originalExtracted, originalSource string, maxDistance int, opts AlignmentOptions) []AlignmentCandidate {
+func (fm *FuzzyMatcher) findFuzzyMatches(normalizedExtracted string, normalizedSource normalizedText, originalExtracted, originalSource string, maxDistance int, opts AlignmentOptions) []AlignmentCandidate {
 	var candidates []AlignmentCandidate
 	
 	if normalizedExtracted == "" {
@@ -290,7 +269,7 @@ func (fm *FuzzyMatcher) findFuzzyMatches(normalizedExtracted, normalizedSource,
 	}
 	
 	extractedLen := len(normalizedExtracted)
-	sourceLen := len(normalizedSource)
+	sourceLen := len(normalizedSource.text)
 	
 	// Sliding window approach for fuzzy matching
 	windowSizes := fm.getWindowSizes(extractedLen, maxDistance)
@@ -306,13 +285,12 @@ func (fm *FuzzyMatcher) findFuzzyMatches(normalizedExtracted, normalizedSource,
 				}
 			}
 			
-			window := normalizedSource[start : start+windowSize]
+			window := normalizedSource.text[start : start+windowSize]
 			editDistance := fm.calculateEditDistance(normalizedExtracted, window)
 			
 			if editDistance <= maxDistance {
 				// Map back to original positions
-				originalStart := fm.mapNormalizedToOriginal(start, normalizedSource, originalSource, opts)
-				originalEnd := fm.mapNormalizedToOriginal(start+windowSize, normalizedSource, originalSource, opts)
+				originalStart, originalEnd := normalizedSource.originalSpan(start, windowSize)
 				originalLength := originalEnd - originalStart
 				
 				if originalStart >= 0 && originalEnd <= len(originalSource) && originalLength > 0 {
@@ -493,27 +471,6 @@ func (fm *FuzzyMatcher) getAlignmentStatus(editDistance, textLength int) types.A
 	}
 }
 
-// mapNormalizedToOriginal maps normalized position to original text position.
-func (fm *FuzzyMatcher) mapNormalizedToOriginal(normalizedPos int, normalizedText, originalText string, opts AlignmentOptions) int {
-	if normalizedPos == 0 {
-		return 0
-	}
-	
-	if normalizedPos >= len(normalizedText) {
-		return len(originalText)
-	}
-	
-	// Simple ratio-based mapping
-	ratio := float64(normalizedPos) / float64(len(normalizedText))
-	originalPos := int(ratio * float64(len(originalText)))
-	
-	if originalPos >= len(originalText) {
-		originalPos = len(originalText) - 1
-	}
-	
-	return originalPos
-}
-
 // min returns the minimum of three integers.
 func min(a, b, c int) int {
 	if a < b {
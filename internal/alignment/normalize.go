@@ -0,0 +1,87 @@
+package alignment
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ignoredPunctuation lists the characters removed when IgnorePunctuation is set.
+const ignoredPunctuation = ".,!?;:()[]{}\"'-"
+
+// normalizedText is a normalized view of a string that remembers which span of
+// the original string produced each normalized byte.
+type normalizedText struct {
+	text   string
+	starts []int // original start offset for each normalized byte
+	ends   []int // original end offset for each normalized byte
+}
+
+// normalizeWithOffsets applies case, whitespace and punctuation normalization
+// according to opts while tracking offsets back into the original text.
+func normalizeWithOffsets(text string, opts AlignmentOptions) normalizedText {
+	collapseSpace := opts.IgnoreWhitespace || opts.IgnorePunctuation
+
+	var builder strings.Builder
+	starts := make([]int, 0, len(text))
+	ends := make([]int, 0, len(text))
+
+	emit := func(s string, start, end int) {
+		builder.WriteString(s)
+		for i := 0; i < len(s); i++ {
+			starts = append(starts, start)
+			ends = append(ends, end)
+		}
+	}
+
+	pendingSpace := -1
+	pendingSpaceEnd := -1
+
+	for pos, r := range text {
+		size := utf8.RuneLen(r)
+		if size < 0 {
+			size = 1
+		}
+		end := pos + size
+
+		if opts.IgnorePunctuation && strings.ContainsRune(ignoredPunctuation, r) {
+			continue
+		}
+
+		if collapseSpace && unicode.IsSpace(r) {
+			// Remember the first whitespace character of a run; it is emitted
+			// as a single space only if more text follows.
+			if pendingSpace < 0 && builder.Len() > 0 {
+				pendingSpace = pos
+				pendingSpaceEnd = end
+			}
+			continue
+		}
+
+		if pendingSpace >= 0 {
+			emit(" ", pendingSpace, pendingSpaceEnd)
+			pendingSpace = -1
+		}
+
+		out := string(r)
+		if !opts.CaseSensitive {
+			out = strings.ToLower(out)
+		}
+		emit(out, pos, end)
+	}
+
+	return normalizedText{
+		text:   builder.String(),
+		starts: starts,
+		ends:   ends,
+	}
+}
+
+// originalSpan maps a normalized byte range to the corresponding range in the
+// original text.
+func (n normalizedText) originalSpan(pos, length int) (int, int) {
+	if length <= 0 || pos < 0 || pos+length > len(n.starts) {
+		return -1, -1
+	}
+	return n.starts[pos], n.ends[pos+length-1]
+}
@@ -241,26 +241,12 @@ func (ac *AdaptiveChunker) createInitialSegments(text string, opts ChunkingOptio
 	var segments []TextSegment
 	
 	// Use paragraph boundaries as initial segment boundaries
-	paragraphs := ac.paragraphPattern.Split(text, -1)
-	currentPos := 0
-	
-	for _, paragraph := range paragraphs {
-		paragraph = strings.TrimSpace(paragraph)
-		if paragraph == "" {
-			continue
-		}
-		
-		// Find the actual position of this paragraph in the original text
-		segmentStart := strings.Index(text[currentPos:], paragraph) + currentPos
-		segmentEnd := segmentStart + len(paragraph)
-		
+	for _, span := range splitSpans(text, ac.paragraphPattern) {
 		segments = append(segments, TextSegment{
-			Text:     paragraph,
-			StartPos: segmentStart,
-			EndPos:   segmentEnd,
+			Text:     text[span.start:span.end],
+			StartPos: span.start,
+			EndPos:   span.end,
 		})
-		
-		currentPos = segmentEnd
 	}
 	
 	// If no paragraphs found, treat entire text as one segment
@@ -306,13 +292,11 @@ func (ac *AdaptiveChunker) createChunksFromSegment(ctx context.Context, segment
 		return chunks, nil
 	}
 	
-	// Split segment into multiple chunks
-	sentences := ac.findSentences(segment.Text)
-	currentChunk := ""
-	currentStart := segment.StartPos
+	// Split segment into multiple chunks at sentence boundaries
+	sentences := splitSpans(segment.Text, ac.sentencePattern)
 	chunkIndex := startIndex
 	
-	for _, sentence := range sentences {
+	for _, span := range packSpans(sentences, targetSize) {
 		// Check for context cancellation
 		select {
 		case <-ctx.Done():
@@ -320,34 +304,9 @@ func (ac *AdaptiveChunker) createChunksFromSegment(ctx context.Context, segment
 		default:
 		}
 		
-		sentence = strings.TrimSpace(sentence)
-		if sentence == "" {
-			continue
-		}
-		
-		// If adding this sentence would exceed target size, finalize current chunk
-		if len(currentChunk)+len(sentence) > targetSize && currentChunk != "" {
-			chunk := ac.createAdaptiveChunk(currentChunk, currentStart, chunkIndex, opts)
-			chunks = append(chunks, chunk)
-			
-			// Start new chunk
-			currentChunk = sentence
-			currentStart = strings.Index(segment.Text[currentStart:], sentence) + segment.StartPos
-			chunkIndex++
-		} else {
-			if currentChunk != "" {
-				currentChunk += " " + sentence
-			} else {
-				currentChunk = sentence
-				currentStart = strings.Index(segment.Text, sentence) + segment.StartPos
-			}
-		}
-	}
-	
-	// Add final chunk if any content remains
-	if currentChunk != "" {
-		chunk := ac.createAdaptiveChunk(currentChunk, currentStart, chunkIndex, opts)
+		chunk := ac.createAdaptiveChunk(segment.Text[span.start:span.end], segment.StartPos+span.start, chunkIndex, opts)
 		chunks = append(chunks, chunk)
+		chunkIndex++
 	}
 	
 	return chunks, nil
@@ -410,19 +369,21 @@ func (ac *AdaptiveChunker) applyAdaptiveOverlap(chunks []TextChunk, text string,
 			overlapStart = chunks[i-1].CharInterval.StartPos
 		}
 		
-		// Try to align with sentence boundary for better context
+		// Try to align with sentence boundary for better context. The first
+		// boundary in the window is used; the last one is usually the end of
+		// the previous chunk itself.
 		overlapText := text[overlapStart:chunks[i].CharInterval.StartPos]
-		sentenceBoundary := strings.LastIndex(overlapText, ". ")
+		sentenceBoundary := strings.Index(overlapText, ". ")
 		if sentenceBoundary > 0 {
 			overlapStart += sentenceBoundary + 2
 		}
 		
 		// Update chunk
 		newText := text[overlapStart:chunks[i].CharInterval.EndPos]
-		chunks[i].Text = newText
-		chunks[i].CharInterval.StartPos = overlapStart
 		chunks[i].Metadata.HasOverlap = true
 		chunks[i].Metadata.OverlapStart = chunks[i].CharInterval.StartPos - overlapStart
+		chunks[i].Text = newText
+		chunks[i].CharInterval.StartPos = overlapStart
 		chunks[i].Metadata.Properties["adaptive_overlap_ratio"] = adaptiveOverlapRatio
 	}
 	
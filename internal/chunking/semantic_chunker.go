@@ -155,8 +155,8 @@ func (sc *SemanticChunker) analyzeTextStructure(text string) *TextStructureAnaly
 	lines := strings.Split(text, "\n")
 	currentPos := 0
 	
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+	for _, rawLine := range lines {
+		line := strings.TrimSpace(rawLine)
 		
 		// Check for various structural patterns
 		for _, pattern := range sc.topicPatterns {
@@ -172,7 +172,7 @@ func (sc *SemanticChunker) analyzeTextStructure(text string) *TextStructureAnaly
 			}
 		}
 		
-		currentPos += len(line) + 1 // +1 for newline
+		currentPos += len(rawLine) + 1 // +1 for newline
 	}
 
 	// Analyze keyword density for better semantic understanding
@@ -197,21 +197,21 @@ func (sc *SemanticChunker) createSemanticChunks(ctx context.Context, text string
 		default:
 		}
 		
-		start := splitPoints[i]
-		end := splitPoints[i+1]
+		span := trimSpan(text, splitPoints[i], splitPoints[i+1])
+		if span.length() == 0 {
+			continue
+		}
 		
-		chunkText := strings.TrimSpace(text[start:end])
-		if len(chunkText) < opts.MinChunkSize && len(chunks) > 0 {
+		if span.length() < opts.MinChunkSize && len(chunks) > 0 {
 			// Merge small chunks with the previous chunk
-			chunks[len(chunks)-1].Text += " " + chunkText
-			chunks[len(chunks)-1].CharInterval.EndPos = end
+			previous := &chunks[len(chunks)-1]
+			previous.CharInterval.EndPos = span.end
+			previous.Text = text[previous.CharInterval.StartPos:span.end]
 			continue
 		}
 		
-		if chunkText != "" {
-			chunk := sc.createSemanticChunk(chunkText, start, len(chunks), analysis, opts)
-			chunks = append(chunks, chunk)
-		}
+		chunk := sc.createSemanticChunk(text[span.start:span.end], span.start, len(chunks), analysis, opts)
+		chunks = append(chunks, chunk)
 	}
 	
 	return chunks, nil
@@ -328,19 +328,20 @@ func (sc *SemanticChunker) applySemanticOverlap(chunks []TextChunk, text string,
 			overlapStart = chunks[i-1].CharInterval.StartPos
 		}
 		
-		// Try to align with sentence boundary
+		// Try to align with sentence boundary. The first boundary in the window is
+		// used; the last one is usually the end of the previous chunk itself.
 		overlapText := text[overlapStart:chunks[i].CharInterval.StartPos]
-		sentenceBoundary := strings.LastIndex(overlapText, ". ")
+		sentenceBoundary := strings.Index(overlapText, ". ")
 		if sentenceBoundary > 0 {
 			overlapStart += sentenceBoundary + 2
 		}
 		
 		// Update chunk to include overlap
 		newText := text[overlapStart:chunks[i].CharInterval.EndPos]
-		chunks[i].Text = newText
-		chunks[i].CharInterval.StartPos = overlapStart
 		chunks[i].Metadata.HasOverlap = true
 		chunks[i].Metadata.OverlapStart = chunks[i].CharInterval.StartPos - overlapStart
+		chunks[i].Text = newText
+		chunks[i].CharInterval.StartPos = overlapStart
 	}
 	
 	return chunks
@@ -436,8 +437,8 @@ func (sc *SemanticChunker) calculateStructuralComplexity(text string) float64 {
 	lines := strings.Split(text, "\n")
 	structuralElements := 0
 	
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+	for _, rawLine := range lines {
+		line := strings.TrimSpace(rawLine)
 		// Count various structural elements
 		if regexp.MustCompile(`^\d+[\.\)]\s+`).MatchString(line) ||
 			regexp.MustCompile(`^[•\-\*]\s+`).MatchString(line) ||
@@ -103,112 +103,32 @@ func (sc *SimpleChunker) EstimateChunks(text string, opts ChunkingOptions) int {
 
 // chunkByParagraphs splits text into chunks while preserving paragraph boundaries.
 func (sc *SimpleChunker) chunkByParagraphs(ctx context.Context, text string, opts ChunkingOptions) ([]TextChunk, error) {
-	paragraphs := sc.paragraphPattern.Split(text, -1)
-	var chunks []TextChunk
-	currentChunk := ""
-	startPos := 0
-	
-	for i, paragraph := range paragraphs {
-		// Check for context cancellation
-		select {
-		case <-ctx.Done():
-			return nil, NewChunkingErrorWithCause(ErrorTypeTimeout, "chunking cancelled", ctx.Err())
-		default:
-		}
-		
-		paragraph = strings.TrimSpace(paragraph)
-		if paragraph == "" {
-			continue
-		}
-		
-		// If adding this paragraph would exceed the limit, finalize current chunk
-		if len(currentChunk)+len(paragraph) > opts.MaxCharBuffer && currentChunk != "" {
-			chunk := sc.createChunk(currentChunk, startPos, len(chunks), opts)
-			chunks = append(chunks, chunk)
-			
-			// Start new chunk
-			currentChunk = paragraph
-			startPos = strings.Index(text[startPos:], paragraph) + startPos
-		} else {
-			if currentChunk != "" {
-				currentChunk += "\n\n" + paragraph
-			} else {
-				currentChunk = paragraph
-				startPos = strings.Index(text, paragraph)
-			}
-		}
-		
-		// Handle last paragraph
-		if i == len(paragraphs)-1 && currentChunk != "" {
-			chunk := sc.createChunk(currentChunk, startPos, len(chunks), opts)
-			chunks = append(chunks, chunk)
-		}
-	}
-	
-	return chunks, nil
+	return sc.chunkBySpans(ctx, text, splitSpans(text, sc.paragraphPattern), opts)
 }
 
 // chunkBySentences splits text into chunks while preserving sentence boundaries.
 func (sc *SimpleChunker) chunkBySentences(ctx context.Context, text string, opts ChunkingOptions) ([]TextChunk, error) {
-	sentences := sc.findSentences(text)
+	return sc.chunkBySpans(ctx, text, splitSpans(text, sc.sentencePattern), opts)
+}
+
+// chunkBySpans packs boundary-preserving spans into chunks of at most
+// MaxCharBuffer characters. Spans that alone exceed the limit become their
+// own chunk. Chunk text is taken verbatim from the source text.
+func (sc *SimpleChunker) chunkBySpans(ctx context.Context, text string, spans []textSpan, opts ChunkingOptions) ([]TextChunk, error) {
 	var chunks []TextChunk
-	currentChunk := ""
-	startPos := 0
-	
-	for i, sentence := range sentences {
+
+	for _, span := range packSpans(spans, opts.MaxCharBuffer) {
 		// Check for context cancellation
 		select {
 		case <-ctx.Done():
 			return nil, NewChunkingErrorWithCause(ErrorTypeTimeout, "chunking cancelled", ctx.Err())
 		default:
 		}
-		
-		sentence = strings.TrimSpace(sentence)
-		if sentence == "" {
-			continue
-		}
-		
-		// If this sentence alone exceeds the limit, create it as its own chunk
-		if len(sentence) > opts.MaxCharBuffer {
-			// First, finalize any existing chunk
-			if currentChunk != "" {
-				chunk := sc.createChunk(currentChunk, startPos, len(chunks), opts)
-				chunks = append(chunks, chunk)
-				currentChunk = ""
-			}
-			
-			// Create chunk for the oversized sentence
-			sentencePos := strings.Index(text[startPos:], sentence) + startPos
-			chunk := sc.createChunk(sentence, sentencePos, len(chunks), opts)
-			chunks = append(chunks, chunk)
-			startPos = sentencePos + len(sentence)
-			continue
-		}
-		
-		// If adding this sentence would exceed the limit, finalize current chunk
-		if len(currentChunk)+len(sentence) > opts.MaxCharBuffer && currentChunk != "" {
-			chunk := sc.createChunk(currentChunk, startPos, len(chunks), opts)
-			chunks = append(chunks, chunk)
-			
-			// Start new chunk with current sentence
-			currentChunk = sentence
-			startPos = strings.Index(text[startPos:], sentence) + startPos
-		} else {
-			if currentChunk != "" {
-				currentChunk += " " + sentence
-			} else {
-				currentChunk = sentence
-				startPos = strings.Index(text[startPos:], sentence) + startPos
-			}
-		}
-		
-		// Handle last sentence
-		if i == len(sentences)-1 && currentChunk != "" {
-			chunk := sc.createChunk(currentChunk, startPos, len(chunks), opts)
-			chunks = append(chunks, chunk)
-		}
+
+		chunk := sc.createChunk(text[span.start:span.end], span.start, len(chunks), opts)
+		chunks = append(chunks, chunk)
 	}
-	
+
 	return chunks, nil
 }
 
@@ -0,0 +1,80 @@
+package chunking
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+// textSpan is a half-open byte range [start, end) within a source text.
+// Chunkers build chunks from spans so that chunk text is always an exact
+// substring of the source and CharInterval positions stay accurate.
+type textSpan struct {
+	start int
+	end   int
+}
+
+// length returns the number of bytes covered by the span.
+func (s textSpan) length() int {
+	return s.end - s.start
+}
+
+// trimSpan shrinks the range [start, end) of text to exclude leading and
+// trailing whitespace.
+func trimSpan(text string, start, end int) textSpan {
+	for start < end {
+		r, size := utf8.DecodeRuneInString(text[start:end])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		start += size
+	}
+	for end > start {
+		r, size := utf8.DecodeLastRuneInString(text[start:end])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		end -= size
+	}
+	return textSpan{start: start, end: end}
+}
+
+// splitSpans splits text after every match of separator. The separator stays
+// with the preceding span and each span is trimmed of surrounding whitespace;
+// empty spans are dropped.
+func splitSpans(text string, separator *regexp.Regexp) []textSpan {
+	var spans []textSpan
+	start := 0
+
+	for _, match := range separator.FindAllStringIndex(text, -1) {
+		if span := trimSpan(text, start, match[1]); span.length() > 0 {
+			spans = append(spans, span)
+		}
+		start = match[1]
+	}
+
+	if span := trimSpan(text, start, len(text)); span.length() > 0 {
+		spans = append(spans, span)
+	}
+
+	return spans
+}
+
+// packSpans greedily merges consecutive spans into larger spans of at most
+// maxSize bytes. A single span larger than maxSize is kept on its own.
+func packSpans(spans []textSpan, maxSize int) []textSpan {
+	var packed []textSpan
+
+	for _, span := range spans {
+		if len(packed) > 0 {
+			last := &packed[len(packed)-1]
+			if span.end-last.start <= maxSize {
+				last.end = span.end
+				continue
+			}
+		}
+		packed = append(packed, span)
+	}
+
+	return packed
+}
@@ -0,0 +1,182 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// ErrBudgetExceeded is returned for requests that a Budget refuses because
+// they would exceed its limits.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// Spend aggregates the token usage and cost of provider requests. Cached
+// responses are counted separately and cost nothing.
+type Spend struct {
+	Requests         int     `json:"requests"`
+	CachedRequests   int     `json:"cached_requests,omitempty"`
+	PromptTokens     int     `json:"prompt_tokens,omitempty"`
+	CompletionTokens int     `json:"completion_tokens,omitempty"`
+	TokensUsed       int     `json:"tokens_used"`
+	CostUSD          float64 `json:"cost_usd"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd,omitempty"`
+}
+
+// Add adds the spend of a provider response.
+func (s *Spend) Add(response *CacheableResponse) {
+	if response.Cached {
+		s.CachedRequests++
+		return
+	}
+	s.Requests++
+	s.PromptTokens += response.PromptTokens
+	s.CompletionTokens += response.CompletionTokens
+	s.TokensUsed += response.TokensUsed
+	s.CostUSD += response.CostUSD
+	s.EstimatedCostUSD += response.EstimatedCostUSD
+}
+
+// BudgetLimits caps the spend of the requests sharing a Budget. Zero limits
+// are unlimited.
+type BudgetLimits struct {
+	// MaxCostUSD caps the cost in USD, estimated from the model catalog.
+	// Requests to models without pricing cost nothing.
+	MaxCostUSD float64 `json:"max_cost_usd,omitempty"`
+
+	// MaxTokens caps the prompt and completion tokens
+	MaxTokens int `json:"max_tokens,omitempty"`
+}
+
+// Validate checks that the limits are not negative.
+func (l BudgetLimits) Validate() error {
+	if l.MaxCostUSD < 0 {
+		return fmt.Errorf("max cost must not be negative")
+	}
+	if l.MaxTokens < 0 {
+		return fmt.Errorf("max tokens must not be negative")
+	}
+	return nil
+}
+
+// String formats the limits for error messages.
+func (l BudgetLimits) String() string {
+	switch {
+	case l.MaxCostUSD > 0 && l.MaxTokens > 0:
+		return fmt.Sprintf("$%.2f and %d tokens", l.MaxCostUSD, l.MaxTokens)
+	case l.MaxCostUSD > 0:
+		return fmt.Sprintf("$%.2f", l.MaxCostUSD)
+	case l.MaxTokens > 0:
+		return fmt.Sprintf("%d tokens", l.MaxTokens)
+	default:
+		return "unlimited"
+	}
+}
+
+// Budget tracks the spend of a run, such as a batch, across the extraction
+// requests that share it, and refuses requests once its limits are reached.
+//
+// Before a request is sent, its prompt tokens and their cost are estimated
+// and reserved. A request whose estimate does not fit the remaining budget
+// fails with ErrBudgetExceeded, and so do all later requests, so that a run
+// stops cleanly instead of starting work it cannot pay for. Once a response
+// arrives, the reservation is replaced by the reported usage. Completion
+// tokens are not known in advance, so requests in flight can overshoot the
+// limits by their output.
+type Budget struct {
+	limits BudgetLimits
+
+	mu             sync.Mutex
+	spent          Spend
+	reservedTokens int
+	reservedCost   float64
+	exceeded       bool
+}
+
+// NewBudget creates a budget with the given limits.
+func NewBudget(limits BudgetLimits) *Budget {
+	return &Budget{limits: limits}
+}
+
+// Limits returns the limits of the budget.
+func (b *Budget) Limits() BudgetLimits {
+	return b.limits
+}
+
+// Spent returns the spend of the requests completed within the budget.
+func (b *Budget) Spent() Spend {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spent
+}
+
+// Exceeded reports whether the budget has refused a request. A nil budget is
+// never exceeded.
+func (b *Budget) Exceeded() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.exceeded
+}
+
+// reserve reserves the estimated tokens and cost of a request, or returns
+// ErrBudgetExceeded if they do not fit the remaining budget.
+func (b *Budget) reserve(tokens int, cost float64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.exceeded {
+		if b.limits.MaxTokens > 0 && b.spent.TokensUsed+b.reservedTokens+tokens > b.limits.MaxTokens {
+			b.exceeded = true
+		}
+		if b.limits.MaxCostUSD > 0 && b.spent.CostUSD+b.reservedCost+cost > b.limits.MaxCostUSD {
+			b.exceeded = true
+		}
+	}
+	if b.exceeded {
+		return fmt.Errorf("%w: spent %d tokens and $%.4f of %s", ErrBudgetExceeded, b.spent.TokensUsed, b.spent.CostUSD, b.limits)
+	}
+
+	b.reservedTokens += tokens
+	b.reservedCost += cost
+	return nil
+}
+
+// release releases a reservation and charges the usage of the response, if
+// the request succeeded. A response without reported usage is charged the
+// estimated prompt tokens.
+func (b *Budget) release(tokens int, cost float64, response *CacheableResponse) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.reservedTokens -= tokens
+	b.reservedCost -= cost
+	if response == nil {
+		return
+	}
+
+	charged := *response
+	if charged.TokensUsed == 0 {
+		charged.PromptTokens = tokens
+		charged.TokensUsed = tokens
+	}
+	b.spent.Add(&charged)
+}
+
+// estimateRequest estimates the prompt tokens of a request and their cost
+// before it is sent.
+func estimateRequest(request *ExtractionRequest) (int, float64) {
+	modelID := request.ModelID
+	if request.Provider != nil {
+		modelID = request.Provider.GetModelID()
+	}
+
+	tokens := providers.EstimateTokens(buildExtractionPrompt(request))
+	if model, ok := providers.LookupModel(modelID); ok {
+		return tokens, model.Cost(providers.Usage{PromptTokens: tokens})
+	}
+	return tokens, 0
+}
@@ -7,8 +7,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/sehwan505/langextract-go/internal/alignment"
+	"github.com/sehwan505/langextract-go/internal/chunking"
 	"github.com/sehwan505/langextract-go/pkg/document"
 	"github.com/sehwan505/langextract-go/pkg/extraction"
+	"github.com/sehwan505/langextract-go/pkg/types"
 )
 
 // ExtractionEngine is the main processing pipeline for document extraction.
@@ -16,9 +19,18 @@ import (
 // and result aggregation.
 type ExtractionEngine struct {
 	providerManager *ProviderManager
+	aligner         alignment.MultiAligner
 	config          *ExtractionEngineConfig
 	activeRequests  map[string]*ExtractionRequest
 	requestMutex    sync.RWMutex
+
+	// spend aggregates the provider requests of all extractions
+	spend   Spend
+	spendMu sync.Mutex
+
+	// requestSlots bounds the chunk requests of all extractions to
+	// MaxConcurrentRequests
+	requestSlots chan struct{}
 }
 
 // ExtractionEngineConfig configures the extraction engine behavior.
@@ -66,10 +78,17 @@ func NewExtractionEngine(config *ExtractionEngineConfig) *ExtractionEngine {
 		config.ProviderConfig = DefaultProviderManagerConfig()
 	}
 
+	limit := config.MaxConcurrentRequests
+	if limit <= 0 {
+		limit = 1
+	}
+
 	engine := &ExtractionEngine{
 		providerManager: NewProviderManager(config.ProviderConfig),
+		aligner:         alignment.NewMultiAligner(),
 		config:          config,
 		activeRequests:  make(map[string]*ExtractionRequest),
+		requestSlots:    make(chan struct{}, limit),
 	}
 
 	return engine
@@ -181,6 +200,19 @@ func (e *ExtractionEngine) stageInitialization(request *ExtractionRequest, respo
 	if request.ExtractionPasses < 1 {
 		request.ExtractionPasses = 1
 	}
+	if request.AlignmentOptions != nil {
+		if err := request.AlignmentOptions.Validate(); err != nil {
+			return fmt.Errorf("invalid alignment options: %w", err)
+		}
+	}
+	if !request.UngroundedPolicy.IsValid() {
+		return fmt.Errorf("unknown ungrounded policy: %s", request.UngroundedPolicy)
+	}
+	if request.ChunkingOptions != nil {
+		if err := request.ChunkingOptions.Validate(); err != nil {
+			return fmt.Errorf("invalid chunking options: %w", err)
+		}
+	}
 
 	// Log processing step
 	response.AddProcessingStep(
@@ -217,12 +249,11 @@ func (e *ExtractionEngine) stagePreprocessing(request *ExtractionRequest, respon
 		request.Text = request.Document.Text
 	}
 
-	// Basic text preprocessing
-	text := strings.TrimSpace(request.Text)
-	if text == "" {
+	// Basic text validation. The text itself is left untouched so that
+	// character offsets remain valid against the original document.
+	if strings.TrimSpace(request.Text) == "" {
 		return fmt.Errorf("document text is empty after preprocessing")
 	}
-	request.Text = text
 
 	response.AddProcessingStep(
 		"preprocessing", 
@@ -251,9 +282,19 @@ func (e *ExtractionEngine) stageExtraction(ctx context.Context, request *Extract
 		passCount = e.determineOptimalPasses(request)
 	}
 
+	chunks, err := e.createChunks(ctx, request)
+	if err != nil {
+		return fmt.Errorf("chunking failed: %w", err)
+	}
+	response.ChunksProcessed = len(chunks)
+	tokens := tokenSpans(request.Text)
+
+	var grounding groundingStats
+	overlapsDropped := 0
+
 	// Execute extraction passes
 	for pass := 1; pass <= passCount; pass++ {
-		passExtractions, err := e.executeExtractionPass(ctx, request, response, pass, passCount)
+		passExtractions, passGrounding, err := e.executeExtractionPass(ctx, request, response, chunks, tokens, pass, passCount)
 		if err != nil {
 			if pass == 1 {
 				// First pass failure is critical
@@ -270,7 +311,15 @@ func (e *ExtractionEngine) stageExtraction(ctx context.Context, request *Extract
 			break
 		}
 
+		// Later passes only add extractions that do not overlap earlier ones
+		if pass > 1 {
+			var dropped int
+			passExtractions, dropped = filterOverlapping(allExtractions, passExtractions)
+			overlapsDropped += dropped
+		}
+
 		allExtractions = append(allExtractions, passExtractions...)
+		grounding.add(passGrounding)
 		response.PassesCompleted = pass
 
 		// Check if additional passes are beneficial
@@ -294,14 +343,81 @@ func (e *ExtractionEngine) stageExtraction(ctx context.Context, request *Extract
 		map[string]any{
 			"passes_completed": response.PassesCompleted,
 			"extraction_count": len(allExtractions),
+			"chunk_count":      len(chunks),
+			"overlaps_dropped": overlapsDropped,
+		},
+	)
+
+	response.AddProcessingStep(
+		string(StageAlignment),
+		"success",
+		fmt.Sprintf("Grounded %d extractions (%d exact, %d fuzzy, %d ungrounded)",
+			grounding.Exact+grounding.Fuzzy, grounding.Exact, grounding.Fuzzy, grounding.Ungrounded),
+		grounding.Duration,
+		map[string]any{
+			"exact_count":      grounding.Exact,
+			"fuzzy_count":      grounding.Fuzzy,
+			"ungrounded_count": grounding.Ungrounded,
+			"dropped_count":    grounding.Dropped,
 		},
 	)
 
 	return nil
 }
 
-// executeExtractionPass executes a single extraction pass.
-func (e *ExtractionEngine) executeExtractionPass(ctx context.Context, request *ExtractionRequest, response *ExtractionResponse, passNum, totalPasses int) ([]*extraction.Extraction, error) {
+// createChunks splits the request text with the request's chunker. Without a
+// chunker the whole text is returned as a single chunk.
+func (e *ExtractionEngine) createChunks(ctx context.Context, request *ExtractionRequest) ([]chunking.TextChunk, error) {
+	if request.Chunker == nil {
+		return []chunking.TextChunk{{
+			ID:           request.ID,
+			Text:         request.Text,
+			CharInterval: &types.CharInterval{StartPos: 0, EndPos: len(request.Text)},
+			TotalChunks:  1,
+		}}, nil
+	}
+
+	opts := chunking.DefaultChunkingOptions()
+	if request.ChunkingOptions != nil {
+		opts = *request.ChunkingOptions
+	}
+	if limit := maxChunkChars(request); limit > 0 && opts.MaxCharBuffer > limit {
+		opts.MaxCharBuffer = limit
+		if opts.MinChunkSize >= limit {
+			opts.MinChunkSize = limit / 2
+		}
+	}
+
+	chunks, err := request.Chunker.ChunkText(ctx, request.Text, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("%s chunker produced no chunks", request.Chunker.Name())
+	}
+
+	for i, chunk := range chunks {
+		interval := chunk.CharInterval
+		if interval == nil || interval.StartPos < 0 || interval.EndPos > len(request.Text) || interval.StartPos > interval.EndPos {
+			return nil, fmt.Errorf("chunk %d has an invalid character interval", i)
+		}
+	}
+
+	return chunks, nil
+}
+
+// chunkResult holds the outcome of extracting from a single chunk.
+type chunkResult struct {
+	extractions []*extraction.Extraction
+	grounding   groundingStats
+	response    *CacheableResponse
+	err         error
+}
+
+// executeExtractionPass executes a single extraction pass over all chunks.
+// Chunks are processed concurrently when the request enables parallel
+// processing; results are always combined in chunk order.
+func (e *ExtractionEngine) executeExtractionPass(ctx context.Context, request *ExtractionRequest, response *ExtractionResponse, chunks []chunking.TextChunk, tokens []types.CharInterval, passNum, totalPasses int) ([]*extraction.Extraction, groundingStats, error) {
 	// Update progress
 	if request.ProgressCallback != nil {
 		progress := ExtractionProgress{
@@ -311,28 +427,196 @@ func (e *ExtractionEngine) executeExtractionPass(ctx context.Context, request *E
 			Message:     fmt.Sprintf("Executing extraction pass %d of %d", passNum, totalPasses),
 			CurrentPass: passNum,
 			TotalPasses: totalPasses,
+			TotalChunks: len(chunks),
 		}
 		request.ProgressCallback(progress)
 	}
 
-	// Execute request with provider manager (includes failover)
-	cachedResponse, err := e.providerManager.ExecuteWithFailover(ctx, request)
+	passRequest := request
+	if passNum > 1 {
+		passCopy := *request
+		passCopy.Pass = passNum
+		passRequest = &passCopy
+	}
+
+	var results []chunkResult
+	var err error
+	if request.ParallelProcessing && len(chunks) > 1 {
+		results, err = e.executeChunksParallel(ctx, passRequest, chunks, tokens, passNum, totalPasses)
+	} else {
+		results, err = e.executeChunksSequential(ctx, passRequest, chunks, tokens, passNum, totalPasses)
+	}
 	if err != nil {
-		return nil, err
+		return nil, groundingStats{}, err
+	}
+
+	var passExtractions []*extraction.Extraction
+	var stats groundingStats
+
+	for _, result := range results {
+		// Update response metadata
+		response.ProviderUsed = result.response.ProviderID
+		response.ModelUsed = result.response.ModelID
+		response.TokensUsed += result.response.TokensUsed
+		response.CostUSD += result.response.CostUSD
+		response.EstimatedCostUSD += result.response.EstimatedCostUSD
+
+		passExtractions = append(passExtractions, result.extractions...)
+		stats.add(result.grounding)
+	}
+
+	return passExtractions, stats, nil
+}
+
+// executeChunksSequential extracts from chunks one at a time, stopping at the
+// first failure. Each chunk request takes one of the engine's request slots.
+func (e *ExtractionEngine) executeChunksSequential(ctx context.Context, request *ExtractionRequest, chunks []chunking.TextChunk, tokens []types.CharInterval, passNum, totalPasses int) ([]chunkResult, error) {
+	results := make([]chunkResult, 0, len(chunks))
+
+	for i, chunk := range chunks {
+		e.reportChunkProgress(request, i, len(chunks), passNum, totalPasses)
+
+		var result chunkResult
+		select {
+		case e.requestSlots <- struct{}{}:
+			result = e.executeChunk(ctx, request, chunk, tokens)
+			<-e.requestSlots
+		case <-ctx.Done():
+			result = chunkResult{err: ctx.Err()}
+		}
+		if result.err != nil {
+			return nil, chunkError(i, len(chunks), result.err)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// executeChunksParallel extracts from all chunks concurrently. The engine's
+// request slots are shared by all extractions, so at most
+// MaxConcurrentRequests provider calls run at once however many documents are
+// extracted concurrently. The first failure cancels the remaining chunks.
+func (e *ExtractionEngine) executeChunksParallel(ctx context.Context, request *ExtractionRequest, chunks []chunking.TextChunk, tokens []types.CharInterval, passNum, totalPasses int) ([]chunkResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chunkResult, len(chunks))
+	sem := e.requestSlots
+	var wg sync.WaitGroup
+
+	var failMu sync.Mutex
+	var firstErr error
+	completed := 0
+
+	for i, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i] = chunkResult{err: ctx.Err()}
+			continue
+		}
+
+		wg.Add(1)
+		go func(index int, chunk chunking.TextChunk) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := e.executeChunk(ctx, request, chunk, tokens)
+			results[index] = result
+
+			failMu.Lock()
+			defer failMu.Unlock()
+			if result.err != nil {
+				// Keep the root cause rather than the cancellations it triggers
+				if firstErr == nil {
+					firstErr = chunkError(index, len(chunks), result.err)
+				}
+				cancel()
+				return
+			}
+			completed++
+			e.reportChunkProgress(request, completed, len(chunks), passNum, totalPasses)
+		}(i, chunk)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	for i, result := range results {
+		if result.err != nil {
+			return nil, chunkError(i, len(chunks), result.err)
+		}
 	}
 
-	// Update response metadata
-	response.ProviderUsed = cachedResponse.ProviderID
-	response.ModelUsed = cachedResponse.ModelID
-	response.TokensUsed += cachedResponse.TokensUsed
+	return results, nil
+}
+
+// chunkError wraps a chunk failure with its index when a request has several chunks.
+func chunkError(index, totalChunks int, err error) error {
+	if totalChunks <= 1 {
+		return err
+	}
+	return fmt.Errorf("chunk %d: %w", index, err)
+}
+
+// reportChunkProgress reports chunk progress for multi-chunk requests.
+func (e *ExtractionEngine) reportChunkProgress(request *ExtractionRequest, processed, totalChunks, passNum, totalPasses int) {
+	if request.ProgressCallback == nil || totalChunks <= 1 {
+		return
+	}
+
+	request.ProgressCallback(ExtractionProgress{
+		RequestID:       request.ID,
+		Stage:           string(StageChunkProcessing),
+		Progress:        (float64(passNum-1) + float64(processed)/float64(totalChunks)) / float64(totalPasses),
+		Message:         fmt.Sprintf("Processed %d of %d chunks", processed, totalChunks),
+		CurrentPass:     passNum,
+		TotalPasses:     totalPasses,
+		CurrentChunk:    processed,
+		ChunksProcessed: processed,
+		TotalChunks:     totalChunks,
+	})
+}
+
+// executeChunk extracts from a single chunk and grounds the results at their
+// position in the whole document.
+func (e *ExtractionEngine) executeChunk(ctx context.Context, request *ExtractionRequest, chunk chunking.TextChunk, tokens []types.CharInterval) chunkResult {
+	offset := chunk.CharInterval.StartPos
+	chunkRequest := request
+	if offset != 0 || chunk.CharInterval.EndPos != len(request.Text) {
+		chunkCopy := *request
+		chunkCopy.ID = fmt.Sprintf("%s_chunk_%d", request.ID, chunk.ChunkIndex)
+		chunkCopy.Text = request.Text[offset:chunk.CharInterval.EndPos]
+		chunkRequest = &chunkCopy
+	}
+
+	// Execute request with provider manager (includes failover)
+	cachedResponse, err := e.providerManager.ExecuteWithFailover(ctx, chunkRequest)
+	if err != nil {
+		return chunkResult{err: err}
+	}
+	e.recordSpend(cachedResponse)
 
 	// Parse extractions from response
-	extractions, err := e.parseExtractions(cachedResponse.Output, request.Text)
+	extractions, err := e.parseExtractions(chunkRequest, cachedResponse.Output)
+	if err != nil {
+		return chunkResult{err: fmt.Errorf("failed to parse extractions: %w", err)}
+	}
+
+	// Ground extractions in the chunk text, at document positions
+	extractions, stats, err := e.alignExtractions(ctx, chunkRequest, extractions, offset, tokens)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse extractions: %w", err)
+		return chunkResult{err: fmt.Errorf("failed to align extractions: %w", err)}
 	}
 
-	return extractions, nil
+	return chunkResult{
+		extractions: extractions,
+		grounding:   stats,
+		response:    cachedResponse,
+	}
 }
 
 // stageAggregation aggregates and deduplicates results from multiple passes.
@@ -531,29 +815,51 @@ func (e *ExtractionEngine) calculatePassImprovement(allExtractions, newExtractio
 	return float64(len(newExtractions)) / float64(len(allExtractions))
 }
 
-func (e *ExtractionEngine) parseExtractions(output, sourceText string) ([]*extraction.Extraction, error) {
-	// This is a simplified implementation - would need proper JSON parsing
-	// For now, return empty list if we can't parse
-	return make([]*extraction.Extraction, 0), nil
+func (e *ExtractionEngine) parseExtractions(request *ExtractionRequest, output string) ([]*extraction.Extraction, error) {
+	return parseExtractionOutput(output, request.Provider)
+}
+
+// alignExtractions grounds extractions in the request text using the engine's
+// aligner. The request text starts at offset in a document with the given tokens.
+func (e *ExtractionEngine) alignExtractions(ctx context.Context, request *ExtractionRequest, extractions []*extraction.Extraction, offset int, tokens []types.CharInterval) ([]*extraction.Extraction, groundingStats, error) {
+	opts := alignment.DefaultAlignmentOptions()
+	if request.AlignmentOptions != nil {
+		opts = *request.AlignmentOptions
+	}
+
+	policy := request.UngroundedPolicy
+	if policy == "" {
+		policy = UngroundedKeep
+	}
+
+	return groundExtractions(ctx, e.aligner, extractions, request.Text, offset, tokens, opts, policy)
 }
 
 func (e *ExtractionEngine) deduplicateExtractions(extractions []*extraction.Extraction) []*extraction.Extraction {
-	seen := make(map[string]*extraction.Extraction)
+	seen := make(map[string]int)
 	result := make([]*extraction.Extraction, 0)
 
 	for _, ext := range extractions {
+		// Grounded extractions are duplicates only when they cover the same span
 		key := fmt.Sprintf("%s:%s", ext.ExtractionClass, ext.ExtractionText)
-		if existing, exists := seen[key]; exists {
+		if ext.CharInterval != nil {
+			key = fmt.Sprintf("%s@%d:%d", ext.ExtractionClass, ext.CharInterval.StartPos, ext.CharInterval.EndPos)
+		}
+
+		index, exists := seen[key]
+		if !exists {
+			index = findOverlappingDuplicate(result, ext)
+		}
+
+		if index >= 0 {
 			// Keep the one with higher confidence
 			if extConf, ok := ext.GetConfidence(); ok {
-				if existingConf, ok := existing.GetConfidence(); ok {
-					if extConf > existingConf {
-						seen[key] = ext
-					}
+				if existingConf, ok := result[index].GetConfidence(); !ok || extConf > existingConf {
+					result[index] = ext
 				}
 			}
 		} else {
-			seen[key] = ext
+			seen[key] = len(result)
 			result = append(result, ext)
 		}
 	}
@@ -561,6 +867,55 @@ func (e *ExtractionEngine) deduplicateExtractions(extractions []*extraction.Extr
 	return result
 }
 
+// filterOverlapping returns the candidates whose spans do not overlap any
+// extraction in existing or any earlier accepted candidate, together with the
+// number of candidates removed. Ungrounded candidates are always kept.
+func filterOverlapping(existing, candidates []*extraction.Extraction) ([]*extraction.Extraction, int) {
+	accepted := make([]*extraction.Extraction, 0, len(candidates))
+
+	overlaps := func(ext *extraction.Extraction, others []*extraction.Extraction) bool {
+		for _, other := range others {
+			if other.CharInterval != nil && other.CharInterval.Overlaps(*ext.CharInterval) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, ext := range candidates {
+		if ext.CharInterval != nil && (overlaps(ext, existing) || overlaps(ext, accepted)) {
+			continue
+		}
+		accepted = append(accepted, ext)
+	}
+
+	return accepted, len(candidates) - len(accepted)
+}
+
+// findOverlappingDuplicate returns the index of a grounded extraction in
+// extractions with the same class and text as ext whose span overlaps it, or
+// -1. Such pairs arise when overlapping chunks align the same mention slightly
+// differently.
+func findOverlappingDuplicate(extractions []*extraction.Extraction, ext *extraction.Extraction) int {
+	if ext.CharInterval == nil {
+		return -1
+	}
+
+	for i, other := range extractions {
+		if other.CharInterval == nil || other.ExtractionClass != ext.ExtractionClass {
+			continue
+		}
+		if !strings.EqualFold(other.ExtractionText, ext.ExtractionText) {
+			continue
+		}
+		if other.CharInterval.Overlaps(*ext.CharInterval) {
+			return i
+		}
+	}
+
+	return -1
+}
+
 func (e *ExtractionEngine) resolveOverlaps(extractions []*extraction.Extraction) []*extraction.Extraction {
 	// Simple implementation - would need proper overlap detection
 	return extractions
@@ -595,6 +950,21 @@ func (e *ExtractionEngine) GetActiveRequests() map[string]*ExtractionRequest {
 	return result
 }
 
+// recordSpend adds the spend of a provider response to the engine totals.
+func (e *ExtractionEngine) recordSpend(response *CacheableResponse) {
+	e.spendMu.Lock()
+	defer e.spendMu.Unlock()
+	e.spend.Add(response)
+}
+
+// Spend returns the token usage and cost of all provider requests made by
+// the engine, including those of failed extractions.
+func (e *ExtractionEngine) Spend() Spend {
+	e.spendMu.Lock()
+	defer e.spendMu.Unlock()
+	return e.spend
+}
+
 // GetProviderHealth returns the health status of all providers.
 func (e *ExtractionEngine) GetProviderHealth() map[string]*ProviderHealth {
 	return e.providerManager.GetProviderHealth()
@@ -603,4 +973,9 @@ func (e *ExtractionEngine) GetProviderHealth() map[string]*ProviderHealth {
 // GetCacheStats returns cache statistics.
 func (e *ExtractionEngine) GetCacheStats() map[string]interface{} {
 	return e.providerManager.GetCacheStats()
+}
+
+// Close releases background resources held by the engine.
+func (e *ExtractionEngine) Close() {
+	e.providerManager.Close()
 }
\ No newline at end of file
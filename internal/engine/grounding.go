@@ -0,0 +1,200 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sehwan505/langextract-go/internal/alignment"
+	"github.com/sehwan505/langextract-go/pkg/extraction"
+	"github.com/sehwan505/langextract-go/pkg/types"
+)
+
+// UngroundedPolicy defines how extractions that cannot be aligned to the
+// source text are handled.
+type UngroundedPolicy string
+
+const (
+	// UngroundedKeep keeps ungroundable extractions without position information
+	UngroundedKeep UngroundedPolicy = "keep"
+
+	// UngroundedDrop removes ungroundable extractions from the result
+	UngroundedDrop UngroundedPolicy = "drop"
+
+	// UngroundedFlag keeps ungroundable extractions and marks them with the
+	// UngroundedAttribute attribute
+	UngroundedFlag UngroundedPolicy = "flag"
+)
+
+// UngroundedAttribute is the attribute set on extractions flagged by UngroundedFlag.
+const UngroundedAttribute = "ungrounded"
+
+// IsValid checks if the policy is a known ungrounded policy.
+func (p UngroundedPolicy) IsValid() bool {
+	switch p {
+	case "", UngroundedKeep, UngroundedDrop, UngroundedFlag:
+		return true
+	default:
+		return false
+	}
+}
+
+// groundingStats summarizes the outcome of aligning a set of extractions.
+type groundingStats struct {
+	Exact      int
+	Fuzzy      int
+	Ungrounded int
+	Dropped    int
+	Duration   time.Duration
+}
+
+// add accumulates the counts from other.
+func (s *groundingStats) add(other groundingStats) {
+	s.Exact += other.Exact
+	s.Fuzzy += other.Fuzzy
+	s.Ungrounded += other.Ungrounded
+	s.Dropped += other.Dropped
+	s.Duration += other.Duration
+}
+
+// groundExtractions aligns extractions to the source text, setting their
+// character interval, token interval and alignment status. Repeated mentions
+// of the same text are assigned to successive occurrences in the source.
+// Extractions that cannot be aligned are handled according to policy.
+//
+// source may be a chunk of a larger document starting at offset; intervals are
+// shifted by offset and token intervals are computed from tokens, the token
+// spans of the whole document.
+func groundExtractions(ctx context.Context, aligner alignment.MultiAligner, extractions []*extraction.Extraction, source string, offset int, tokens []types.CharInterval, opts alignment.AlignmentOptions, policy UngroundedPolicy) ([]*extraction.Extraction, groundingStats, error) {
+	var stats groundingStats
+	start := time.Now()
+
+	cursors := make(map[string]int)
+	result := make([]*extraction.Extraction, 0, len(extractions))
+
+	for _, ext := range extractions {
+		if ext.CharInterval == nil {
+			key := strings.ToLower(ext.ExtractionText)
+			interval, status, err := alignFrom(ctx, aligner, ext.ExtractionText, source, cursors[key], opts)
+			if err != nil {
+				return nil, stats, err
+			}
+
+			if interval == nil {
+				stats.Ungrounded++
+				ext.SetAlignmentStatus(types.AlignmentNone)
+
+				switch policy {
+				case UngroundedDrop:
+					stats.Dropped++
+					continue
+				case UngroundedFlag:
+					ext.AddAttribute(UngroundedAttribute, true)
+				}
+
+				result = append(result, ext)
+				continue
+			}
+
+			cursors[key] = interval.EndPos
+			ext.SetCharInterval(&types.CharInterval{
+				StartPos: interval.StartPos + offset,
+				EndPos:   interval.EndPos + offset,
+			})
+			ext.SetAlignmentStatus(status)
+		}
+
+		if ext.TokenInterval == nil {
+			ext.SetTokenInterval(tokenInterval(tokens, ext.CharInterval))
+		}
+
+		if ext.AlignmentStatus != nil && *ext.AlignmentStatus == types.AlignmentExact {
+			stats.Exact++
+		} else {
+			stats.Fuzzy++
+		}
+		result = append(result, ext)
+	}
+
+	stats.Duration = time.Since(start)
+	return result, stats, nil
+}
+
+// alignFrom aligns text against source starting at offset, falling back to the
+// whole source when no alignment is found after offset. A nil interval is
+// returned when the text cannot be aligned at all.
+func alignFrom(ctx context.Context, aligner alignment.MultiAligner, text, source string, offset int, opts alignment.AlignmentOptions) (*types.CharInterval, types.AlignmentStatus, error) {
+	if offset > 0 && offset < len(source) {
+		interval, result, err := aligner.AlignWithBestMethod(ctx, text, source[offset:], opts)
+		if err == nil && interval != nil {
+			return &types.CharInterval{
+				StartPos: interval.StartPos + offset,
+				EndPos:   interval.EndPos + offset,
+			}, result.Status, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, types.AlignmentNone, ctxErr
+		}
+	}
+
+	interval, result, err := aligner.AlignWithBestMethod(ctx, text, source, opts)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, types.AlignmentNone, ctxErr
+		}
+		return nil, types.AlignmentNone, nil
+	}
+	if interval == nil {
+		return nil, types.AlignmentNone, nil
+	}
+
+	return interval, result.Status, nil
+}
+
+// tokenSpans returns the character interval of each whitespace-delimited token
+// in text, matching the tokenization used by document.Document.
+func tokenSpans(text string) []types.CharInterval {
+	var spans []types.CharInterval
+	start := -1
+	for pos, r := range text {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				spans = append(spans, types.CharInterval{StartPos: start, EndPos: pos})
+				start = -1
+			}
+		} else if start < 0 {
+			start = pos
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, types.CharInterval{StartPos: start, EndPos: len(text)})
+	}
+	return spans
+}
+
+// tokenInterval returns the half-open range of tokens overlapping interval.
+func tokenInterval(tokens []types.CharInterval, interval *types.CharInterval) *types.TokenInterval {
+	if interval == nil {
+		return nil
+	}
+
+	start, end := -1, -1
+	for i, token := range tokens {
+		if token.EndPos <= interval.StartPos {
+			continue
+		}
+		if token.StartPos >= interval.EndPos {
+			break
+		}
+		if start < 0 {
+			start = i
+		}
+		end = i + 1
+	}
+
+	if start < 0 {
+		return nil
+	}
+	return &types.TokenInterval{StartToken: start, EndToken: end}
+}
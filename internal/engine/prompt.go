@@ -0,0 +1,320 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sehwan505/langextract-go/pkg/extraction"
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// buildExtractionPrompt constructs the prompt sent to the language model for a request.
+func buildExtractionPrompt(request *ExtractionRequest) string {
+	var prompt strings.Builder
+
+	// Add task description
+	prompt.WriteString("Extract structured information from the following text.\n\n")
+
+	if request.TaskDescription != "" {
+		prompt.WriteString("Task: ")
+		prompt.WriteString(request.TaskDescription)
+		prompt.WriteString("\n\n")
+	}
+
+	// Add examples if provided
+	if len(request.Examples) > 0 {
+		prompt.WriteString("Examples:\n")
+		for i, example := range request.Examples {
+			prompt.WriteString(fmt.Sprintf("\nExample %d:\n", i+1))
+			prompt.WriteString("Text: ")
+			prompt.WriteString(example.Text)
+			prompt.WriteString("\n")
+
+			if len(example.Extractions) > 0 {
+				prompt.WriteString("Extractions:\n")
+				for _, ext := range example.Extractions {
+					prompt.WriteString(fmt.Sprintf("- %s: %s\n", ext.ExtractionClass, ext.ExtractionText))
+				}
+			}
+		}
+		prompt.WriteString("\n")
+	}
+
+	// Add schema information if provided
+	if request.Schema != nil {
+		prompt.WriteString("Expected extraction classes: ")
+		prompt.WriteString(strings.Join(request.Schema.GetClasses(), ", "))
+		prompt.WriteString("\n\n")
+		writeSchemaAttributes(&prompt, request.Schema)
+	}
+
+	// Add additional document context if present
+	if request.Document != nil && request.Document.AdditionalContext != "" {
+		prompt.WriteString("Additional context:\n")
+		prompt.WriteString(request.Document.AdditionalContext)
+		prompt.WriteString("\n\n")
+	}
+
+	// Add the text to process
+	prompt.WriteString("Text to process:\n")
+	prompt.WriteString(request.Text)
+	prompt.WriteString("\n\n")
+
+	// Add format instructions
+	writeFormatInstructions(&prompt)
+
+	return prompt.String()
+}
+
+// buildExtractionMessages constructs the conversation sent to chat models
+// for a request. The instructions are the system message, each example is a
+// user turn answered by an assistant turn in the output format, and the text
+// to process is the final user turn.
+func buildExtractionMessages(request *ExtractionRequest) []providers.Message {
+	var system strings.Builder
+	system.WriteString("Extract structured information from the text of each user message.\n\n")
+
+	if request.TaskDescription != "" {
+		system.WriteString("Task: ")
+		system.WriteString(request.TaskDescription)
+		system.WriteString("\n\n")
+	}
+
+	if request.Schema != nil {
+		system.WriteString("Expected extraction classes: ")
+		system.WriteString(strings.Join(request.Schema.GetClasses(), ", "))
+		system.WriteString("\n\n")
+		writeSchemaAttributes(&system, request.Schema)
+	}
+	writeFormatInstructions(&system)
+
+	messages := []providers.Message{{Role: providers.RoleSystem, Content: system.String()}}
+	for _, example := range request.Examples {
+		messages = append(messages,
+			providers.Message{Role: providers.RoleUser, Content: "Text to process:\n" + example.Text},
+			providers.Message{Role: providers.RoleAssistant, Content: exampleOutput(example)},
+		)
+	}
+
+	var text strings.Builder
+	if request.Document != nil && request.Document.AdditionalContext != "" {
+		text.WriteString("Additional context:\n")
+		text.WriteString(request.Document.AdditionalContext)
+		text.WriteString("\n\n")
+	}
+	text.WriteString("Text to process:\n")
+	text.WriteString(request.Text)
+
+	return append(messages, providers.Message{Role: providers.RoleUser, Content: text.String()})
+}
+
+// writeFormatInstructions describes the JSON output format.
+func writeFormatInstructions(prompt *strings.Builder) {
+	prompt.WriteString("Please extract entities in the following JSON format:\n")
+	prompt.WriteString("{\n")
+	prompt.WriteString("  \"extractions\": [\n")
+	prompt.WriteString("    {\n")
+	prompt.WriteString("      \"extraction_class\": \"class_name\",\n")
+	prompt.WriteString("      \"extraction_text\": \"extracted_text\",\n")
+	prompt.WriteString("      \"confidence\": 0.95\n")
+	prompt.WriteString("    }\n")
+	prompt.WriteString("  ]\n")
+	prompt.WriteString("}")
+}
+
+// exampleOutput renders the extractions of an example in the output format,
+// with their attributes as additional keys.
+func exampleOutput(example *extraction.ExampleData) string {
+	items := make([]map[string]any, 0, len(example.Extractions))
+	for _, ext := range example.Extractions {
+		item := make(map[string]any, len(ext.Attributes)+2)
+		for key, value := range ext.Attributes {
+			item[key] = value
+		}
+		item["extraction_class"] = ext.ExtractionClass
+		item["extraction_text"] = ext.ExtractionText
+		items = append(items, item)
+	}
+
+	data, err := json.Marshal(map[string]any{"extractions": items})
+	if err != nil {
+		// Attributes that cannot be encoded are left out
+		for i, ext := range example.Extractions {
+			items[i] = map[string]any{"extraction_class": ext.ExtractionClass, "extraction_text": ext.ExtractionText}
+		}
+		data, _ = json.Marshal(map[string]any{"extractions": items})
+	}
+	return string(data)
+}
+
+// writeSchemaAttributes describes the attributes of each class in a schema
+// that defines fields. Attributes are returned as extra keys on each extraction.
+func writeSchemaAttributes(prompt *strings.Builder, schema extraction.ExtractionSchema) {
+	basic, ok := schema.(*extraction.BasicExtractionSchema)
+	if !ok {
+		return
+	}
+
+	var described bool
+	for _, name := range basic.GetClasses() {
+		class := basic.GetClass(name)
+		if class == nil || len(class.Fields) == 0 {
+			continue
+		}
+		if !described {
+			prompt.WriteString("Include these attributes as additional keys on each extraction:\n")
+			described = true
+		}
+
+		prompt.WriteString(fmt.Sprintf("%s:\n", name))
+		for _, field := range class.Fields {
+			prompt.WriteString(fmt.Sprintf("- %s", field.Name))
+
+			var details []string
+			if field.Type != "" {
+				details = append(details, field.Type)
+			}
+			if field.Required {
+				details = append(details, "required")
+			}
+			if len(field.Enum) > 0 {
+				details = append(details, "one of: "+strings.Join(field.Enum, ", "))
+			}
+			if field.Minimum != nil {
+				details = append(details, fmt.Sprintf("minimum %v", *field.Minimum))
+			}
+			if field.Maximum != nil {
+				details = append(details, fmt.Sprintf("maximum %v", *field.Maximum))
+			}
+			if len(details) > 0 {
+				prompt.WriteString(" (" + strings.Join(details, ", ") + ")")
+			}
+			if field.Description != "" {
+				prompt.WriteString(": " + field.Description)
+			}
+			prompt.WriteString("\n")
+		}
+	}
+	if described {
+		prompt.WriteString("\n")
+	}
+}
+
+// parseExtractionOutput parses raw model output into Extraction objects.
+// The provider's ParseOutput is used when available; plain string results
+// are decoded as JSON after stripping markdown code fences.
+func parseExtractionOutput(output string, provider providers.BaseLanguageModel) ([]*extraction.Extraction, error) {
+	var parsed any = output
+	if provider != nil {
+		var err error
+		parsed, err = provider.ParseOutput(output)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse provider response: %w", err)
+		}
+	}
+
+	if text, ok := parsed.(string); ok {
+		if err := json.Unmarshal([]byte(stripCodeFences(text)), &parsed); err != nil {
+			return nil, fmt.Errorf("response is not valid JSON: %w", err)
+		}
+	}
+
+	// Convert to map
+	data, ok := parsed.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected JSON object, got %T", parsed)
+	}
+
+	// Get extractions array
+	extractionsData, ok := data["extractions"]
+	if !ok {
+		return nil, fmt.Errorf("no 'extractions' field found in response")
+	}
+
+	extractionsArray, ok := extractionsData.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'extractions' field is not an array")
+	}
+
+	// Parse each extraction
+	extractions := make([]*extraction.Extraction, 0, len(extractionsArray))
+	for _, item := range extractionsArray {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue // Skip invalid items
+		}
+
+		class, _ := itemMap["extraction_class"].(string)
+		text, _ := itemMap["extraction_text"].(string)
+
+		if class == "" || text == "" {
+			continue // Skip incomplete extractions
+		}
+
+		ext := extraction.NewExtraction(class, text)
+
+		// Add confidence if present
+		if conf, ok := itemMap["confidence"].(float64); ok {
+			ext.SetConfidence(conf)
+		}
+
+		// Add other attributes. Null values are treated as absent, since
+		// strict structured outputs fill unused optional fields with null.
+		for key, value := range itemMap {
+			if value == nil {
+				continue
+			}
+			if key != "extraction_class" && key != "extraction_text" && key != "confidence" && key != "attributes" {
+				ext.AddAttribute(key, value)
+			}
+		}
+
+		// Models sometimes nest attributes in an "attributes" object
+		if nested, ok := itemMap["attributes"].(map[string]interface{}); ok {
+			for key, value := range nested {
+				if _, exists := ext.GetAttribute(key); !exists && value != nil {
+					ext.AddAttribute(key, value)
+				}
+			}
+		} else if value, ok := itemMap["attributes"]; ok {
+			ext.AddAttribute("attributes", value)
+		}
+
+		ext.SetExtractionIndex(len(extractions))
+		extractions = append(extractions, ext)
+	}
+
+	return extractions, nil
+}
+
+// stripCodeFences removes markdown code fences and surrounding whitespace.
+func stripCodeFences(output string) string {
+	output = strings.TrimSpace(output)
+	output = strings.TrimPrefix(output, "```json")
+	output = strings.TrimPrefix(output, "```")
+	output = strings.TrimSuffix(output, "```")
+	return strings.TrimSpace(output)
+}
+
+// maxChunkChars returns the largest chunk, in characters, whose prompt fits
+// the input token budget of the request's model in the model catalog, or
+// zero if the budget is unknown or taken up by the rest of the prompt.
+func maxChunkChars(request *ExtractionRequest) int {
+	model, ok := providers.LookupModel(request.ModelID)
+	if !ok {
+		return 0
+	}
+	budget := model.InputTokenBudget(request.MaxTokens)
+
+	// The prompt without the text to process
+	empty := *request
+	empty.Text = ""
+	available := budget - providers.EstimateTokens(buildExtractionPrompt(&empty))
+	if budget == 0 || available <= 1 {
+		return 0
+	}
+
+	// EstimateTokens counts four bytes per token
+	return available * 4
+}
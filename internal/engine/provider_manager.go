@@ -3,6 +3,8 @@ package engine
 import (
 	"context"
 	"crypto/md5"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -18,6 +20,8 @@ type ProviderManager struct {
 	cache       *ResponseCache
 	config      *ProviderManagerConfig
 	mu          sync.RWMutex
+	stopCh      chan struct{}
+	closeOnce   sync.Once
 }
 
 // ProviderManagerConfig configures the provider manager behavior.
@@ -55,6 +59,12 @@ type ProviderHealth struct {
 	SuccessfulRequests int64        `json:"successful_requests"`
 	FailedRequests    int64         `json:"failed_requests"`
 	LastError         string        `json:"last_error,omitempty"`
+
+	// Token usage and outcomes reported by the provider
+	PromptTokens     int64                          `json:"prompt_tokens"`
+	CompletionTokens int64                          `json:"completion_tokens"`
+	TotalTokens      int64                          `json:"total_tokens"`
+	FinishReasons    map[providers.FinishReason]int `json:"finish_reasons,omitempty"`
 }
 
 // LoadBalanceStrategy defines the load balancing strategy.
@@ -69,10 +79,12 @@ const (
 
 // ResponseCache implements a simple in-memory response cache.
 type ResponseCache struct {
-	cache    map[string]*CacheEntry
-	maxSize  int
-	timeout  time.Duration
-	mu       sync.RWMutex
+	cache     map[string]*CacheEntry
+	maxSize   int
+	timeout   time.Duration
+	mu        sync.RWMutex
+	stopCh    chan struct{}
+	closeOnce sync.Once
 }
 
 // CacheEntry represents a cached response.
@@ -89,6 +101,14 @@ type CacheableResponse struct {
 	Latency     time.Duration `json:"latency"`
 	ProviderID  string    `json:"provider_id"`
 	ModelID     string    `json:"model_id"`
+
+	PromptTokens     int                    `json:"prompt_tokens,omitempty"`
+	CompletionTokens int                    `json:"completion_tokens,omitempty"`
+	FinishReason     providers.FinishReason `json:"finish_reason,omitempty"`
+	ResponseID       string                 `json:"response_id,omitempty"`
+	CostUSD          float64                `json:"cost_usd,omitempty"`
+	EstimatedCostUSD float64                `json:"estimated_cost_usd,omitempty"`
+	Cached           bool                   `json:"cached,omitempty"` // Served from the response cache
 }
 
 // NewProviderManager creates a new provider manager with the given configuration.
@@ -102,6 +122,7 @@ func NewProviderManager(config *ProviderManagerConfig) *ProviderManager {
 		healthStats: make(map[string]*ProviderHealth),
 		cache:       NewResponseCache(config.MaxCacheSize, config.CacheTimeout),
 		config:      config,
+		stopCh:      make(chan struct{}),
 	}
 
 	// Register default providers
@@ -170,6 +191,9 @@ func (pm *ProviderManager) SelectProvider(modelID string, excludeProviders []str
 }
 
 // ExecuteWithFailover executes a request with automatic failover on failure.
+// When the request carries an explicit Provider, that provider is used for
+// every attempt and registry-based failover is skipped. A request with a
+// Budget is refused with ErrBudgetExceeded if the budget cannot cover it.
 func (pm *ProviderManager) ExecuteWithFailover(ctx context.Context, request *ExtractionRequest) (*CacheableResponse, error) {
 	// Check cache first
 	if pm.config.EnableCaching {
@@ -178,6 +202,26 @@ func (pm *ProviderManager) ExecuteWithFailover(ctx context.Context, request *Ext
 		}
 	}
 
+	if request.Budget == nil {
+		return pm.execute(ctx, request)
+	}
+
+	tokens, cost := estimateRequest(request)
+	if err := request.Budget.reserve(tokens, cost); err != nil {
+		return nil, err
+	}
+	response, err := pm.execute(ctx, request)
+	request.Budget.release(tokens, cost, response)
+	return response, err
+}
+
+// execute executes a request with its own provider or with failover between
+// the registered providers.
+func (pm *ProviderManager) execute(ctx context.Context, request *ExtractionRequest) (*CacheableResponse, error) {
+	if request.Provider != nil {
+		return pm.executeWithProvider(ctx, request)
+	}
+
 	var lastErr error
 	excludeProviders := make([]string, 0)
 
@@ -190,11 +234,11 @@ func (pm *ProviderManager) ExecuteWithFailover(ctx context.Context, request *Ext
 
 		// Execute request
 		startTime := time.Now()
-		response, err := pm.executeRequest(ctx, provider, request)
+		response, err := pm.executeRequest(ctx, provider, providerName, request)
 		latency := time.Since(startTime)
 
 		// Update health stats
-		pm.updateProviderHealth(providerName, err == nil, latency, err)
+		pm.updateProviderHealth(providerName, response, latency, err)
 
 		if err == nil {
 			// Cache successful response
@@ -222,13 +266,60 @@ func (pm *ProviderManager) ExecuteWithFailover(ctx context.Context, request *Ext
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
-		case <-time.After(time.Duration(attempt+1) * time.Second):
+		case <-time.After(retryDelay(lastErr, attempt)):
 		}
 	}
 
 	return nil, fmt.Errorf("all provider attempts failed, last error: %w", lastErr)
 }
 
+// executeWithProvider executes a request against the provider attached to it.
+// Failed requests are not retried here: providers retry transient failures
+// themselves with their RetryPolicy, so retrying again would multiply the
+// attempts and ignore the provider's Retry-After pacing.
+func (pm *ProviderManager) executeWithProvider(ctx context.Context, request *ExtractionRequest) (*CacheableResponse, error) {
+	providerName := request.ProviderID
+	if providerName == "" {
+		providerName = request.Provider.GetModelID()
+	}
+
+	startTime := time.Now()
+	response, err := pm.executeRequest(ctx, request.Provider, providerName, request)
+	latency := time.Since(startTime)
+
+	pm.updateProviderHealth(providerName, response, latency, err)
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("provider %s failed: %w", providerName, err)
+	}
+	if pm.config.EnableCaching {
+		pm.cacheResponse(request, response)
+	}
+	return response, nil
+}
+
+// retryDelay returns the delay before retrying a failed request, honoring the
+// delay requested by the provider.
+func retryDelay(err error, retry int) time.Duration {
+	var apiErr *providers.APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+	return providers.DefaultRetryPolicy().Backoff(retry)
+}
+
+// Close stops the background health monitoring and cache cleanup goroutines.
+// It is safe to call Close more than once.
+func (pm *ProviderManager) Close() {
+	pm.closeOnce.Do(func() {
+		close(pm.stopCh)
+		pm.cache.Close()
+	})
+}
+
 // GetProviderHealth returns the health status of all providers.
 func (pm *ProviderManager) GetProviderHealth() map[string]*ProviderHealth {
 	pm.mu.RLock()
@@ -238,6 +329,12 @@ func (pm *ProviderManager) GetProviderHealth() map[string]*ProviderHealth {
 	for name, health := range pm.healthStats {
 		// Create a copy to avoid concurrent modification
 		healthCopy := *health
+		if health.FinishReasons != nil {
+			healthCopy.FinishReasons = make(map[providers.FinishReason]int, len(health.FinishReasons))
+			for reason, count := range health.FinishReasons {
+				healthCopy.FinishReasons[reason] = count
+			}
+		}
 		result[name] = &healthCopy
 	}
 	return result
@@ -269,8 +366,13 @@ func (pm *ProviderManager) healthMonitorLoop() {
 	ticker := time.NewTicker(pm.config.HealthCheckInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		pm.performHealthChecks()
+	for {
+		select {
+		case <-pm.stopCh:
+			return
+		case <-ticker.C:
+			pm.performHealthChecks()
+		}
 	}
 }
 
@@ -374,11 +476,14 @@ func (pm *ProviderManager) selectWeightedRandom(providers []string) string {
 	return pm.selectHealthyOnly(providers)
 }
 
-// updateProviderHealth updates the health statistics for a provider.
-func (pm *ProviderManager) updateProviderHealth(providerName string, success bool, latency time.Duration, err error) {
+// updateProviderHealth updates the health statistics for a provider from the
+// outcome of a request. The response is nil if the request failed.
+func (pm *ProviderManager) updateProviderHealth(providerName string, response *CacheableResponse, latency time.Duration, err error) {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 
+	success := err == nil
+
 	health, exists := pm.healthStats[providerName]
 	if !exists {
 		health = &ProviderHealth{
@@ -389,6 +494,17 @@ func (pm *ProviderManager) updateProviderHealth(providerName string, success boo
 	}
 
 	health.TotalRequests++
+	if response != nil {
+		health.PromptTokens += int64(response.PromptTokens)
+		health.CompletionTokens += int64(response.CompletionTokens)
+		health.TotalTokens += int64(response.TokensUsed)
+		if response.FinishReason != "" {
+			if health.FinishReasons == nil {
+				health.FinishReasons = make(map[providers.FinishReason]int)
+			}
+			health.FinishReasons[response.FinishReason]++
+		}
+	}
 	if success {
 		health.SuccessfulRequests++
 		health.ConsecutiveSuccesses++
@@ -418,31 +534,75 @@ func (pm *ProviderManager) updateProviderHealth(providerName string, success boo
 	}
 }
 
-// executeRequest executes a request with the given provider.
-func (pm *ProviderManager) executeRequest(ctx context.Context, provider providers.BaseLanguageModel, request *ExtractionRequest) (*CacheableResponse, error) {
-	// Build prompt (simplified for now)
-	prompt := fmt.Sprintf("Task: %s\nText: %s", request.TaskDescription, request.Text)
+// executeRequest executes a request with the given provider. Chat models
+// get the request as a conversation with the instructions as the system
+// message and the examples as turns; other models get a single prompt.
+func (pm *ProviderManager) executeRequest(ctx context.Context, provider providers.BaseLanguageModel, providerName string, request *ExtractionRequest) (*CacheableResponse, error) {
+	prompt, messages := renderRequest(provider, request)
+
+	// Fail prompts that cannot fit the model's context window before
+	// spending a request on them
+	model, cataloged := providers.LookupModel(provider.GetModelID())
+	if cataloged {
+		if budget := model.InputTokenBudget(request.MaxTokens); budget > 0 {
+			if tokens := providers.EstimateTokens(prompt); tokens > budget {
+				return nil, fmt.Errorf("prompt of about %d tokens exceeds the %d token input budget of %s, use chunking or smaller chunks: %w",
+					tokens, budget, model.ID, providers.ErrContextLengthExceeded)
+			}
+		}
+	}
 	
 	// Execute the request
-	results, err := provider.Infer(ctx, []string{prompt}, nil)
+	var results []*providers.InferenceResult
+	var err error
+	if messages != nil {
+		results, err = providers.InferMessages(ctx, provider, [][]providers.Message{messages}, nil)
+	} else {
+		results, err = providers.InferDetailed(ctx, provider, []string{prompt}, nil)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if len(results) == 0 || len(results[0]) == 0 {
+	if len(results) == 0 || len(results[0].Outputs) == 0 {
 		return nil, fmt.Errorf("no results returned from provider")
 	}
 
+	result := results[0]
 	response := &CacheableResponse{
-		Output:     results[0][0].Output,
-		TokensUsed: 0, // Would be calculated from actual usage
-		ProviderID: request.ProviderID,
-		ModelID:    request.ModelID,
+		Output:           result.Outputs[0].Output,
+		TokensUsed:       result.Usage.TotalTokens,
+		Latency:          result.Latency,
+		ProviderID:       providerName,
+		ModelID:          provider.GetModelID(),
+		PromptTokens:     result.Usage.PromptTokens,
+		CompletionTokens: result.Usage.CompletionTokens,
+		FinishReason:     result.FinishReason,
+		ResponseID:       result.ResponseID,
+	}
+	if cataloged {
+		response.EstimatedCostUSD = model.Cost(providers.Usage{PromptTokens: providers.EstimateTokens(prompt)})
+		response.CostUSD = model.Cost(result.Usage)
+		if result.Usage == (providers.Usage{}) {
+			// Providers that report no usage are billed the estimate
+			response.CostUSD = response.EstimatedCostUSD
+		}
 	}
 
 	return response, nil
 }
 
+// renderRequest renders a request for a provider. Chat models get the
+// conversation along with its flattened prompt; other models get a single
+// prompt and no messages.
+func renderRequest(provider providers.BaseLanguageModel, request *ExtractionRequest) (string, []providers.Message) {
+	if _, ok := provider.(providers.ChatLanguageModel); ok {
+		messages := buildExtractionMessages(request)
+		return providers.FlattenMessages(messages), messages
+	}
+	return buildExtractionPrompt(request), nil
+}
+
 // Response caching methods
 func (pm *ProviderManager) getCachedResponse(request *ExtractionRequest) *CacheableResponse {
 	if !pm.config.EnableCaching {
@@ -450,7 +610,20 @@ func (pm *ProviderManager) getCachedResponse(request *ExtractionRequest) *Cachea
 	}
 
 	key := pm.generateCacheKey(request)
-	return pm.cache.Get(key)
+	cached := pm.cache.Get(key)
+	if cached == nil {
+		return nil
+	}
+
+	// A cache hit uses no tokens
+	hit := *cached
+	hit.TokensUsed = 0
+	hit.PromptTokens = 0
+	hit.CompletionTokens = 0
+	hit.CostUSD = 0
+	hit.EstimatedCostUSD = 0
+	hit.Cached = true
+	return &hit
 }
 
 func (pm *ProviderManager) cacheResponse(request *ExtractionRequest, response *CacheableResponse) {
@@ -462,17 +635,40 @@ func (pm *ProviderManager) cacheResponse(request *ExtractionRequest, response *C
 	pm.cache.Set(key, response)
 }
 
+// generateCacheKey derives the cache key of a request from everything that
+// shapes the response: the provider and model, the generation settings, the
+// response schema and the prompt as rendered for the provider, which holds
+// the task, examples, context and text.
 func (pm *ProviderManager) generateCacheKey(request *ExtractionRequest) string {
-	// Generate a cache key based on request parameters
-	data := fmt.Sprintf("%s|%s|%s|%f|%d", 
-		request.TaskDescription, 
-		request.Text, 
-		request.ModelID, 
-		request.Temperature, 
-		request.MaxTokens)
-	
-	hash := md5.Sum([]byte(data))
-	return fmt.Sprintf("%x", hash)
+	providerID := request.ProviderID
+	modelID := request.ModelID
+	var rendered string
+	if request.Provider != nil {
+		if providerID == "" {
+			providerID = fmt.Sprintf("%T", request.Provider)
+		}
+		modelID = request.Provider.GetModelID()
+		prompt, messages := renderRequest(request.Provider, request)
+		rendered = prompt
+		if messages != nil {
+			encoded, _ := json.Marshal(messages)
+			rendered = string(encoded)
+		}
+	} else {
+		rendered = buildExtractionPrompt(request)
+	}
+
+	var schema []byte
+	if request.Schema != nil {
+		if jsonSchema, err := request.Schema.ToJSONSchema(); err == nil {
+			schema, _ = json.Marshal(jsonSchema)
+		}
+	}
+
+	hash := md5.New()
+	fmt.Fprintf(hash, "%q|%q|%f|%d|%d|%q|", providerID, modelID, request.Temperature, request.MaxTokens, request.Pass, schema)
+	hash.Write([]byte(rendered))
+	return fmt.Sprintf("%x", hash.Sum(nil))
 }
 
 // NewResponseCache creates a new response cache.
@@ -481,6 +677,7 @@ func NewResponseCache(maxSize int, timeout time.Duration) *ResponseCache {
 		cache:   make(map[string]*CacheEntry),
 		maxSize: maxSize,
 		timeout: timeout,
+		stopCh:  make(chan struct{}),
 	}
 
 	// Start cleanup goroutine
@@ -491,8 +688,9 @@ func NewResponseCache(maxSize int, timeout time.Duration) *ResponseCache {
 
 // Get retrieves a cached response.
 func (c *ResponseCache) Get(key string) *CacheableResponse {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	// A write lock is required because lookups update hit counts
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	entry, exists := c.cache[key]
 	if !exists {
@@ -547,11 +745,23 @@ func (c *ResponseCache) cleanupLoop() {
 	ticker := time.NewTicker(time.Minute)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		c.cleanup()
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			c.cleanup()
+		}
 	}
 }
 
+// Close stops the cleanup goroutine. It is safe to call Close more than once.
+func (c *ResponseCache) Close() {
+	c.closeOnce.Do(func() {
+		close(c.stopCh)
+	})
+}
+
 // cleanup removes expired entries from the cache.
 func (c *ResponseCache) cleanup() {
 	c.mu.Lock()
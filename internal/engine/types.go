@@ -3,8 +3,11 @@ package engine
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/sehwan505/langextract-go/internal/alignment"
+	"github.com/sehwan505/langextract-go/internal/chunking"
 	"github.com/sehwan505/langextract-go/pkg/document"
 	"github.com/sehwan505/langextract-go/pkg/extraction"
 	"github.com/sehwan505/langextract-go/pkg/providers"
@@ -40,6 +43,26 @@ type ExtractionRequest struct {
 	ValidateOutput   bool          `json:"validate_output"`
 	ExtractionPasses int           `json:"extraction_passes"`
 
+	// Pass is the extraction pass this request belongs to. Later passes are
+	// cached separately so they do not reuse first-pass responses.
+	Pass int `json:"pass,omitempty"`
+
+	// ParallelProcessing sends chunk requests to the provider concurrently
+	ParallelProcessing bool `json:"parallel_processing"`
+
+	// Budget, when set, is charged for every provider request and refuses
+	// requests once its limits are reached. It is shared by the requests of a run.
+	Budget *Budget `json:"-"`
+
+	// Source grounding configuration
+	AlignmentOptions *alignment.AlignmentOptions `json:"-"`
+	UngroundedPolicy UngroundedPolicy            `json:"ungrounded_policy,omitempty"`
+
+	// Chunking configuration. When Chunker is set, the text is split into
+	// chunks that are extracted separately and remapped to document positions.
+	Chunker         chunking.TextChunker      `json:"-"`
+	ChunkingOptions *chunking.ChunkingOptions `json:"-"`
+
 	// Context and cancellation
 	Context context.Context `json:"-"` // Not serialized
 
@@ -60,9 +83,12 @@ type ExtractionResponse struct {
 	// Execution metadata
 	ExecutionTime    time.Duration `json:"execution_time"`
 	TokensUsed       int           `json:"tokens_used,omitempty"`
+	CostUSD          float64       `json:"cost_usd,omitempty"` // Estimated from the model catalog, zero if unpriced
+	EstimatedCostUSD float64       `json:"estimated_cost_usd,omitempty"` // Prompt cost estimated before the requests were sent
 	ProviderUsed     string        `json:"provider_used"`
 	ModelUsed        string        `json:"model_used"`
 	PassesCompleted  int           `json:"passes_completed"`
+	ChunksProcessed  int           `json:"chunks_processed,omitempty"`
 	
 	// Quality metrics
 	ExtractionCount  int     `json:"extraction_count"`
@@ -197,6 +223,7 @@ func NewExtractionRequest(doc *document.Document, taskDescription string) *Extra
 		RetryCount:       2,
 		ValidateOutput:   true,
 		ExtractionPasses: 1,
+		UngroundedPolicy: UngroundedKeep,
 		Context:          context.Background(),
 	}
 }
@@ -274,8 +301,10 @@ func (r *ExtractionResponse) AddFailoverEvent(originalProvider, failureReason, f
 	r.DebugInfo.FailoverEvents = append(r.DebugInfo.FailoverEvents, event)
 }
 
+// requestCounter disambiguates request IDs generated at the same instant.
+var requestCounter atomic.Uint64
+
 // generateRequestID generates a unique request ID.
 func generateRequestID() string {
-	// Use timestamp + random suffix for uniqueness
-	return fmt.Sprintf("req_%d_%d", time.Now().UnixNano(), time.Now().Nanosecond()%10000)
+	return fmt.Sprintf("req_%d_%d", time.Now().UnixNano(), requestCounter.Add(1))
 }
\ No newline at end of file
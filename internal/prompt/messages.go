@@ -0,0 +1,112 @@
+package prompt
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sehwan505/langextract-go/pkg/extraction"
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// MessageBuilder is implemented by prompt builders that can emit a prompt as
+// chat messages, for models that accept roles
+type MessageBuilder interface {
+	// BuildMessages constructs the conversation for the given task, text and
+	// examples: the instructions as the system message, each example as a
+	// user turn answered by an assistant turn, and the text as the final
+	// user turn
+	BuildMessages(ctx context.Context, task *ExtractionTask, text string, examples []*extraction.ExampleData) ([]providers.Message, error)
+}
+
+// BuildMessages constructs the prompt as chat messages. The system message
+// is the template's SystemMessage, if set, followed by the task description.
+func (b *FewShotPromptBuilder) BuildMessages(ctx context.Context, task *ExtractionTask, text string, examples []*extraction.ExampleData) ([]providers.Message, error) {
+	if task == nil {
+		return nil, ErrPromptBuildFailed("task cannot be nil", nil)
+	}
+
+	if text == "" {
+		return nil, ErrPromptBuildFailed("text cannot be empty", nil)
+	}
+
+	selectedExamples := examples
+	if len(examples) > b.options.MaxExamples {
+		var err error
+		selectedExamples, err = b.exampleSelector.SelectExamples(ctx, task, examples, b.options.MaxExamples)
+		if err != nil {
+			return nil, ErrPromptBuildFailed("example selection failed", err)
+		}
+	}
+
+	template := b.createPromptTemplate(task, selectedExamples)
+	system := template.SystemMessage
+	if system == "" {
+		system = "You are an expert information extraction system."
+	}
+
+	return buildMessages(joinNonEmpty(system, template.Description), selectedExamples, text, b.options.OutputFormat), nil
+}
+
+// BuildMessages constructs the prompt as chat messages, with the schema in
+// the system message.
+func (b *SchemaPromptBuilder) BuildMessages(ctx context.Context, task *ExtractionTask, text string, examples []*extraction.ExampleData) ([]providers.Message, error) {
+	if task == nil {
+		return nil, ErrPromptBuildFailed("task cannot be nil", nil)
+	}
+
+	if text == "" {
+		return nil, ErrPromptBuildFailed("text cannot be empty", nil)
+	}
+
+	if task.Schema == nil {
+		return nil, ErrPromptBuildFailed("task must have schema for schema-based prompt building", nil)
+	}
+
+	selectedExamples := examples
+	if len(examples) > b.options.MaxExamples {
+		var err error
+		selectedExamples, err = b.exampleSelector.SelectExamples(ctx, task, examples, b.options.MaxExamples)
+		if err != nil {
+			return nil, ErrPromptBuildFailed("schema-aware example selection failed", err)
+		}
+	}
+
+	template := b.createSchemaPromptTemplate(task, selectedExamples)
+	system := template.SystemMessage
+	if system == "" {
+		system = "You are a precise information extraction system that follows JSON schemas exactly."
+	}
+	schema := "JSON Schema for Extractions:\n" + b.buildSchemaString(task.Schema)
+
+	return buildMessages(joinNonEmpty(system, template.Description, schema), selectedExamples, text, b.options.OutputFormat), nil
+}
+
+// buildMessages assembles a conversation from the system message, the
+// examples with their extractions in the output format, and the text.
+func buildMessages(system string, examples []*extraction.ExampleData, text, outputFormat string) []providers.Message {
+	messages := make([]providers.Message, 0, 2*len(examples)+2)
+	messages = append(messages, providers.Message{Role: providers.RoleSystem, Content: system})
+
+	for _, example := range examples {
+		if example == nil {
+			continue
+		}
+		messages = append(messages,
+			providers.Message{Role: providers.RoleUser, Content: "Text: " + example.Text},
+			providers.Message{Role: providers.RoleAssistant, Content: formatExample(example, outputFormat)},
+		)
+	}
+
+	return append(messages, providers.Message{Role: providers.RoleUser, Content: "Text: " + text})
+}
+
+// joinNonEmpty joins the non-empty parts with blank lines
+func joinNonEmpty(parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, "\n\n")
+}
@@ -10,6 +10,8 @@ import (
 type Document struct {
 	Text              string            `json:"text"`                         // Raw text content
 	AdditionalContext string            `json:"additional_context,omitempty"` // Optional context metadata
+	Metadata          map[string]string `json:"metadata,omitempty"`           // Source metadata such as MetadataSourceURL
+	SourceMap         *SourceMap        `json:"-"`                            // Mapping back to the original markup, if converted
 	documentID        string            // Internal document identifier
 	tokenizedText     []string          // Cached tokenized text
 }
@@ -29,6 +31,29 @@ func NewDocumentWithContext(text, context string) *Document {
 	}
 }
 
+// Well-known document metadata keys.
+const (
+	// MetadataSourceURL is the URL a document was fetched from
+	MetadataSourceURL = "source_url"
+
+	// MetadataContentType is the media type of the fetched content
+	MetadataContentType = "content_type"
+)
+
+// SetMetadata sets a metadata value on the document.
+func (d *Document) SetMetadata(key, value string) {
+	if d.Metadata == nil {
+		d.Metadata = make(map[string]string)
+	}
+	d.Metadata[key] = value
+}
+
+// GetMetadata returns a metadata value and whether it is set.
+func (d *Document) GetMetadata(key string) (string, bool) {
+	value, ok := d.Metadata[key]
+	return value, ok
+}
+
 // DocumentID returns the document identifier, generating one if not set.
 func (d *Document) DocumentID() string {
 	if d.documentID == "" {
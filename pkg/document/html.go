@@ -0,0 +1,260 @@
+package document
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sehwan505/langextract-go/pkg/types"
+)
+
+// SourceMap maps byte positions in a document's text back to the markup the
+// text was converted from.
+type SourceMap struct {
+	// Source is the original markup
+	Source string
+
+	starts []int // source start offset for each text byte
+	ends   []int // source end offset for each text byte
+}
+
+// SourceInterval maps an interval of the document text to the span of source
+// markup that produced it. It returns false if the interval is out of range.
+func (m *SourceMap) SourceInterval(interval types.CharInterval) (types.CharInterval, bool) {
+	if interval.StartPos < 0 || interval.EndPos > len(m.starts) || interval.StartPos >= interval.EndPos {
+		return types.CharInterval{}, false
+	}
+	return types.CharInterval{
+		StartPos: m.starts[interval.StartPos],
+		EndPos:   m.ends[interval.EndPos-1],
+	}, true
+}
+
+// skippedElements are elements whose content is not part of the document text.
+var skippedElements = map[string]bool{
+	"head":     true,
+	"script":   true,
+	"style":    true,
+	"noscript": true,
+	"template": true,
+	"svg":      true,
+}
+
+// blockElements are elements that break the text flow, mapped to the break
+// they produce.
+var blockElements = map[string]string{
+	"br": "\n", "li": "\n", "tr": "\n", "dt": "\n", "dd": "\n",
+	"p": "\n\n", "div": "\n\n", "section": "\n\n", "article": "\n\n",
+	"header": "\n\n", "footer": "\n\n", "main": "\n\n", "nav": "\n\n",
+	"aside": "\n\n", "ul": "\n\n", "ol": "\n\n", "dl": "\n\n",
+	"table": "\n\n", "blockquote": "\n\n", "pre": "\n\n", "hr": "\n\n",
+	"h1": "\n\n", "h2": "\n\n", "h3": "\n\n", "h4": "\n\n", "h5": "\n\n", "h6": "\n\n",
+	"form": "\n\n", "figure": "\n\n", "figcaption": "\n", "address": "\n\n",
+}
+
+// NewDocumentFromHTML creates a Document from HTML markup. Tags, comments and
+// non-content elements such as scripts are removed, entities are decoded,
+// whitespace is collapsed and block elements become line breaks. The
+// document's SourceMap maps text positions back to the markup.
+func NewDocumentFromHTML(markup string) *Document {
+	c := &htmlConverter{source: markup}
+	c.convert()
+
+	return &Document{
+		Text: c.text.String(),
+		SourceMap: &SourceMap{
+			Source: markup,
+			starts: c.starts,
+			ends:   c.ends,
+		},
+	}
+}
+
+// htmlConverter converts HTML markup to text while recording offsets.
+type htmlConverter struct {
+	source string
+	text   strings.Builder
+	starts []int
+	ends   []int
+
+	// Pending separator, emitted only if more text follows
+	pending      string
+	pendingStart int
+	pendingEnd   int
+
+	preDepth int
+}
+
+func (c *htmlConverter) convert() {
+	pos := 0
+	for pos < len(c.source) {
+		switch c.source[pos] {
+		case '<':
+			pos = c.tag(pos)
+		case '&':
+			pos = c.entity(pos)
+		default:
+			r, size := utf8.DecodeRuneInString(c.source[pos:])
+			c.char(string(r), r, pos, pos+size)
+			pos += size
+		}
+	}
+}
+
+// emit appends s to the text, mapping every byte to [start, end) in the source.
+func (c *htmlConverter) emit(s string, start, end int) {
+	c.text.WriteString(s)
+	for i := 0; i < len(s); i++ {
+		c.starts = append(c.starts, start)
+		c.ends = append(c.ends, end)
+	}
+}
+
+// char handles a single decoded character produced by source[start:end].
+func (c *htmlConverter) char(s string, r rune, start, end int) {
+	if unicode.IsSpace(r) && c.preDepth == 0 {
+		c.separate(" ", start, end)
+		return
+	}
+
+	if c.pending != "" {
+		if c.text.Len() > 0 {
+			c.emit(c.pending, c.pendingStart, c.pendingEnd)
+		}
+		c.pending = ""
+	}
+	c.emit(s, start, end)
+}
+
+// separate records a pending separator, keeping the strongest one seen.
+func (c *htmlConverter) separate(sep string, start, end int) {
+	if len(sep) > len(c.pending) {
+		c.pending = sep
+		c.pendingStart = start
+		c.pendingEnd = end
+	}
+}
+
+// entity decodes a character reference starting at pos.
+func (c *htmlConverter) entity(pos int) int {
+	limit := pos + 32
+	if limit > len(c.source) {
+		limit = len(c.source)
+	}
+
+	if semi := strings.IndexByte(c.source[pos:limit], ';'); semi > 0 {
+		end := pos + semi + 1
+		ref := c.source[pos:end]
+		if decoded := html.UnescapeString(ref); decoded != ref {
+			for _, r := range decoded {
+				c.char(string(r), r, pos, end)
+			}
+			return end
+		}
+	}
+
+	c.char("&", '&', pos, pos+1)
+	return pos + 1
+}
+
+// tag handles markup starting at pos and returns the position after it.
+func (c *htmlConverter) tag(pos int) int {
+	rest := c.source[pos:]
+
+	switch {
+	case strings.HasPrefix(rest, "<!--"):
+		if end := strings.Index(rest[4:], "-->"); end >= 0 {
+			return pos + 4 + end + 3
+		}
+		return len(c.source)
+	case strings.HasPrefix(rest, "<!"), strings.HasPrefix(rest, "<?"):
+		return c.tagEnd(pos)
+	}
+
+	closing := strings.HasPrefix(rest, "</")
+	nameStart := pos + 1
+	if closing {
+		nameStart++
+	}
+
+	nameEnd := nameStart
+	for nameEnd < len(c.source) && isTagNameByte(c.source[nameEnd]) {
+		nameEnd++
+	}
+	if nameEnd == nameStart || !isASCIILetter(c.source[nameStart]) {
+		// Not a tag; keep the '<' as text
+		c.char("<", '<', pos, pos+1)
+		return pos + 1
+	}
+
+	name := strings.ToLower(c.source[nameStart:nameEnd])
+	end := c.tagEnd(pos)
+	selfClosing := strings.HasSuffix(c.source[pos:end], "/>")
+
+	if !closing && skippedElements[name] && !selfClosing {
+		return c.skipElement(name, end)
+	}
+
+	if name == "pre" && !selfClosing {
+		if closing {
+			if c.preDepth > 0 {
+				c.preDepth--
+			}
+		} else {
+			c.preDepth++
+		}
+	}
+
+	if sep, ok := blockElements[name]; ok {
+		c.separate(sep, pos, end)
+	}
+
+	return end
+}
+
+// tagEnd returns the position after the '>' closing the tag at pos, skipping
+// quoted attribute values.
+func (c *htmlConverter) tagEnd(pos int) int {
+	var quote byte
+	for i := pos + 1; i < len(c.source); i++ {
+		ch := c.source[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '>':
+			return i + 1
+		}
+	}
+	return len(c.source)
+}
+
+// skipElement skips the content of a non-content element up to and including
+// its closing tag.
+func (c *htmlConverter) skipElement(name string, pos int) int {
+	closeTag := "</" + name
+
+	for i := pos; i+len(closeTag) <= len(c.source); i++ {
+		if c.source[i] != '<' || !strings.EqualFold(c.source[i:i+len(closeTag)], closeTag) {
+			continue
+		}
+		after := i + len(closeTag)
+		if after == len(c.source) || !isTagNameByte(c.source[after]) {
+			return c.tagEnd(i)
+		}
+	}
+
+	return len(c.source)
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isTagNameByte(b byte) bool {
+	return isASCIILetter(b) || (b >= '0' && b <= '9') || b == '-'
+}
@@ -255,7 +255,41 @@ func (s *BasicExtractionSchema) validateNumberField(field *FieldDefinition, valu
 }
 
 // ToJSONSchema converts the schema to JSON Schema format.
+// Fields of the classes and global fields become properties of each
+// extraction, as does the confidence the prompts ask for unless a field
+// defines it. A field is required if it is a required global field or
+// required by every class, since a field may only apply to some classes.
 func (s *BasicExtractionSchema) ToJSONSchema() (map[string]interface{}, error) {
+	itemProperties := map[string]interface{}{
+		"extraction_class": map[string]interface{}{
+			"type": "string",
+			"enum": s.GetClasses(),
+		},
+		"extraction_text": map[string]interface{}{
+			"type": "string",
+		},
+	}
+	required := []string{"extraction_class", "extraction_text"}
+
+	fields := append([]*FieldDefinition(nil), s.GlobalFields...)
+	for _, class := range s.Classes {
+		fields = append(fields, class.Fields...)
+	}
+	for _, field := range fields {
+		if _, exists := itemProperties[field.Name]; exists {
+			continue
+		}
+		itemProperties[field.Name] = fieldJSONSchema(field)
+		if s.isRequiredField(field.Name) {
+			required = append(required, field.Name)
+		}
+	}
+	if _, exists := itemProperties["confidence"]; !exists {
+		itemProperties["confidence"] = map[string]interface{}{
+			"type": "number",
+		}
+	}
+
 	schema := map[string]interface{}{
 		"$schema":     "http://json-schema.org/draft-07/schema#",
 		"type":        "object",
@@ -265,17 +299,9 @@ func (s *BasicExtractionSchema) ToJSONSchema() (map[string]interface{}, error) {
 			"extractions": map[string]interface{}{
 				"type": "array",
 				"items": map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"extraction_class": map[string]interface{}{
-							"type": "string",
-							"enum": s.GetClasses(),
-						},
-						"extraction_text": map[string]interface{}{
-							"type": "string",
-						},
-					},
-					"required": []string{"extraction_class", "extraction_text"},
+					"type":       "object",
+					"properties": itemProperties,
+					"required":   required,
 				},
 			},
 		},
@@ -285,6 +311,62 @@ func (s *BasicExtractionSchema) ToJSONSchema() (map[string]interface{}, error) {
 	return schema, nil
 }
 
+// isRequiredField reports whether every extraction must have the field: it
+// is a required global field, or all classes define it as required.
+func (s *BasicExtractionSchema) isRequiredField(name string) bool {
+	for _, field := range s.GlobalFields {
+		if field.Name == name {
+			return field.Required
+		}
+	}
+	if len(s.Classes) == 0 {
+		return false
+	}
+	for _, class := range s.Classes {
+		required := false
+		for _, field := range class.Fields {
+			if field.Name == name {
+				required = field.Required
+				break
+			}
+		}
+		if !required {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldJSONSchema converts a field definition to a JSON Schema property.
+func fieldJSONSchema(field *FieldDefinition) map[string]interface{} {
+	property := make(map[string]interface{})
+	if field.Type != "" {
+		property["type"] = field.Type
+	}
+	if field.Description != "" {
+		property["description"] = field.Description
+	}
+	if len(field.Enum) > 0 {
+		property["enum"] = field.Enum
+	}
+	if field.Pattern != "" {
+		property["pattern"] = field.Pattern
+	}
+	if field.MinLength != nil {
+		property["minLength"] = *field.MinLength
+	}
+	if field.MaxLength != nil {
+		property["maxLength"] = *field.MaxLength
+	}
+	if field.Minimum != nil {
+		property["minimum"] = *field.Minimum
+	}
+	if field.Maximum != nil {
+		property["maximum"] = *field.Maximum
+	}
+	return property
+}
+
 // ToJSON converts the schema to JSON format.
 func (s *BasicExtractionSchema) ToJSON() ([]byte, error) {
 	return json.MarshalIndent(s, "", "  ")
@@ -0,0 +1,248 @@
+package langextract
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sehwan505/langextract-go/pkg/document"
+)
+
+// DocumentResult is the outcome of extracting a single document in a batch.
+type DocumentResult struct {
+	// Index is the position of the document in the batch input
+	Index int
+
+	// Document is the annotated document, nil if extraction failed
+	Document *document.AnnotatedDocument
+
+	// Metadata describes the execution, nil if extraction failed
+	Metadata *ExtractMetadata
+
+	// Error is the extraction error for this document, if any
+	Error error
+}
+
+// BatchResult holds the per-document results of ExtractDocuments.
+type BatchResult struct {
+	// Results are in the same order as the input documents
+	Results []*DocumentResult
+
+	// Succeeded and Failed count documents by outcome
+	Succeeded int
+	Failed    int
+
+	// ExecutionTime is the wall-clock time for the whole batch
+	ExecutionTime time.Duration
+
+	// Spend is the token usage and cost of the batch
+	Spend Spend
+
+	// BudgetExceeded is set when opts.Budget stopped the batch. Documents
+	// that were not extracted fail with ErrBudgetExceeded.
+	BudgetExceeded bool
+}
+
+// Errors returns the errors of failed documents in input order.
+func (br *BatchResult) Errors() []error {
+	var errs []error
+	for _, result := range br.Results {
+		if result.Error != nil {
+			errs = append(errs, result.Error)
+		}
+	}
+	return errs
+}
+
+// ExtractDocuments extracts structured information from multiple documents
+// concurrently, sharing a single Extractor.
+//
+// The input accepts the same types as Extract, and slices of texts, URLs
+// or documents. URLs are downloaded when their document is started, and a
+// failed download fails only that document. At most config.MaxConcurrency
+// documents are processed at once, and their chunk requests share the same
+// limit, so at most config.MaxConcurrency provider requests are in flight;
+// when config is nil the global configuration is used. Each document gets its
+// own opts.Timeout. A failing document does not stop the batch: its error is
+// reported in its DocumentResult.
+//
+// opts.OnDocumentComplete, if set, is called once per document as soon as it
+// finishes. Calls are serialized, so the callback does not need to be
+// safe for concurrent use.
+//
+// Once opts.Budget is exceeded, documents in progress fail at their next
+// provider request and the remaining documents are not started.
+func ExtractDocuments(input TextOrDocuments, opts *ExtractOptions, config *Config) (*BatchResult, error) {
+	startTime := time.Now()
+
+	if opts == nil {
+		opts = NewExtractOptions()
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, NewExtractError("validate_options", "invalid extraction options", err)
+	}
+
+	if config == nil {
+		var err error
+		config, err = GetGlobalConfig()
+		if err != nil {
+			return nil, NewExtractError("load_config", "failed to load configuration", err)
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, NewExtractError("validate_config", "invalid configuration", err)
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	docs, err := parseBatchInput(input)
+	if err != nil {
+		return nil, NewExtractError("parse_input", "failed to parse input", err)
+	}
+
+	if len(docs) == 0 {
+		return nil, NewExtractError("parse_input", "no documents to process", nil)
+	}
+
+	extractor, err := NewExtractor(config, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer extractor.Close()
+
+	results := make([]*DocumentResult, len(docs))
+	var callbackMu sync.Mutex
+	complete := func(result *DocumentResult) {
+		results[result.Index] = result
+		if opts.OnDocumentComplete != nil {
+			callbackMu.Lock()
+			defer callbackMu.Unlock()
+			opts.OnDocumentComplete(result)
+		}
+	}
+
+	sem := make(chan struct{}, config.MaxConcurrency)
+	var wg sync.WaitGroup
+
+	for i, doc := range docs {
+		// Wait for a free slot; documents not started before cancellation
+		// are reported with the context error.
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			complete(&DocumentResult{
+				Index: i,
+				Error: NewExtractError("perform_extraction", "extraction cancelled", ctx.Err()),
+			})
+			continue
+		}
+
+		if opts.Budget.Exceeded() {
+			<-sem
+			complete(&DocumentResult{
+				Index: i,
+				Error: NewExtractError("perform_extraction", "extraction skipped", ErrBudgetExceeded),
+			})
+			continue
+		}
+
+		wg.Add(1)
+		go func(index int, doc batchDocument) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			complete(extractBatchDocument(ctx, extractor, index, doc, opts.URLFetcher))
+		}(i, doc)
+	}
+
+	wg.Wait()
+
+	batch := &BatchResult{
+		Results:        results,
+		ExecutionTime:  time.Since(startTime),
+		Spend:          extractor.Spend(),
+		BudgetExceeded: opts.Budget.Exceeded(),
+	}
+	for _, result := range results {
+		if result.Error != nil {
+			batch.Failed++
+		} else {
+			batch.Succeeded++
+		}
+	}
+
+	return batch, nil
+}
+
+// batchDocument is a document of a batch input, or the URL to download it
+// from when the batch starts it.
+type batchDocument struct {
+	doc *document.Document
+	url string
+}
+
+// parseBatchInput converts the input of ExtractDocuments to batch documents
+// without downloading URLs, so that a failed download only fails its own
+// document.
+func parseBatchInput(input TextOrDocuments) ([]batchDocument, error) {
+	var texts []string
+	switch v := input.(type) {
+	case string:
+		texts = []string{v}
+	case []string:
+		texts = v
+	default:
+		docs, err := parseInput(context.Background(), input, nil)
+		if err != nil {
+			return nil, err
+		}
+		batch := make([]batchDocument, len(docs))
+		for i, doc := range docs {
+			batch[i] = batchDocument{doc: doc}
+		}
+		return batch, nil
+	}
+
+	batch := make([]batchDocument, len(texts))
+	for i, text := range texts {
+		if isURL(text) {
+			batch[i] = batchDocument{url: text}
+		} else {
+			batch[i] = batchDocument{doc: document.NewDocument(text)}
+		}
+	}
+	return batch, nil
+}
+
+// extractBatchDocument extracts a single document of a batch with its own
+// timeout, downloading it first if it is a URL.
+func extractBatchDocument(ctx context.Context, extractor *Extractor, index int, input batchDocument, fetcher *URLFetcher) *DocumentResult {
+	doc := input.doc
+	if input.url != "" {
+		var err error
+		if doc, err = textOrURLDocument(ctx, input.url, fetcher); err != nil {
+			return &DocumentResult{
+				Index: index,
+				Error: err,
+			}
+		}
+	}
+
+	result, err := extractor.Extract(ctx, doc)
+	if err != nil {
+		return &DocumentResult{
+			Index: index,
+			Error: err,
+		}
+	}
+
+	return &DocumentResult{
+		Index:    index,
+		Document: result.Document,
+		Metadata: result.Metadata,
+	}
+}
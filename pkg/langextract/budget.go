@@ -0,0 +1,34 @@
+package langextract
+
+import (
+	"github.com/sehwan505/langextract-go/internal/engine"
+)
+
+// Budget tracks the token usage and cost of the extractions that share it
+// and stops them once its limits are reached. Share one Budget through
+// ExtractOptions.Budget to cap a whole run, such as a batch of documents.
+//
+// Costs are estimated from the model catalog. Before each provider request,
+// the prompt is estimated and reserved; a request that does not fit fails
+// with ErrBudgetExceeded, and so does all later work. Requests in flight
+// when the limit is reached still complete, so the budget can be overshot
+// by their output.
+type Budget = engine.Budget
+
+// BudgetLimits caps the cost and tokens of a Budget. Zero limits are
+// unlimited.
+type BudgetLimits = engine.BudgetLimits
+
+// Spend aggregates the token usage and cost of provider requests.
+type Spend = engine.Spend
+
+// ErrBudgetExceeded is returned for extractions stopped by their Budget.
+var ErrBudgetExceeded = engine.ErrBudgetExceeded
+
+// NewBudget creates a budget with the given limits.
+//
+//	budget := langextract.NewBudget(langextract.BudgetLimits{MaxCostUSD: 5})
+//	opts := langextract.NewExtractOptions().WithBudget(budget)
+func NewBudget(limits BudgetLimits) *Budget {
+	return engine.NewBudget(limits)
+}
@@ -3,20 +3,24 @@ package langextract
 import (
 	"bufio"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
 )
 
 // Config represents global library configuration.
 // This mirrors the configuration approach from Google's langextract Python library.
 type Config struct {
 	// API Keys for different providers
-	OpenAIAPIKey string
-	GeminiAPIKey string
-	OllamaURL    string
+	OpenAIAPIKey    string
+	GeminiAPIKey    string
+	AnthropicAPIKey string
+	OllamaURL       string
 
 	// Default provider settings
 	DefaultModelID   string
@@ -31,6 +35,14 @@ type Config struct {
 	LogLevel         string
 	MaxConcurrency   int
 	ConfigFilePath   string
+
+	// HTTP configures the transport of the built-in providers, such as a
+	// proxy or CA bundle, for model configs that do not set their own
+	HTTP *providers.HTTPConfig
+
+	// HTTPClient sends the requests of the built-in providers, for model
+	// configs that do not set their own
+	HTTPClient *http.Client
 }
 
 // DefaultConfig returns a Config with sensible defaults.
@@ -93,6 +105,9 @@ func (c *Config) loadFromEnvironment() error {
 	if key := os.Getenv("GOOGLE_API_KEY"); key != "" {
 		c.GeminiAPIKey = key
 	}
+	if key := os.Getenv("ANTHROPIC_API_KEY"); key != "" {
+		c.AnthropicAPIKey = key
+	}
 	if url := os.Getenv("OLLAMA_URL"); url != "" {
 		c.OllamaURL = url
 	}
@@ -141,6 +156,14 @@ func (c *Config) loadFromEnvironment() error {
 		c.ConfigFilePath = configFile
 	}
 
+	// HTTP transport settings
+	if proxyURL := os.Getenv("LANGEXTRACT_PROXY_URL"); proxyURL != "" {
+		c.httpConfig().ProxyURL = proxyURL
+	}
+	if caFile := os.Getenv("LANGEXTRACT_CA_FILE"); caFile != "" {
+		c.httpConfig().CAFile = caFile
+	}
+
 	return nil
 }
 
@@ -256,6 +279,8 @@ func (c *Config) applyConfigValue(key, value string) {
 		c.OpenAIAPIKey = value
 	case "GEMINI_API_KEY", "GOOGLE_API_KEY":
 		c.GeminiAPIKey = value
+	case "ANTHROPIC_API_KEY":
+		c.AnthropicAPIKey = value
 	case "OLLAMA_URL":
 		c.OllamaURL = value
 	case "LANGEXTRACT_MODEL_ID":
@@ -286,7 +311,19 @@ func (c *Config) applyConfigValue(key, value string) {
 		if concurrency, err := strconv.Atoi(value); err == nil {
 			c.MaxConcurrency = concurrency
 		}
+	case "LANGEXTRACT_PROXY_URL":
+		c.httpConfig().ProxyURL = value
+	case "LANGEXTRACT_CA_FILE":
+		c.httpConfig().CAFile = value
+	}
+}
+
+// httpConfig returns the HTTP configuration, creating it if unset.
+func (c *Config) httpConfig() *providers.HTTPConfig {
+	if c.HTTP == nil {
+		c.HTTP = &providers.HTTPConfig{}
 	}
+	return c.HTTP
 }
 
 // Validate checks if the configuration is valid.
@@ -324,6 +361,8 @@ func (c *Config) HasProviderCredentials(provider string) bool {
 		return c.OpenAIAPIKey != ""
 	case "gemini":
 		return c.GeminiAPIKey != ""
+	case "anthropic":
+		return c.AnthropicAPIKey != ""
 	case "ollama":
 		return c.OllamaURL != ""
 	default:
@@ -338,6 +377,8 @@ func (c *Config) GetAPIKey(provider string) string {
 		return c.OpenAIAPIKey
 	case "gemini":
 		return c.GeminiAPIKey
+	case "anthropic":
+		return c.AnthropicAPIKey
 	default:
 		return ""
 	}
@@ -38,7 +38,7 @@ func (v *ValidationError) Error() string {
 
 // ProviderError represents errors from language model providers.
 type ProviderError struct {
-	Provider string // Provider name (openai, gemini, ollama)
+	Provider string // Provider name (openai, gemini, anthropic, ollama)
 	Status   string // HTTP status or error code
 	Message  string // Provider error message
 	Err      error  // Underlying error
@@ -69,6 +69,50 @@ func (a *AlignmentError) Error() string {
 	return fmt.Sprintf("alignment error: failed to align %q in source: %s", a.ExtractedText, a.Message)
 }
 
+// FieldError represents a failure to decode an extraction attribute into a
+// struct field.
+type FieldError struct {
+	Index     int         // Index of the extraction in the document
+	Class     string      // Extraction class
+	Field     string      // Go struct field name
+	Attribute string      // Extraction attribute name
+	Value     interface{} // Attribute value, nil if missing
+	Err       error       // Underlying error
+}
+
+// Error implements the error interface.
+func (f *FieldError) Error() string {
+	return fmt.Sprintf("extraction %d (%s): field %s (attribute %q): %v", f.Index, f.Class, f.Field, f.Attribute, f.Err)
+}
+
+// Unwrap returns the underlying error.
+func (f *FieldError) Unwrap() error {
+	return f.Err
+}
+
+// DecodeError collects the field errors from decoding extractions into structs.
+type DecodeError struct {
+	Errors []*FieldError
+}
+
+// Error implements the error interface.
+func (d *DecodeError) Error() string {
+	if len(d.Errors) == 1 {
+		return fmt.Sprintf("decode error: %v", d.Errors[0])
+	}
+	return fmt.Sprintf("decode error: %d field errors, first: %v", len(d.Errors), d.Errors[0])
+}
+
+// Unwrap returns the field errors, so that errors.Is and errors.As match any
+// of them.
+func (d *DecodeError) Unwrap() []error {
+	errs := make([]error, len(d.Errors))
+	for i, err := range d.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
 // Error creation helpers
 
 // NewExtractError creates a new ExtractError.
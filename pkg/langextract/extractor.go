@@ -0,0 +1,210 @@
+package langextract
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/sehwan505/langextract-go/internal/chunking"
+	"github.com/sehwan505/langextract-go/internal/engine"
+	"github.com/sehwan505/langextract-go/pkg/document"
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// ErrExtractorClosed is returned by Extractor.Extract after Close.
+var ErrExtractorClosed = NewExtractError("extract", "extractor is closed", nil)
+
+// Extractor is a long-lived extraction client.
+//
+// An Extractor creates its language model provider, extraction engine and
+// chunker once and reuses them for every document, so HTTP connections and,
+// if Config.EnableCaching is set, the engine's response cache are shared
+// between calls. The engine builds the
+// prompts and holds the aligner used for grounding.
+//
+// An Extractor is safe for concurrent use by multiple goroutines. Call Close
+// when it is no longer needed.
+//
+//	extractor, err := langextract.NewExtractor(nil, opts)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer extractor.Close()
+//
+//	result, err := extractor.Extract(ctx, document.NewDocument(text))
+type Extractor struct {
+	opts     *ExtractOptions
+	provider providers.BaseLanguageModel
+	engine   *engine.ExtractionEngine
+	chunker  chunking.TextChunker
+
+	// ownsProvider is set when the provider was created by the Extractor
+	// rather than supplied through ExtractOptions.Provider
+	ownsProvider bool
+
+	// mu is held for reading by in-flight extractions and for writing by Close
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewExtractor creates an Extractor from a configuration and extraction options.
+//
+// When config is nil the global configuration is used. config.MaxConcurrency
+// limits how many chunk requests are sent to the provider concurrently,
+// across all documents the Extractor is extracting.
+//
+// The options are copied, so later changes to opts do not affect the Extractor.
+// A provider supplied through opts.Provider is shared, however: the options'
+// schema, if any, is applied to it, replacing the schema that other Extractors
+// using the same provider applied.
+func NewExtractor(config *Config, opts *ExtractOptions) (*Extractor, error) {
+	if config == nil {
+		var err error
+		config, err = GetGlobalConfig()
+		if err != nil {
+			return nil, NewExtractError("load_config", "failed to load configuration", err)
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, NewExtractError("validate_config", "invalid configuration", err)
+	}
+
+	if opts == nil {
+		opts = NewExtractOptions()
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, NewExtractError("validate_options", "invalid extraction options", err)
+	}
+
+	optsCopy := *opts
+
+	provider, err := createProvider(&optsCopy, config)
+	if err != nil {
+		return nil, NewExtractError("create_provider", "failed to create language model provider", err)
+	}
+
+	if err := applySchema(provider, &optsCopy); err != nil {
+		return nil, NewExtractError("perform_extraction", "extraction failed", err)
+	}
+
+	engineConfig := newEngineConfig(&optsCopy)
+	engineConfig.MaxConcurrentRequests = config.MaxConcurrency
+	engineConfig.ProviderConfig = engine.DefaultProviderManagerConfig()
+	engineConfig.ProviderConfig.EnableCaching = config.EnableCaching
+
+	return &Extractor{
+		opts:         &optsCopy,
+		provider:     provider,
+		engine:       engine.NewExtractionEngine(engineConfig),
+		chunker:      optsCopy.ChunkingStrategy.newChunker(),
+		ownsProvider: opts.Provider == nil,
+	}, nil
+}
+
+// Extract extracts structured information from a document. The extraction is
+// bounded by ctx and by the Timeout of the Extractor's options.
+func (e *Extractor) Extract(ctx context.Context, doc *document.Document) (*ExtractResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if e.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.opts.Timeout)
+		defer cancel()
+	}
+
+	return e.extract(ctx, doc)
+}
+
+// Close releases the resources held by the Extractor. It waits for in-flight
+// extractions to finish; later calls to Extract return ErrExtractorClosed.
+// A provider supplied through ExtractOptions.Provider is not closed.
+func (e *Extractor) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+
+	e.engine.Close()
+
+	if closer, ok := e.provider.(io.Closer); ok && e.ownsProvider {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Provider returns the language model provider used by the Extractor.
+func (e *Extractor) Provider() providers.BaseLanguageModel {
+	return e.provider
+}
+
+// Spend returns the token usage and cost of all provider requests made by
+// the Extractor.
+func (e *Extractor) Spend() Spend {
+	return e.engine.Spend()
+}
+
+// extract processes a single document without applying the options timeout.
+func (e *Extractor) extract(ctx context.Context, doc *document.Document) (*ExtractResult, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.closed {
+		return nil, ErrExtractorClosed
+	}
+
+	if doc == nil {
+		return nil, NewExtractError("parse_input", "document cannot be nil", nil)
+	}
+
+	response, err := e.engine.ProcessExtraction(e.newRequest(ctx, doc))
+	if err != nil {
+		return nil, NewExtractError("perform_extraction", "extraction failed", err)
+	}
+
+	if e.opts.DebugMode {
+		for _, step := range response.DebugInfo.ProcessingSteps {
+			log.Printf("Step %s [%s]: %s (%v)", step.Name, step.Status, step.Message, step.Duration)
+		}
+	}
+
+	return &ExtractResult{
+		Document: response.AnnotatedDocument,
+		Metadata: newExtractMetadata(response),
+	}, nil
+}
+
+// newRequest builds an engine extraction request for a document.
+func (e *Extractor) newRequest(ctx context.Context, doc *document.Document) *engine.ExtractionRequest {
+	opts := e.opts
+
+	request := engine.NewExtractionRequest(doc, opts.PromptDescription)
+	request.Examples = opts.Examples
+	request.Schema = opts.Schema
+	request.Provider = e.provider
+	request.ModelID = e.provider.GetModelID()
+	request.ModelConfig = opts.ModelConfig
+	request.MaxTokens = opts.MaxTokens
+	request.Temperature = opts.Temperature
+	request.Timeout = opts.Timeout
+	request.RetryCount = opts.RetryCount
+	request.ValidateOutput = opts.ValidateOutput
+	request.ExtractionPasses = opts.ExtractionPasses
+	request.ParallelProcessing = opts.ParallelProcessing
+	request.AlignmentOptions = opts.AlignmentOptions
+	request.UngroundedPolicy = opts.UngroundedPolicy
+	request.Chunker = e.chunker
+	request.ChunkingOptions = opts.ChunkingOptions
+	request.Budget = opts.Budget
+	request.Context = ctx
+	if opts.ModelConfig != nil {
+		request.ProviderID = opts.ModelConfig.Provider
+	}
+	return request
+}
@@ -32,10 +32,9 @@ import (
 	"log"
 	"net/url"
 	"strings"
-	"time"
 
+	"github.com/sehwan505/langextract-go/internal/engine"
 	"github.com/sehwan505/langextract-go/pkg/document"
-	"github.com/sehwan505/langextract-go/pkg/extraction"
 	"github.com/sehwan505/langextract-go/pkg/providers"
 )
 
@@ -47,12 +46,26 @@ type TextOrDocuments interface{}
 //
 // The input can be:
 //   - A string of text
-//   - A URL (must start with http:// or https://)
+//   - A URL (must start with http:// or https://), downloaded with
+//     opts.URLFetcher
 //   - A Document object
-//   - A slice of any of the above
+//
+// Multiple documents must be processed with ExtractDocuments. The global
+// configuration applies, see GetGlobalConfig.
 //
 // Returns an AnnotatedDocument with extracted entities and their source grounding.
 func Extract(input TextOrDocuments, opts *ExtractOptions) (*document.AnnotatedDocument, error) {
+	result, err := ExtractWithMetadata(input, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Document, nil
+}
+
+// ExtractWithMetadata behaves like Extract but also returns execution metadata
+// from the extraction engine, such as token usage, completed passes, the
+// provider used and the individual processing steps.
+func ExtractWithMetadata(input TextOrDocuments, opts *ExtractOptions) (*ExtractResult, error) {
 	if opts == nil {
 		opts = NewExtractOptions()
 	}
@@ -64,6 +77,9 @@ func Extract(input TextOrDocuments, opts *ExtractOptions) (*document.AnnotatedDo
 
 	// Set up context with timeout
 	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	if opts.Timeout > 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
@@ -71,7 +87,7 @@ func Extract(input TextOrDocuments, opts *ExtractOptions) (*document.AnnotatedDo
 	}
 
 	// Convert input to documents
-	docs, err := parseInput(input)
+	docs, err := parseInput(ctx, input, opts.URLFetcher)
 	if err != nil {
 		return nil, NewExtractError("parse_input", "failed to parse input", err)
 	}
@@ -82,10 +98,13 @@ func Extract(input TextOrDocuments, opts *ExtractOptions) (*document.AnnotatedDo
 	}
 
 	if len(docs) > 1 {
-		return nil, NewExtractError("multi_document", "multi-document extraction not yet implemented", nil)
+		return nil, NewExtractError("multi_document", "multiple documents provided; use ExtractDocuments", nil)
 	}
 
 	doc := docs[0]
+	if doc == nil {
+		return nil, NewExtractError("parse_input", "document cannot be nil", nil)
+	}
 
 	// Validate required parameters
 	if len(opts.Examples) == 0 {
@@ -94,19 +113,16 @@ func Extract(input TextOrDocuments, opts *ExtractOptions) (*document.AnnotatedDo
 		}
 	}
 
-	// Create provider
-	provider, err := createProvider(opts)
-	if err != nil {
-		return nil, NewExtractError("create_provider", "failed to create language model provider", err)
-	}
-
-	// Perform extraction
-	annotatedDoc, err := performExtraction(ctx, doc, provider, opts)
+	// The global configuration supplies the HTTP settings, such as a proxy or
+	// CA bundle; long-lived callers should create an Extractor once and
+	// reuse it
+	extractor, err := NewExtractor(nil, opts)
 	if err != nil {
-		return nil, NewExtractError("perform_extraction", "extraction failed", err)
+		return nil, err
 	}
+	defer extractor.Close()
 
-	return annotatedDoc, nil
+	return extractor.extract(ctx, doc)
 }
 
 // Visualize generates visualization output for extracted data.
@@ -138,15 +154,16 @@ func Visualize(doc *document.AnnotatedDocument, opts *VisualizeOptions) (string,
 	}
 }
 
-// parseInput converts various input types to Document objects.
-func parseInput(input TextOrDocuments) ([]*document.Document, error) {
+// parseInput converts various input types to Document objects. URL inputs
+// are downloaded with the fetcher.
+func parseInput(ctx context.Context, input TextOrDocuments, fetcher *URLFetcher) ([]*document.Document, error) {
 	switch v := input.(type) {
 	case string:
-		// Check if it's a URL
-		if isURL(v) {
-			return nil, NewExtractError("url_input", "URL input not yet implemented", nil)
+		doc, err := textOrURLDocument(ctx, v, fetcher)
+		if err != nil {
+			return nil, err
 		}
-		return []*document.Document{document.NewDocument(v)}, nil
+		return []*document.Document{doc}, nil
 
 	case *document.Document:
 		return []*document.Document{v}, nil
@@ -157,10 +174,11 @@ func parseInput(input TextOrDocuments) ([]*document.Document, error) {
 	case []string:
 		docs := make([]*document.Document, len(v))
 		for i, text := range v {
-			if isURL(text) {
-				return nil, NewExtractError("url_input", "URL input not yet implemented", nil)
+			doc, err := textOrURLDocument(ctx, text, fetcher)
+			if err != nil {
+				return nil, err
 			}
-			docs[i] = document.NewDocument(text)
+			docs[i] = doc
 		}
 		return docs, nil
 
@@ -169,6 +187,24 @@ func parseInput(input TextOrDocuments) ([]*document.Document, error) {
 	}
 }
 
+// textOrURLDocument creates a document from text, downloading it first if the
+// text is a URL.
+func textOrURLDocument(ctx context.Context, text string, fetcher *URLFetcher) (*document.Document, error) {
+	if !isURL(text) {
+		return document.NewDocument(text), nil
+	}
+
+	if fetcher == nil {
+		fetcher = NewURLFetcher()
+	}
+
+	doc, err := fetcher.Fetch(ctx, text)
+	if err != nil {
+		return nil, NewExtractError("url_input", "failed to fetch URL input", err)
+	}
+	return doc, nil
+}
+
 // isURL checks if a string looks like a URL.
 func isURL(s string) bool {
 	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
@@ -178,12 +214,21 @@ func isURL(s string) bool {
 	return false
 }
 
-// createProvider creates a language model provider based on options.
-func createProvider(opts *ExtractOptions) (providers.BaseLanguageModel, error) {
+// createProvider creates a language model provider based on options. The
+// HTTP settings of the library config apply to model configs that do not
+// set their own, and RetryCount sets the retries of a model config without a
+// retry policy.
+func createProvider(opts *ExtractOptions, libConfig *Config) (providers.BaseLanguageModel, error) {
+	if opts.Provider != nil {
+		return opts.Provider, nil
+	}
+
 	var config *providers.ModelConfig
 	
 	if opts.ModelConfig != nil {
-		config = opts.ModelConfig
+		// Copy the config, which may be shared by concurrent extractions
+		copied := *opts.ModelConfig
+		config = &copied
 	} else {
 		config = providers.NewModelConfig(opts.ModelID)
 	}
@@ -195,12 +240,23 @@ func createProvider(opts *ExtractOptions) (providers.BaseLanguageModel, error) {
 	if opts.MaxTokens > 0 {
 		config = config.WithMaxTokens(opts.MaxTokens)
 	}
+	if config.Retry == nil {
+		policy := providers.DefaultRetryPolicy()
+		policy.MaxRetries = opts.RetryCount
+		config.Retry = policy
+	}
+	if libConfig != nil {
+		if config.HTTP == nil && config.ProviderKwargs["http"] == nil {
+			config.HTTP = libConfig.HTTP
+		}
+		if config.HTTPClient == nil && config.ProviderKwargs["http_client"] == nil {
+			config.HTTPClient = libConfig.HTTPClient
+		}
+	}
 
-	// Create provider using registry
-	registry := providers.NewProviderRegistry()
-	providers.RegisterDefaultProviders(registry)
-
-	provider, err := registry.CreateModel(config)
+	// Create provider using the default registry, which includes providers
+	// registered by the application such as OpenAI-compatible servers
+	provider, err := providers.CreateModel(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create provider for model %s: %w", opts.ModelID, err)
 	}
@@ -213,225 +269,31 @@ func createProvider(opts *ExtractOptions) (providers.BaseLanguageModel, error) {
 	return provider, nil
 }
 
-// performExtraction executes the extraction process.
-func performExtraction(ctx context.Context, doc *document.Document, provider providers.BaseLanguageModel, opts *ExtractOptions) (*document.AnnotatedDocument, error) {
-	// Create annotated document
-	annotatedDoc := document.NewAnnotatedDocument(doc)
-
-	// Build prompt
-	prompt, err := buildPrompt(doc.Text, opts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build prompt: %w", err)
-	}
-
-	if opts.DebugMode {
-		log.Printf("Generated prompt: %s", prompt)
-	}
-
-	// Apply schema if provided
-	if opts.Schema != nil {
-		jsonSchema, err := opts.Schema.ToJSONSchema()
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert schema: %w", err)
-		}
-		provider.ApplySchema(jsonSchema)
-	}
-
-	// Perform extraction with retries
-	var lastErr error
-	for attempt := 0; attempt <= opts.RetryCount; attempt++ {
-		if attempt > 0 {
-			if opts.DebugMode {
-				log.Printf("Retry attempt %d/%d", attempt, opts.RetryCount)
-			}
-			time.Sleep(time.Duration(attempt) * time.Second) // Exponential backoff
-		}
-
-		// Check context cancellation
-		if ctx.Err() != nil {
-			return nil, ctx.Err()
-		}
-
-		// Call provider
-		results, err := provider.Infer(ctx, []string{prompt}, nil)
-		if err != nil {
-			lastErr = err
-			if opts.DebugMode {
-				log.Printf("Provider error (attempt %d): %v", attempt+1, err)
-			}
-			continue
-		}
-
-		if len(results) == 0 || len(results[0]) == 0 {
-			lastErr = fmt.Errorf("no results returned from provider")
-			continue
-		}
-
-		// Parse the response
-		response := results[0][0].Output
-		if opts.DebugMode {
-			log.Printf("Provider response: %s", response)
-		}
-
-		// Parse extractions from response
-		extractions, err := parseExtractions(response, doc.Text, provider)
-		if err != nil {
-			lastErr = fmt.Errorf("failed to parse extractions: %w", err)
-			if opts.DebugMode {
-				log.Printf("Parse error: %v", err)
-			}
-			continue
-		}
-
-		// Validate extractions if schema is provided
-		if opts.ValidateOutput && opts.Schema != nil {
-			validExtractions := make([]*extraction.Extraction, 0, len(extractions))
-			for _, ext := range extractions {
-				if err := opts.Schema.ValidateExtraction(ext); err != nil {
-					if opts.DebugMode {
-						log.Printf("Validation failed for extraction %s: %v", ext.ExtractionText, err)
-					}
-					continue
-				}
-				validExtractions = append(validExtractions, ext)
-			}
-			extractions = validExtractions
-		}
+// newEngineConfig derives the extraction engine configuration from options.
+func newEngineConfig(opts *ExtractOptions) *engine.ExtractionEngineConfig {
+	config := engine.DefaultExtractionEngineConfig()
+	config.DefaultTimeout = opts.Timeout
+	config.EnableDebugMode = opts.DebugMode
+	config.EnableProgressTracking = false
 
-		// Add extractions to document
-		annotatedDoc.AddExtractions(extractions)
+	// Model-reported confidences are kept as-is; filtering is left to callers.
+	config.ConfidenceThreshold = 0.0
 
-		if opts.DebugMode {
-			log.Printf("Successfully extracted %d entities", len(extractions))
-		}
-
-		return annotatedDoc, nil
-	}
-
-	return nil, fmt.Errorf("extraction failed after %d retries: %w", opts.RetryCount+1, lastErr)
+	return config
 }
 
-// buildPrompt constructs the prompt for the language model.
-func buildPrompt(text string, opts *ExtractOptions) (string, error) {
-	var prompt strings.Builder
-
-	// Add task description
-	prompt.WriteString("Extract structured information from the following text.\n\n")
-	
-	if opts.PromptDescription != "" {
-		prompt.WriteString("Task: ")
-		prompt.WriteString(opts.PromptDescription)
-		prompt.WriteString("\n\n")
-	}
-
-	// Add examples if provided
-	if len(opts.Examples) > 0 {
-		prompt.WriteString("Examples:\n")
-		for i, example := range opts.Examples {
-			prompt.WriteString(fmt.Sprintf("\nExample %d:\n", i+1))
-			prompt.WriteString("Text: ")
-			prompt.WriteString(example.Text)
-			prompt.WriteString("\n")
-			
-			if len(example.Extractions) > 0 {
-				prompt.WriteString("Extractions:\n")
-				for _, ext := range example.Extractions {
-					prompt.WriteString(fmt.Sprintf("- %s: %s\n", ext.ExtractionClass, ext.ExtractionText))
-				}
-			}
-		}
-		prompt.WriteString("\n")
-	}
-
-	// Add schema information if provided
-	if opts.Schema != nil {
-		prompt.WriteString("Expected extraction classes: ")
-		classes := opts.Schema.GetClasses()
-		prompt.WriteString(strings.Join(classes, ", "))
-		prompt.WriteString("\n\n")
+// applySchema applies the extraction schema, if any, to the provider.
+func applySchema(provider providers.BaseLanguageModel, opts *ExtractOptions) error {
+	if opts.Schema == nil {
+		return nil
 	}
 
-	// Add the text to process
-	prompt.WriteString("Text to process:\n")
-	prompt.WriteString(text)
-	prompt.WriteString("\n\n")
-
-	// Add format instructions
-	prompt.WriteString("Please extract entities in the following JSON format:\n")
-	prompt.WriteString("{\n")
-	prompt.WriteString("  \"extractions\": [\n")
-	prompt.WriteString("    {\n")
-	prompt.WriteString("      \"extraction_class\": \"class_name\",\n")
-	prompt.WriteString("      \"extraction_text\": \"extracted_text\",\n")
-	prompt.WriteString("      \"confidence\": 0.95\n")
-	prompt.WriteString("    }\n")
-	prompt.WriteString("  ]\n")
-	prompt.WriteString("}")
-
-	return prompt.String(), nil
-}
-
-// parseExtractions parses the model response into Extraction objects.
-func parseExtractions(response, sourceText string, provider providers.BaseLanguageModel) ([]*extraction.Extraction, error) {
-	// Parse JSON response
-	parsed, err := provider.ParseOutput(response)
+	jsonSchema, err := opts.Schema.ToJSONSchema()
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse provider response: %w", err)
-	}
-
-	// Convert to map
-	data, ok := parsed.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("expected JSON object, got %T", parsed)
-	}
-
-	// Get extractions array
-	extractionsData, ok := data["extractions"]
-	if !ok {
-		return nil, fmt.Errorf("no 'extractions' field found in response")
-	}
-
-	extractionsArray, ok := extractionsData.([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("'extractions' field is not an array")
+		return fmt.Errorf("failed to convert schema: %w", err)
 	}
-
-	// Parse each extraction
-	extractions := make([]*extraction.Extraction, 0, len(extractionsArray))
-	for _, item := range extractionsArray {
-		itemMap, ok := item.(map[string]interface{})
-		if !ok {
-			continue // Skip invalid items
-		}
-
-		class, _ := itemMap["extraction_class"].(string)
-		text, _ := itemMap["extraction_text"].(string)
-		
-		if class == "" || text == "" {
-			continue // Skip incomplete extractions
-		}
-
-		ext := extraction.NewExtraction(class, text)
-
-		// Add confidence if present
-		if conf, ok := itemMap["confidence"].(float64); ok {
-			ext.SetConfidence(conf)
-		}
-
-		// Add other attributes
-		for key, value := range itemMap {
-			if key != "extraction_class" && key != "extraction_text" && key != "confidence" {
-				ext.AddAttribute(key, value)
-			}
-		}
-
-		// TODO: Add text alignment to find source positions
-		// This will be implemented when the alignment package is created
-
-		extractions = append(extractions, ext)
-	}
-
-	return extractions, nil
+	provider.ApplySchema(jsonSchema)
+	return nil
 }
 
 // Visualize generates visualizations from annotated documents.
@@ -4,6 +4,9 @@ import (
 	"context"
 	"time"
 
+	"github.com/sehwan505/langextract-go/internal/alignment"
+	"github.com/sehwan505/langextract-go/internal/chunking"
+	"github.com/sehwan505/langextract-go/internal/engine"
 	"github.com/sehwan505/langextract-go/pkg/extraction"
 	"github.com/sehwan505/langextract-go/pkg/providers"
 )
@@ -24,11 +27,19 @@ type ExtractOptions struct {
 	// ModelConfig provides model-specific configuration
 	ModelConfig *providers.ModelConfig
 
-	// ExtractionPasses controls how many sequential extraction attempts to make
+	// Provider supplies a preconstructed language model, bypassing
+	// ModelID/ModelConfig based provider creation. If Schema is set, it is
+	// applied to the provider with ApplySchema, which also changes the schema
+	// for other users of the same provider; give each schema its own provider.
+	Provider providers.BaseLanguageModel
+
+	// ExtractionPasses controls how many sequential extraction attempts to make.
+	// Later passes only add extractions that do not overlap earlier ones.
 	// Default: 1
 	ExtractionPasses int
 
-	// ParallelProcessing enables concurrent processing for large documents
+	// ParallelProcessing enables concurrent processing for large documents by
+	// sending the chunks of a chunked document to the provider concurrently
 	// Default: false
 	ParallelProcessing bool
 
@@ -54,15 +65,125 @@ type ExtractOptions struct {
 	// Default: true
 	ValidateOutput bool
 
-	// RetryCount specifies number of retries on failure
+	// RetryCount specifies number of retries of failed provider requests.
+	// It sets MaxRetries of the provider's retry policy, unless ModelConfig
+	// sets one; a Provider set by the caller retries with its own policy.
 	// Default: 2
 	RetryCount int
 
 	// DebugMode enables detailed logging and debugging
 	// Default: false
 	DebugMode bool
+
+	// AlignmentOptions configures how extractions are grounded in the source text
+	// Default: DefaultAlignmentOptions()
+	AlignmentOptions *AlignmentOptions
+
+	// UngroundedPolicy controls what happens to extractions that cannot be
+	// aligned to the source text
+	// Default: UngroundedKeep
+	UngroundedPolicy UngroundedPolicy
+
+	// ChunkingStrategy selects how long documents are split into chunks that
+	// are extracted separately
+	// Default: ChunkingNone (the whole document is sent in one request)
+	ChunkingStrategy ChunkingStrategy
+
+	// ChunkingOptions configures chunk size and overlap when a chunking
+	// strategy is set
+	// Default: DefaultChunkingOptions()
+	ChunkingOptions *ChunkingOptions
+
+	// URLFetcher downloads URL inputs
+	// Default: NewURLFetcher()
+	URLFetcher *URLFetcher
+
+	// OnDocumentComplete is called by ExtractDocuments as each document finishes
+	OnDocumentComplete func(result *DocumentResult)
+
+	// Budget caps the cost and tokens of the extractions using these options.
+	// Extractions stop with ErrBudgetExceeded once it is reached.
+	// Default: nil (unlimited)
+	Budget *Budget
+}
+
+// ChunkingStrategy names the text chunker used to split long documents.
+type ChunkingStrategy string
+
+const (
+	// ChunkingNone disables chunking
+	ChunkingNone ChunkingStrategy = ""
+
+	// ChunkingSimple splits on sentence boundaries up to the chunk size
+	ChunkingSimple ChunkingStrategy = "simple"
+
+	// ChunkingSemantic splits on paragraph and section boundaries
+	ChunkingSemantic ChunkingStrategy = "semantic"
+
+	// ChunkingAdaptive sizes chunks according to content complexity
+	ChunkingAdaptive ChunkingStrategy = "adaptive"
+)
+
+// IsValid checks if the strategy is a known chunking strategy.
+func (s ChunkingStrategy) IsValid() bool {
+	switch s {
+	case ChunkingNone, ChunkingSimple, ChunkingSemantic, ChunkingAdaptive:
+		return true
+	default:
+		return false
+	}
 }
 
+// newChunker returns the text chunker for the strategy, or nil for ChunkingNone.
+func (s ChunkingStrategy) newChunker() chunking.TextChunker {
+	switch s {
+	case ChunkingSimple:
+		return chunking.NewSimpleChunker()
+	case ChunkingSemantic:
+		return chunking.NewSemanticChunker()
+	case ChunkingAdaptive:
+		return chunking.NewAdaptiveChunker()
+	default:
+		return nil
+	}
+}
+
+// ChunkingOptions configures how documents are split into chunks.
+type ChunkingOptions = chunking.ChunkingOptions
+
+// DefaultChunkingOptions returns the default chunking configuration:
+// chunks of up to 1000 characters with 10% overlap.
+func DefaultChunkingOptions() ChunkingOptions {
+	return chunking.DefaultChunkingOptions()
+}
+
+// AlignmentOptions configures source grounding of extractions.
+type AlignmentOptions = alignment.AlignmentOptions
+
+// DefaultAlignmentOptions returns the default grounding configuration:
+// case-insensitive exact matching with fuzzy matching as a fallback.
+func DefaultAlignmentOptions() AlignmentOptions {
+	return alignment.DefaultAlignmentOptions()
+}
+
+// UngroundedPolicy defines how extractions that cannot be aligned are handled.
+type UngroundedPolicy = engine.UngroundedPolicy
+
+const (
+	// UngroundedKeep keeps ungroundable extractions without position information
+	UngroundedKeep = engine.UngroundedKeep
+
+	// UngroundedDrop removes ungroundable extractions from the result
+	UngroundedDrop = engine.UngroundedDrop
+
+	// UngroundedFlag keeps ungroundable extractions and sets the
+	// UngroundedAttribute attribute on them
+	UngroundedFlag = engine.UngroundedFlag
+)
+
+// UngroundedAttribute is the attribute set on extractions flagged by UngroundedFlag.
+const UngroundedAttribute = engine.UngroundedAttribute
+
 // NewExtractOptions creates ExtractOptions with sensible defaults.
 func NewExtractOptions() *ExtractOptions {
 	return &ExtractOptions{
@@ -75,6 +196,7 @@ func NewExtractOptions() *ExtractOptions {
 		ValidateOutput:     true,
 		RetryCount:         2,
 		DebugMode:          false,
+		UngroundedPolicy:   UngroundedKeep,
 	}
 }
 
@@ -102,6 +224,13 @@ func (opts *ExtractOptions) WithModelConfig(config *providers.ModelConfig) *Extr
 	return opts
 }
 
+// WithProvider sets a preconstructed language model provider. The options'
+// schema, if any, is applied to it.
+func (opts *ExtractOptions) WithProvider(provider providers.BaseLanguageModel) *ExtractOptions {
+	opts.Provider = provider
+	return opts
+}
+
 // WithExtractionPasses sets the number of extraction passes.
 func (opts *ExtractOptions) WithExtractionPasses(passes int) *ExtractOptions {
 	opts.ExtractionPasses = passes
@@ -156,6 +285,49 @@ func (opts *ExtractOptions) WithDebugMode(enabled bool) *ExtractOptions {
 	return opts
 }
 
+// WithAlignmentOptions sets the source grounding options.
+func (opts *ExtractOptions) WithAlignmentOptions(alignmentOpts AlignmentOptions) *ExtractOptions {
+	opts.AlignmentOptions = &alignmentOpts
+	return opts
+}
+
+// WithUngroundedPolicy sets how ungroundable extractions are handled.
+func (opts *ExtractOptions) WithUngroundedPolicy(policy UngroundedPolicy) *ExtractOptions {
+	opts.UngroundedPolicy = policy
+	return opts
+}
+
+// WithChunking enables chunked extraction with the given strategy and options.
+func (opts *ExtractOptions) WithChunking(strategy ChunkingStrategy, chunkingOpts ChunkingOptions) *ExtractOptions {
+	opts.ChunkingStrategy = strategy
+	opts.ChunkingOptions = &chunkingOpts
+	return opts
+}
+
+// WithChunkingStrategy sets the chunking strategy, keeping default chunking options.
+func (opts *ExtractOptions) WithChunkingStrategy(strategy ChunkingStrategy) *ExtractOptions {
+	opts.ChunkingStrategy = strategy
+	return opts
+}
+
+// WithURLFetcher sets the fetcher used to download URL inputs.
+func (opts *ExtractOptions) WithURLFetcher(fetcher *URLFetcher) *ExtractOptions {
+	opts.URLFetcher = fetcher
+	return opts
+}
+
+// WithDocumentCallback sets the per-document completion callback used by ExtractDocuments.
+func (opts *ExtractOptions) WithDocumentCallback(callback func(result *DocumentResult)) *ExtractOptions {
+	opts.OnDocumentComplete = callback
+	return opts
+}
+
+// WithBudget sets the budget that caps the cost and tokens of extractions.
+func (opts *ExtractOptions) WithBudget(budget *Budget) *ExtractOptions {
+	opts.Budget = budget
+	return opts
+}
+
 // Validate checks if the options are valid.
 func (opts *ExtractOptions) Validate() error {
 	if opts.PromptDescription == "" {
@@ -182,6 +354,32 @@ func (opts *ExtractOptions) Validate() error {
 		return NewValidationError("RetryCount", string(rune(opts.RetryCount)), "must be non-negative")
 	}
 
+	if opts.AlignmentOptions != nil {
+		if err := opts.AlignmentOptions.Validate(); err != nil {
+			return NewValidationError("AlignmentOptions", "", err.Error())
+		}
+	}
+
+	if !opts.UngroundedPolicy.IsValid() {
+		return NewValidationError("UngroundedPolicy", string(opts.UngroundedPolicy), "must be one of keep, drop or flag")
+	}
+
+	if !opts.ChunkingStrategy.IsValid() {
+		return NewValidationError("ChunkingStrategy", string(opts.ChunkingStrategy), "must be one of simple, semantic or adaptive")
+	}
+
+	if opts.ChunkingOptions != nil {
+		if err := opts.ChunkingOptions.Validate(); err != nil {
+			return NewValidationError("ChunkingOptions", "", err.Error())
+		}
+	}
+
+	if opts.Budget != nil {
+		if err := opts.Budget.Limits().Validate(); err != nil {
+			return NewValidationError("Budget", opts.Budget.Limits().String(), err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,81 @@
+package langextract
+
+import (
+	"time"
+
+	"github.com/sehwan505/langextract-go/internal/engine"
+	"github.com/sehwan505/langextract-go/pkg/document"
+)
+
+// ExtractResult bundles an annotated document with metadata describing
+// how the extraction was executed.
+type ExtractResult struct {
+	// Document is the annotated document with grounded extractions
+	Document *document.AnnotatedDocument
+
+	// Metadata describes the execution of the extraction pipeline
+	Metadata *ExtractMetadata
+}
+
+// ExtractMetadata reports execution details from the extraction engine.
+type ExtractMetadata struct {
+	RequestID        string
+	ProviderUsed     string
+	ModelUsed        string
+	TokensUsed       int
+	CostUSD          float64 // Estimated from the model catalog, zero if the model is unpriced
+	EstimatedCostUSD float64 // Prompt cost estimated before the requests were sent
+	PassesCompleted  int
+	ChunksProcessed  int
+	ExtractionCount  int
+	ExecutionTime    time.Duration
+	TextCoverage     float64
+	ConfidenceScore  float64
+	ProcessingSteps  []ProcessingStep
+	ValidationErrors []string
+}
+
+// ProcessingStep describes a single stage of the extraction pipeline.
+type ProcessingStep struct {
+	Name     string
+	Status   string
+	Message  string
+	Duration time.Duration
+	Metadata map[string]any
+}
+
+// newExtractMetadata converts an engine response into public metadata.
+func newExtractMetadata(response *engine.ExtractionResponse) *ExtractMetadata {
+	metadata := &ExtractMetadata{
+		RequestID:        response.RequestID,
+		ProviderUsed:     response.ProviderUsed,
+		ModelUsed:        response.ModelUsed,
+		TokensUsed:       response.TokensUsed,
+		CostUSD:          response.CostUSD,
+		EstimatedCostUSD: response.EstimatedCostUSD,
+		PassesCompleted:  response.PassesCompleted,
+		ChunksProcessed:  response.ChunksProcessed,
+		ExtractionCount:  response.ExtractionCount,
+		ExecutionTime:    response.ExecutionTime,
+		TextCoverage:     response.TextCoverage,
+		ConfidenceScore:  response.ConfidenceScore,
+	}
+
+	if response.DebugInfo != nil {
+		for _, step := range response.DebugInfo.ProcessingSteps {
+			metadata.ProcessingSteps = append(metadata.ProcessingSteps, ProcessingStep{
+				Name:     step.Name,
+				Status:   step.Status,
+				Message:  step.Message,
+				Duration: step.Duration,
+				Metadata: step.Metadata,
+			})
+		}
+	}
+
+	for _, validationErr := range response.ValidationErrors {
+		metadata.ValidationErrors = append(metadata.ValidationErrors, validationErr.Message)
+	}
+
+	return metadata
+}
@@ -0,0 +1,593 @@
+package langextract
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sehwan505/langextract-go/pkg/extraction"
+	"github.com/sehwan505/langextract-go/pkg/types"
+)
+
+// Extracted is an extraction decoded into a Go value by ExtractInto.
+type Extracted[T any] struct {
+	// Value is the decoded struct
+	Value T
+
+	// CharInterval is the position of the extraction in the source text
+	CharInterval *types.CharInterval
+
+	// Extraction is the underlying extraction
+	Extraction *extraction.Extraction
+}
+
+// ExtractionClasser can be implemented by types used with ExtractInto to
+// choose their extraction class. By default the class is the snake_case type
+// name, e.g. "medical_condition" for MedicalCondition.
+type ExtractionClasser interface {
+	ExtractionClass() string
+}
+
+// ExtractInto extracts instances of T from the input and decodes each
+// grounded extraction into a T.
+//
+// The extraction schema is derived from T with SchemaFor and replaces
+// opts.Schema. Each exported field of T maps to an extraction attribute,
+// configured with an `extract` struct tag:
+//
+//	type Medication struct {
+//		Name      string  `extract:",text"`
+//		Dosage    string  `extract:"dosage,required"`
+//		Route     string  `extract:"route,enum=oral|iv|topical"`
+//		Frequency int     `extract:"times_per_day,min=1,max=12"`
+//		Notes     *string `extract:"notes" description:"Free-text remarks"`
+//		Internal  string  `extract:"-"`
+//	}
+//
+// Fields of embedded structs and struct pointers are flattened into T, like
+// in encoding/json; embedded pointers are allocated when one of their fields
+// is decoded. The first tag element is the attribute name; it defaults to the
+// json tag name or the snake_case field name. The "text" option binds the field to the
+// extraction text instead of an attribute. "required", "enum", "min" and "max"
+// become schema constraints; "min" and "max" limit string length for strings.
+//
+// Attribute values are coerced to the field types, so "42" decodes into an
+// int field. Extractions whose attributes cannot be decoded are left out of the
+// result and reported in a *DecodeError, with one FieldError per field;
+// the successfully decoded extractions are still returned alongside it.
+// Ungrounded extractions and extractions of other classes are skipped.
+func ExtractInto[T any](input TextOrDocuments, opts *ExtractOptions) ([]Extracted[T], error) {
+	schema, err := SchemaFor[T]()
+	if err != nil {
+		return nil, NewExtractError("derive_schema", "failed to derive extraction schema", err)
+	}
+
+	if opts == nil {
+		opts = NewExtractOptions()
+	}
+
+	// Constraint violations are reported per field by the decoder rather than
+	// dropped by schema validation in the engine.
+	typedOpts := *opts
+	typedOpts.Schema = schema
+	typedOpts.ValidateOutput = false
+
+	doc, err := Extract(input, &typedOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeExtractions[T](doc.Extractions)
+}
+
+// SchemaFor derives an extraction schema with a single class from the struct
+// type T. See ExtractInto for the supported struct tags.
+func SchemaFor[T any]() (*extraction.BasicExtractionSchema, error) {
+	spec, err := typeSpecFor[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	class := &extraction.ClassDefinition{
+		Name:        spec.class,
+		Description: fmt.Sprintf("%s entities", spec.typ.Name()),
+	}
+	for _, field := range spec.fields {
+		class.Fields = append(class.Fields, field.definition)
+	}
+
+	schema := extraction.NewBasicExtractionSchema(spec.class, fmt.Sprintf("Schema derived from %s", spec.typ.Name()))
+	schema.AddClass(class)
+	return schema, nil
+}
+
+// DecodeExtractions decodes the grounded extractions of T's class into values
+// of T. It reports decoding failures the same way as ExtractInto.
+func DecodeExtractions[T any](extractions []*extraction.Extraction) ([]Extracted[T], error) {
+	spec, err := typeSpecFor[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Extracted[T]
+	var fieldErrors []*FieldError
+
+	for index, ext := range extractions {
+		if ext == nil || ext.ExtractionClass != spec.class || ext.CharInterval == nil {
+			continue
+		}
+
+		var value T
+		errs := spec.decode(reflect.ValueOf(&value).Elem(), ext, index)
+		if len(errs) > 0 {
+			fieldErrors = append(fieldErrors, errs...)
+			continue
+		}
+
+		results = append(results, Extracted[T]{
+			Value:        value,
+			CharInterval: ext.CharInterval,
+			Extraction:   ext,
+		})
+	}
+
+	if len(fieldErrors) > 0 {
+		return results, &DecodeError{Errors: fieldErrors}
+	}
+	return results, nil
+}
+
+// typeSpec describes how extractions decode into a struct type.
+type typeSpec struct {
+	typ       reflect.Type
+	class     string
+	textField []int
+	fields    []fieldSpec
+}
+
+// fieldSpec describes how an attribute decodes into a struct field.
+type fieldSpec struct {
+	name       string // Go field name
+	index      []int
+	definition *extraction.FieldDefinition
+}
+
+func typeSpecFor[T any]() (*typeSpec, error) {
+	var zero T
+	typ := reflect.TypeOf(&zero).Elem()
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("ExtractInto requires a struct type, got %s", typ)
+	}
+
+	spec := &typeSpec{typ: typ, class: toSnakeCase(typ.Name())}
+	if classer, ok := any(zero).(ExtractionClasser); ok {
+		spec.class = classer.ExtractionClass()
+	} else if classer, ok := any(&zero).(ExtractionClasser); ok {
+		spec.class = classer.ExtractionClass()
+	}
+	if spec.class == "" {
+		return nil, fmt.Errorf("cannot derive an extraction class for %s", typ)
+	}
+
+	if err := spec.addFields(typ, nil, map[reflect.Type]bool{typ: true}); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// addFields adds the exported fields of typ, flattening embedded structs and
+// struct pointers. embedding holds the struct types being flattened, to
+// reject recursive embedding.
+func (s *typeSpec) addFields(typ reflect.Type, index []int, embedding map[reflect.Type]bool) error {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		tag, hasTag := field.Tag.Lookup("extract")
+		if tag == "-" {
+			continue
+		}
+
+		if embedded := indirectType(field.Type); field.Anonymous && !hasTag && embedded.Kind() == reflect.Struct {
+			if field.Type.Kind() == reflect.Pointer && !field.IsExported() {
+				return fmt.Errorf("field %s: cannot set embedded pointer to unexported struct", field.Name)
+			}
+			if embedding[embedded] {
+				return fmt.Errorf("field %s: recursive embedded struct %s", field.Name, embedded)
+			}
+			embedding[embedded] = true
+			err := s.addFields(embedded, fieldIndex, embedding)
+			delete(embedding, embedded)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options := parseExtractTag(tag)
+		if name == "" {
+			name = jsonFieldName(field)
+		}
+
+		if _, ok := options["text"]; ok {
+			if indirectType(field.Type).Kind() != reflect.String {
+				return fmt.Errorf("field %s: text field must be a string", field.Name)
+			}
+			if s.textField != nil {
+				return fmt.Errorf("field %s: only one field can hold the extraction text", field.Name)
+			}
+			s.textField = fieldIndex
+			continue
+		}
+
+		definition, err := fieldDefinition(field, name, options)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		s.fields = append(s.fields, fieldSpec{name: field.Name, index: fieldIndex, definition: definition})
+	}
+	return nil
+}
+
+// fieldDefinition builds the schema definition of a struct field.
+func fieldDefinition(field reflect.StructField, name string, options map[string]string) (*extraction.FieldDefinition, error) {
+	definition := &extraction.FieldDefinition{
+		Name:        name,
+		Description: field.Tag.Get("description"),
+	}
+
+	typ := indirectType(field.Type)
+	switch typ.Kind() {
+	case reflect.String:
+		definition.Type = "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		definition.Type = "number"
+	case reflect.Bool:
+		definition.Type = "boolean"
+	case reflect.Slice, reflect.Array:
+		definition.Type = "array"
+	case reflect.Interface:
+		// Any value is accepted
+	default:
+		return nil, fmt.Errorf("unsupported field type %s", field.Type)
+	}
+
+	for option, value := range options {
+		switch option {
+		case "required":
+			definition.Required = true
+		case "enum":
+			if definition.Type != "string" {
+				return nil, fmt.Errorf("enum is only supported for string fields")
+			}
+			definition.Enum = strings.Split(value, "|")
+		case "min", "max":
+			limit, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value %q", option, value)
+			}
+			switch definition.Type {
+			case "number":
+				if option == "min" {
+					definition.Minimum = &limit
+				} else {
+					definition.Maximum = &limit
+				}
+			case "string":
+				length := int(limit)
+				if option == "min" {
+					definition.MinLength = &length
+				} else {
+					definition.MaxLength = &length
+				}
+			default:
+				return nil, fmt.Errorf("%s is only supported for string and number fields", option)
+			}
+		default:
+			return nil, fmt.Errorf("unknown extract tag option %q", option)
+		}
+	}
+
+	return definition, nil
+}
+
+// decode decodes an extraction into the struct value v.
+func (s *typeSpec) decode(v reflect.Value, ext *extraction.Extraction, index int) []*FieldError {
+	var errs []*FieldError
+
+	if s.textField != nil {
+		setString(fieldByIndex(v, s.textField), ext.ExtractionText)
+	}
+
+	for _, field := range s.fields {
+		fail := func(value interface{}, err error) {
+			errs = append(errs, &FieldError{
+				Index:     index,
+				Class:     ext.ExtractionClass,
+				Field:     field.name,
+				Attribute: field.definition.Name,
+				Value:     value,
+				Err:       err,
+			})
+		}
+
+		value, ok := ext.GetAttribute(field.definition.Name)
+		if !ok || value == nil {
+			if field.definition.Required {
+				fail(nil, fmt.Errorf("required attribute is missing"))
+			}
+			continue
+		}
+
+		target := fieldByIndex(v, field.index)
+		if err := coerceValue(target, value); err != nil {
+			fail(value, err)
+			continue
+		}
+		if err := checkConstraints(field.definition, target); err != nil {
+			fail(value, err)
+		}
+	}
+
+	return errs
+}
+
+// coerceValue stores value in target, converting between JSON types and the
+// target type where the conversion is lossless. A JSON null leaves the zero
+// value, like in encoding/json.
+func coerceValue(target reflect.Value, value interface{}) error {
+	if value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	if target.Kind() == reflect.Pointer {
+		elem := reflect.New(target.Type().Elem())
+		if err := coerceValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		target.Set(elem)
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Interface:
+		v := reflect.ValueOf(value)
+		if !v.Type().AssignableTo(target.Type()) {
+			return fmt.Errorf("cannot assign %T to %s", value, target.Type())
+		}
+		target.Set(v)
+
+	case reflect.String:
+		switch v := value.(type) {
+		case string:
+			target.SetString(v)
+		case float64:
+			target.SetString(strconv.FormatFloat(v, 'f', -1, 64))
+		case bool:
+			target.SetString(strconv.FormatBool(v))
+		default:
+			return fmt.Errorf("cannot convert %T to string", value)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		num, err := toFloat(value)
+		if err != nil {
+			return err
+		}
+		// Out of range floats convert to implementation-defined integers,
+		// so the range is checked before converting
+		if num != math.Trunc(num) || num < -(1<<63) || num >= 1<<63 || target.OverflowInt(int64(num)) {
+			return fmt.Errorf("%v does not fit in %s", value, target.Type())
+		}
+		target.SetInt(int64(num))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		num, err := toFloat(value)
+		if err != nil {
+			return err
+		}
+		if num < 0 || num != math.Trunc(num) || num >= 1<<64 || target.OverflowUint(uint64(num)) {
+			return fmt.Errorf("%v does not fit in %s", value, target.Type())
+		}
+		target.SetUint(uint64(num))
+
+	case reflect.Float32, reflect.Float64:
+		num, err := toFloat(value)
+		if err != nil {
+			return err
+		}
+		if target.OverflowFloat(num) {
+			return fmt.Errorf("%v does not fit in %s", value, target.Type())
+		}
+		target.SetFloat(num)
+
+	case reflect.Bool:
+		switch v := value.(type) {
+		case bool:
+			target.SetBool(v)
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("cannot convert %q to bool", v)
+			}
+			target.SetBool(b)
+		default:
+			return fmt.Errorf("cannot convert %T to bool", value)
+		}
+
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			// A single value decodes into a one-element slice
+			items = []interface{}{value}
+		}
+		slice := reflect.MakeSlice(target.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := coerceValue(slice.Index(i), item); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		target.Set(slice)
+
+	case reflect.Array:
+		items, ok := value.([]interface{})
+		if !ok || len(items) != target.Len() {
+			return fmt.Errorf("expected an array of %d elements", target.Len())
+		}
+		for i, item := range items {
+			if err := coerceValue(target.Index(i), item); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+
+	default:
+		return fmt.Errorf("unsupported field type %s", target.Type())
+	}
+
+	return nil
+}
+
+// toFloat converts a JSON number or numeric string to float64.
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case string:
+		num, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot convert %q to a number", v)
+		}
+		return num, nil
+	default:
+		return 0, fmt.Errorf("cannot convert %T to a number", value)
+	}
+}
+
+// checkConstraints checks a decoded field against its schema constraints.
+func checkConstraints(definition *extraction.FieldDefinition, target reflect.Value) error {
+	for target.Kind() == reflect.Pointer {
+		target = target.Elem()
+	}
+
+	switch definition.Type {
+	case "string":
+		str := target.String()
+		if len(definition.Enum) > 0 && !containsString(definition.Enum, str) {
+			return fmt.Errorf("value %q not in allowed values: %s", str, strings.Join(definition.Enum, ", "))
+		}
+		if definition.MinLength != nil && len(str) < *definition.MinLength {
+			return fmt.Errorf("string too short: %d < %d", len(str), *definition.MinLength)
+		}
+		if definition.MaxLength != nil && len(str) > *definition.MaxLength {
+			return fmt.Errorf("string too long: %d > %d", len(str), *definition.MaxLength)
+		}
+
+	case "number":
+		var num float64
+		switch target.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			num = float64(target.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			num = float64(target.Uint())
+		default:
+			num = target.Float()
+		}
+		if definition.Minimum != nil && num < *definition.Minimum {
+			return fmt.Errorf("number too small: %v < %v", num, *definition.Minimum)
+		}
+		if definition.Maximum != nil && num > *definition.Maximum {
+			return fmt.Errorf("number too large: %v > %v", num, *definition.Maximum)
+		}
+	}
+
+	return nil
+}
+
+// parseExtractTag splits an extract tag into the attribute name and options.
+func parseExtractTag(tag string) (string, map[string]string) {
+	parts := strings.Split(tag, ",")
+	options := make(map[string]string)
+	for _, part := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if key != "" {
+			options[key] = value
+		}
+	}
+	return strings.TrimSpace(parts[0]), options
+}
+
+// jsonFieldName returns the json tag name of a field or its snake_case name.
+func jsonFieldName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return toSnakeCase(field.Name)
+}
+
+// toSnakeCase converts a Go identifier such as "HTTPStatusCode" to "http_status_code".
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				builder.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// fieldByIndex returns the nested field of v with the given index, allocating
+// the nil embedded struct pointers on the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+func setString(target reflect.Value, value string) {
+	if target.Kind() == reflect.Pointer {
+		target.Set(reflect.New(target.Type().Elem()))
+		target = target.Elem()
+	}
+	target.SetString(value)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package langextract
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sehwan505/langextract-go/pkg/document"
+)
+
+// URLFetcher downloads documents for URL inputs.
+type URLFetcher struct {
+	// Client performs the requests
+	// Default: http.DefaultClient
+	Client *http.Client
+
+	// MaxBytes limits the size of a downloaded document
+	// Default: 10 MiB
+	MaxBytes int64
+
+	// Timeout limits the duration of a single download, in addition to any
+	// deadline on the caller's context
+	// Default: 30 seconds
+	Timeout time.Duration
+
+	// UserAgent is sent with each request when set
+	UserAgent string
+}
+
+// NewURLFetcher creates a URLFetcher with sensible defaults.
+func NewURLFetcher() *URLFetcher {
+	return &URLFetcher{
+		Client:    http.DefaultClient,
+		MaxBytes:  10 << 20,
+		Timeout:   30 * time.Second,
+		UserAgent: "langextract-go",
+	}
+}
+
+// WithClient sets the HTTP client used for downloads.
+func (f *URLFetcher) WithClient(client *http.Client) *URLFetcher {
+	f.Client = client
+	return f
+}
+
+// WithMaxBytes sets the maximum document size.
+func (f *URLFetcher) WithMaxBytes(maxBytes int64) *URLFetcher {
+	f.MaxBytes = maxBytes
+	return f
+}
+
+// WithTimeout sets the per-download timeout.
+func (f *URLFetcher) WithTimeout(timeout time.Duration) *URLFetcher {
+	f.Timeout = timeout
+	return f
+}
+
+// Fetch downloads the document at rawURL.
+//
+// HTML and XHTML responses are converted to text with
+// document.NewDocumentFromHTML, so the document's SourceMap maps positions
+// back to the markup. Other text responses, JSON and XML are used as-is.
+// Any other content type is rejected. The URL and content type are recorded
+// in the document metadata.
+func (f *URLFetcher) Fetch(ctx context.Context, rawURL string) (*document.Document, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if f.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", rawURL, err)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.9,*/*;q=0.5")
+	if f.UserAgent != "" {
+		req.Header.Set("User-Agent", f.UserAgent)
+	}
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("failed to fetch %s: unexpected status %s", rawURL, resp.Status)
+	}
+
+	mediaType, isHTML, err := parseContentType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+	}
+
+	if f.MaxBytes > 0 && resp.ContentLength > f.MaxBytes {
+		return nil, fmt.Errorf("failed to fetch %s: content length %d exceeds limit of %d bytes", rawURL, resp.ContentLength, f.MaxBytes)
+	}
+
+	body := io.Reader(resp.Body)
+	if f.MaxBytes > 0 {
+		body = io.LimitReader(resp.Body, f.MaxBytes+1)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rawURL, err)
+	}
+	if f.MaxBytes > 0 && int64(len(data)) > f.MaxBytes {
+		return nil, fmt.Errorf("failed to fetch %s: content exceeds limit of %d bytes", rawURL, f.MaxBytes)
+	}
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("failed to fetch %s: content is not valid UTF-8", rawURL)
+	}
+
+	var doc *document.Document
+	if isHTML {
+		doc = document.NewDocumentFromHTML(string(data))
+	} else {
+		doc = document.NewDocument(string(data))
+	}
+
+	doc.SetMetadata(document.MetadataSourceURL, rawURL)
+	doc.SetMetadata(document.MetadataContentType, mediaType)
+
+	return doc, nil
+}
+
+// parseContentType returns the media type of a response and whether it is
+// HTML. A missing content type is treated as plain text.
+func parseContentType(contentType string) (string, bool, error) {
+	if contentType == "" {
+		return "text/plain", false, nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid content type %q: %w", contentType, err)
+	}
+
+	if charset, ok := params["charset"]; ok {
+		if !strings.EqualFold(charset, "utf-8") && !strings.EqualFold(charset, "us-ascii") {
+			return "", false, fmt.Errorf("unsupported charset %q", charset)
+		}
+	}
+
+	switch {
+	case mediaType == "text/html", mediaType == "application/xhtml+xml":
+		return mediaType, true, nil
+	case strings.HasPrefix(mediaType, "text/"),
+		mediaType == "application/json",
+		mediaType == "application/xml",
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return mediaType, false, nil
+	default:
+		return "", false, fmt.Errorf("unsupported content type %q", mediaType)
+	}
+}
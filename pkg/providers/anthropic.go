@@ -0,0 +1,435 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// anthropicVersion is the Messages API version sent with each request
+	anthropicVersion = "2023-06-01"
+
+	// anthropicDefaultMaxTokens is used when the config sets no limit, since
+	// the Messages API requires max_tokens
+	anthropicDefaultMaxTokens = 1024
+
+	// anthropicExtractionTool is the tool used for schema-constrained output
+	anthropicExtractionTool = "record_extractions"
+)
+
+// AnthropicProvider implements the BaseLanguageModel interface for Anthropic
+// Claude models using the Messages API.
+//
+// Provider kwargs:
+//   - api_key: API key, used when ANTHROPIC_API_KEY is not set
+//   - base_url: API base URL (default "https://api.anthropic.com/v1")
+//   - system: system prompt sent with every request
+//   - stop_sequences: custom stop sequences ([]string)
+//
+// The system messages of a conversation passed to InferMessages replace the
+// system kwarg. The "system" and "stop_sequences" Infer options override
+// both for a single call. When a schema is applied, the model is forced to answer through
+// a tool whose input schema is the extraction schema, and the tool input is
+// returned as JSON output.
+type AnthropicProvider struct {
+	config        *ModelConfig
+	apiKey        string
+	baseURL       string
+	client        *http.Client
+	timeout       time.Duration // timeout of requests whose context has no deadline
+	system        string
+	stopSequences []string
+	schema        any
+	fenceOutput   bool
+	limit         requestLimit
+	rate          *rateLimiter
+	retry         *RetryPolicy
+
+	usageMu sync.Mutex
+	usage   AnthropicUsage
+}
+
+// AnthropicRequest represents an Anthropic Messages API request.
+type AnthropicRequest struct {
+	Model         string               `json:"model"`
+	MaxTokens     int                  `json:"max_tokens"`
+	System        string               `json:"system,omitempty"`
+	Messages      []Message            `json:"messages"`
+	Temperature   *float64             `json:"temperature,omitempty"`
+	TopP          *float64             `json:"top_p,omitempty"`
+	StopSequences []string             `json:"stop_sequences,omitempty"`
+	Tools         []AnthropicTool      `json:"tools,omitempty"`
+	ToolChoice    *AnthropicToolChoice `json:"tool_choice,omitempty"`
+}
+
+// AnthropicTool describes a tool the model can call.
+type AnthropicTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InputSchema any    `json:"input_schema"`
+}
+
+// AnthropicToolChoice controls how the model uses tools.
+type AnthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+// AnthropicResponse represents an Anthropic Messages API response.
+type AnthropicResponse struct {
+	ID           string                  `json:"id"`
+	Type         string                  `json:"type"`
+	Role         string                  `json:"role"`
+	Model        string                  `json:"model"`
+	Content      []AnthropicContentBlock `json:"content"`
+	StopReason   string                  `json:"stop_reason"`
+	StopSequence string                  `json:"stop_sequence,omitempty"`
+	Usage        AnthropicUsage          `json:"usage"`
+}
+
+// AnthropicContentBlock represents a block of response content.
+type AnthropicContentBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text,omitempty"`
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+// AnthropicUsage represents token usage information.
+type AnthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// TotalTokens returns the sum of input and output tokens.
+func (u AnthropicUsage) TotalTokens() int {
+	return u.InputTokens + u.OutputTokens
+}
+
+// NewAnthropicProvider creates a new Anthropic provider instance.
+func NewAnthropicProvider(config *ModelConfig) (BaseLanguageModel, error) {
+	apiKey := os.Getenv("ANTHROPIC_API_KEY")
+	if apiKey == "" {
+		if config.ProviderKwargs != nil {
+			if key, ok := config.ProviderKwargs["api_key"].(string); ok {
+				apiKey = key
+			}
+		}
+	}
+
+	if apiKey == "" {
+		return nil, fmt.Errorf("Anthropic API key not found. Set ANTHROPIC_API_KEY environment variable or provide in config")
+	}
+
+	baseURL := "https://api.anthropic.com/v1"
+	if envURL := os.Getenv("ANTHROPIC_BASE_URL"); envURL != "" {
+		baseURL = envURL
+	}
+
+	client, timeout, err := newHTTPClient(config, 60*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP configuration: %w", err)
+	}
+
+	provider := &AnthropicProvider{
+		config:  config,
+		apiKey:  apiKey,
+		baseURL: baseURL,
+		limit:   newRequestLimit(config),
+		rate:    sharedRateLimiter("anthropic", config),
+		retry:   retryPolicy(config),
+		client:  client,
+		timeout: timeout,
+	}
+
+	if config.ProviderKwargs != nil {
+		if url, ok := config.ProviderKwargs["base_url"].(string); ok {
+			provider.baseURL = url
+		}
+		if system, ok := config.ProviderKwargs["system"].(string); ok {
+			provider.system = system
+		}
+		if stops, ok := stringSlice(config.ProviderKwargs["stop_sequences"]); ok {
+			provider.stopSequences = stops
+		}
+	}
+	provider.baseURL = strings.TrimSuffix(provider.baseURL, "/")
+
+	return provider, nil
+}
+
+// Infer generates model output for the given prompts.
+func (p *AnthropicProvider) Infer(ctx context.Context, prompts []string, options map[string]any) ([][]ScoredOutput, error) {
+	return inferOutputs(p.InferDetailed(ctx, prompts, options))
+}
+
+// InferDetailed generates model output for the given prompts, with token
+// usage, stop reason and message ID.
+func (p *AnthropicProvider) InferDetailed(ctx context.Context, prompts []string, options map[string]any) ([]*InferenceResult, error) {
+	return p.InferMessages(ctx, promptConversations(prompts), options)
+}
+
+// InferMessages generates model output for each conversation. System
+// messages are sent as the system prompt and the other messages as turns.
+func (p *AnthropicProvider) InferMessages(ctx context.Context, conversations [][]Message, options map[string]any) ([]*InferenceResult, error) {
+	return inferBatch(ctx, p.limit, p.rate, conversations, func(ctx context.Context, messages []Message) (*InferenceResult, error) {
+		response, err := p.generateMessage(ctx, messages, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate completion: %w", err)
+		}
+
+		output, err := p.responseOutput(response)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response: %w", err)
+		}
+
+		return &InferenceResult{
+			Outputs: []ScoredOutput{{
+				Output: output,
+				Score:  1.0, // Anthropic doesn't provide scores, use default
+			}},
+			Usage: Usage{
+				PromptTokens:     response.Usage.InputTokens,
+				CompletionTokens: response.Usage.OutputTokens,
+				TotalTokens:      response.Usage.TotalTokens(),
+			},
+			FinishReason: anthropicFinishReason(response.StopReason),
+			ResponseID:   response.ID,
+		}, nil
+	})
+}
+
+// anthropicFinishReason normalizes a Messages API stop reason.
+func anthropicFinishReason(reason string) FinishReason {
+	switch reason {
+	case "end_turn", "stop_sequence", "tool_use":
+		return FinishReasonStop
+	case "max_tokens":
+		return FinishReasonLength
+	case "refusal":
+		return FinishReasonSafety
+	case "":
+		return ""
+	}
+	return FinishReasonOther
+}
+
+// buildRequest creates the Messages API request for a conversation.
+func (p *AnthropicProvider) buildRequest(messages []Message, options map[string]any) *AnthropicRequest {
+	maxTokens := p.config.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = anthropicDefaultMaxTokens
+	}
+
+	system, turns := splitSystemMessages(messages)
+	if system == "" {
+		system = p.system
+	}
+
+	temperature := p.config.Temperature
+	request := &AnthropicRequest{
+		Model:         p.config.ModelID,
+		MaxTokens:     maxTokens,
+		System:        system,
+		Messages:      turns,
+		Temperature:   &temperature,
+		StopSequences: p.stopSequences,
+	}
+
+	// The default top_p of 1.0 is not sent; Claude models recommend tuning
+	// either temperature or top_p, not both
+	if p.config.TopP > 0 && p.config.TopP < 1 {
+		topP := p.config.TopP
+		request.TopP = &topP
+	}
+
+	if system, ok := options["system"].(string); ok {
+		request.System = system
+	}
+	if stops, ok := stringSlice(options["stop_sequences"]); ok {
+		request.StopSequences = stops
+	}
+
+	if p.schema != nil {
+		request.Tools = []AnthropicTool{{
+			Name:        anthropicExtractionTool,
+			Description: "Record the information extracted from the text.",
+			InputSchema: p.schema,
+		}}
+		request.ToolChoice = &AnthropicToolChoice{Type: "tool", Name: anthropicExtractionTool}
+	}
+
+	return request
+}
+
+// generateMessage makes a request to the Messages API.
+func (p *AnthropicProvider) generateMessage(ctx context.Context, messages []Message, options map[string]any) (*AnthropicResponse, error) {
+	requestBody, err := json.Marshal(p.buildRequest(messages, options))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/messages", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := doRequest(p.client, p.retry, p.timeout, "anthropic", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response AnthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	p.usageMu.Lock()
+	p.usage.InputTokens += response.Usage.InputTokens
+	p.usage.OutputTokens += response.Usage.OutputTokens
+	p.usageMu.Unlock()
+
+	return &response, nil
+}
+
+// responseOutput extracts the model output from a response. Tool input is
+// returned when a schema is applied; otherwise the text blocks are joined.
+func (p *AnthropicProvider) responseOutput(response *AnthropicResponse) (string, error) {
+	if p.schema != nil {
+		for _, block := range response.Content {
+			if block.Type == "tool_use" && block.Name == anthropicExtractionTool {
+				return string(block.Input), nil
+			}
+		}
+		return "", fmt.Errorf("model did not call the %s tool (stop reason %q)", anthropicExtractionTool, response.StopReason)
+	}
+
+	var text strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String(), nil
+}
+
+// Usage returns the total token usage of all requests made by the provider.
+func (p *AnthropicProvider) Usage() AnthropicUsage {
+	p.usageMu.Lock()
+	defer p.usageMu.Unlock()
+	return p.usage
+}
+
+// ParseOutput processes raw model output into structured format.
+func (p *AnthropicProvider) ParseOutput(output string) (any, error) {
+	// Try to parse as JSON first
+	var result any
+
+	// Remove code fences if present
+	cleanOutput := p.cleanOutput(output)
+
+	if err := json.Unmarshal([]byte(cleanOutput), &result); err != nil {
+		// If JSON parsing fails, return as string
+		return cleanOutput, nil
+	}
+
+	return result, nil
+}
+
+// cleanOutput removes markdown code fences and extra whitespace.
+func (p *AnthropicProvider) cleanOutput(output string) string {
+	output = strings.TrimSpace(output)
+
+	// Remove ```json and ``` fences
+	if strings.HasPrefix(output, "```json") {
+		output = strings.TrimPrefix(output, "```json")
+		output = strings.TrimSpace(output)
+	}
+	if strings.HasPrefix(output, "```") {
+		output = strings.TrimPrefix(output, "```")
+		output = strings.TrimSpace(output)
+	}
+	if strings.HasSuffix(output, "```") {
+		output = strings.TrimSuffix(output, "```")
+		output = strings.TrimSpace(output)
+	}
+
+	return output
+}
+
+// ApplySchema applies schema constraints to the model. The schema must be a
+// JSON Schema object; it becomes the input schema of the extraction tool.
+func (p *AnthropicProvider) ApplySchema(schema any) {
+	p.schema = schema
+}
+
+// SetFenceOutput configures whether output should be fenced.
+func (p *AnthropicProvider) SetFenceOutput(enabled bool) {
+	p.fenceOutput = enabled
+}
+
+// GetModelID returns the model identifier.
+func (p *AnthropicProvider) GetModelID() string {
+	return p.config.ModelID
+}
+
+// IsAvailable checks if the provider is ready for use.
+func (p *AnthropicProvider) IsAvailable() bool {
+	return p.apiKey != ""
+}
+
+// stringSlice converts a []string or []any of strings to []string.
+func stringSlice(value any) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []any:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			result = append(result, s)
+		}
+		return result, true
+	default:
+		return nil, false
+	}
+}
+
+// anthropicModelAliases are the Claude model IDs routed to the Anthropic provider.
+var anthropicModelAliases = []string{
+	"claude-opus-4-1",
+	"claude-opus-4-0",
+	"claude-sonnet-4-5",
+	"claude-sonnet-4-0",
+	"claude-3-7-sonnet-latest",
+	"claude-3-5-sonnet-latest",
+	"claude-3-5-haiku-latest",
+	"claude-3-opus-latest",
+	"claude-3-haiku-20240307",
+}
+
+// init registers the Anthropic provider with the global registry.
+func init() {
+	Register("anthropic", NewAnthropicProvider)
+
+	// Register common Claude model aliases
+	for _, modelID := range anthropicModelAliases {
+		RegisterAlias(modelID, "anthropic")
+	}
+}
@@ -0,0 +1,233 @@
+package providers
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CatalogFormatVersion is the version of the model catalog file format.
+// Catalog files of another version are rejected.
+const CatalogFormatVersion = 1
+
+//go:embed catalog.yaml
+var builtinCatalog []byte
+
+// ModelInfo describes the capabilities and pricing of a model.
+type ModelInfo struct {
+	ID              string   `yaml:"id" json:"id"`
+	Provider        string   `yaml:"provider" json:"provider"`
+	Aliases         []string `yaml:"aliases,omitempty" json:"aliases,omitempty"`                     // Other IDs of the model
+	ContextWindow   int      `yaml:"context_window,omitempty" json:"context_window,omitempty"`       // Input and output tokens, zero if unknown
+	MaxOutputTokens int      `yaml:"max_output_tokens,omitempty" json:"max_output_tokens,omitempty"` // Output token limit, zero if bounded only by the context window
+	JSONMode        bool     `yaml:"json_mode,omitempty" json:"json_mode,omitempty"`                 // Whether the model can be constrained to JSON output
+	Schema          bool     `yaml:"schema,omitempty" json:"schema,omitempty"`                       // Whether the model can be constrained to a JSON schema
+	Streaming       bool     `yaml:"streaming,omitempty" json:"streaming,omitempty"`                 // Whether the provider streams the model's output
+	InputPrice      float64  `yaml:"input_price,omitempty" json:"input_price,omitempty"`             // USD per million input tokens
+	OutputPrice     float64  `yaml:"output_price,omitempty" json:"output_price,omitempty"`           // USD per million output tokens
+}
+
+// Cost returns the cost in USD of the given token usage.
+func (m *ModelInfo) Cost(usage Usage) float64 {
+	promptTokens, completionTokens := usage.PromptTokens, usage.CompletionTokens
+	if promptTokens == 0 && completionTokens == 0 {
+		// Providers that report only a total are billed at the input price
+		promptTokens = usage.TotalTokens
+	}
+	return (float64(promptTokens)*m.InputPrice + float64(completionTokens)*m.OutputPrice) / 1e6
+}
+
+// InputTokenBudget returns the tokens available to a prompt when maxTokens
+// are reserved for the output, or zero if the context window is unknown. A
+// maxTokens of zero reserves the model's output limit.
+func (m *ModelInfo) InputTokenBudget(maxTokens int) int {
+	if m.ContextWindow <= 0 {
+		return 0
+	}
+	reserved := maxTokens
+	if reserved <= 0 || (m.MaxOutputTokens > 0 && reserved > m.MaxOutputTokens) {
+		reserved = m.MaxOutputTokens
+	}
+	if budget := m.ContextWindow - reserved; budget > 0 {
+		return budget
+	}
+	return 0
+}
+
+// catalogFile is the format of a model catalog file.
+type catalogFile struct {
+	Version int          `yaml:"version"`
+	Updated string       `yaml:"updated,omitempty"`
+	Models  []*ModelInfo `yaml:"models"`
+}
+
+// ModelCatalog is a versioned catalog of models. Models are looked up by ID
+// or alias. A model ID that is not in the catalog matches the longest
+// catalog ID it extends with a version or tag, so that "gpt-4o-2024-08-06"
+// and "llama3.1:8b" match "gpt-4o" and "llama3.1".
+type ModelCatalog struct {
+	mu      sync.RWMutex
+	updated string
+	models  map[string]*ModelInfo
+	aliases map[string]string
+}
+
+// NewModelCatalog creates an empty model catalog.
+func NewModelCatalog() *ModelCatalog {
+	return &ModelCatalog{
+		models:  make(map[string]*ModelInfo),
+		aliases: make(map[string]string),
+	}
+}
+
+// Load adds the models of a catalog file in YAML format to the catalog,
+// replacing models of the same ID.
+func (c *ModelCatalog) Load(data []byte) error {
+	var file catalogFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("invalid model catalog: %w", err)
+	}
+	if file.Version != 0 && file.Version != CatalogFormatVersion {
+		return fmt.Errorf("unsupported model catalog version %d, expected %d", file.Version, CatalogFormatVersion)
+	}
+
+	for i, model := range file.Models {
+		if model == nil || model.ID == "" || model.Provider == "" {
+			return fmt.Errorf("invalid model catalog: model %d must have an id and a provider", i)
+		}
+		if model.ContextWindow < 0 || model.MaxOutputTokens < 0 || model.InputPrice < 0 || model.OutputPrice < 0 {
+			return fmt.Errorf("invalid model catalog: model %s has negative limits or prices", model.ID)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if file.Updated > c.updated {
+		c.updated = file.Updated
+	}
+	for _, model := range file.Models {
+		c.models[model.ID] = model
+		for _, alias := range model.Aliases {
+			c.aliases[alias] = model.ID
+		}
+	}
+	return nil
+}
+
+// LoadFile adds the models of a catalog file to the catalog.
+func (c *ModelCatalog) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read model catalog: %w", err)
+	}
+	if err := c.Load(data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Updated returns the date of the most recent catalog file loaded.
+func (c *ModelCatalog) Updated() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.updated
+}
+
+// Lookup returns the catalog entry of a model.
+func (c *ModelCatalog) Lookup(modelID string) (*ModelInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if model, ok := c.models[modelID]; ok {
+		return model, true
+	}
+	if id, ok := c.aliases[modelID]; ok {
+		model, ok := c.models[id]
+		return model, ok
+	}
+
+	var match *ModelInfo
+	for id, model := range c.models {
+		if len(modelID) > len(id) && strings.HasPrefix(modelID, id) && strings.ContainsRune("-:@", rune(modelID[len(id)])) {
+			if match == nil || len(id) > len(match.ID) {
+				match = model
+			}
+		}
+	}
+	return match, match != nil
+}
+
+// Models returns the models of the catalog sorted by provider and ID, or
+// only the models of the given providers.
+func (c *ModelCatalog) Models(providers ...string) []*ModelInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	models := make([]*ModelInfo, 0, len(c.models))
+	for _, model := range c.models {
+		if len(providers) > 0 && !containsString(providers, model.Provider) {
+			continue
+		}
+		models = append(models, model)
+	}
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].Provider != models[j].Provider {
+			return models[i].Provider < models[j].Provider
+		}
+		return models[i].ID < models[j].ID
+	})
+	return models
+}
+
+// containsString reports whether values contains value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultCatalog is the catalog used by LookupModel, initialized with the
+// built-in catalog.
+var defaultCatalog = func() *ModelCatalog {
+	catalog := NewModelCatalog()
+	if err := catalog.Load(builtinCatalog); err != nil {
+		panic(fmt.Sprintf("invalid built-in model catalog: %v", err))
+	}
+	return catalog
+}()
+
+// DefaultCatalog returns the default model catalog, which holds the built-in
+// models and those loaded with LoadCatalogFile.
+func DefaultCatalog() *ModelCatalog {
+	return defaultCatalog
+}
+
+// LookupModel returns the default catalog entry of a model.
+func LookupModel(modelID string) (*ModelInfo, bool) {
+	return defaultCatalog.Lookup(modelID)
+}
+
+// LoadCatalogFile adds the models of a catalog file to the default catalog.
+// A missing file is not an error, so that optional user catalogs can be
+// loaded unconditionally.
+func LoadCatalogFile(path string) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return defaultCatalog.LoadFile(path)
+}
+
+// EstimateTokens estimates the tokens of a text, at about four characters
+// per token.
+func EstimateTokens(text string) int {
+	return estimateTokens(text)
+}
@@ -0,0 +1,141 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Roles of chat messages.
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// ChatLanguageModel is implemented by providers that accept prompts as
+// conversations of messages with roles, so that instructions can be sent as
+// a system prompt and few-shot examples as user and assistant turns. The
+// OpenAI, OpenAI-compatible, Gemini, Anthropic, Ollama and plugin providers
+// implement it.
+type ChatLanguageModel interface {
+	BaseLanguageModel
+
+	// InferMessages generates model output for each conversation, returning
+	// one result per conversation in order. A conversation is answered as
+	// the next assistant turn. If some conversations fail, the other results
+	// are returned with a *BatchError.
+	InferMessages(ctx context.Context, conversations [][]Message, options map[string]any) ([]*InferenceResult, error)
+}
+
+// InferMessages runs inference on conversations. Providers that do not
+// implement ChatLanguageModel get each conversation as a single prompt
+// rendered by FlattenMessages. As with Infer, a *BatchError may come with
+// partial results.
+func InferMessages(ctx context.Context, model BaseLanguageModel, conversations [][]Message, options map[string]any) ([]*InferenceResult, error) {
+	if err := ValidateMessages(conversations...); err != nil {
+		return nil, err
+	}
+	if chat, ok := model.(ChatLanguageModel); ok {
+		return chat.InferMessages(ctx, conversations, options)
+	}
+
+	prompts := make([]string, len(conversations))
+	for i, messages := range conversations {
+		prompts[i] = FlattenMessages(messages)
+	}
+	return InferDetailed(ctx, model, prompts, options)
+}
+
+// ValidateMessages checks that each conversation has messages of known
+// roles and ends with a user message.
+func ValidateMessages(conversations ...[]Message) error {
+	for i, messages := range conversations {
+		if len(messages) == 0 {
+			return fmt.Errorf("conversation %d has no messages", i)
+		}
+		for _, message := range messages {
+			switch message.Role {
+			case RoleSystem, RoleUser, RoleAssistant:
+			default:
+				return fmt.Errorf("conversation %d has a message of unknown role %q", i, message.Role)
+			}
+		}
+		if last := messages[len(messages)-1]; last.Role != RoleUser {
+			return fmt.Errorf("conversation %d must end with a user message, got %s", i, last.Role)
+		}
+	}
+	return nil
+}
+
+// FlattenMessages renders a conversation as a single prompt, for models
+// without chat support. System messages come first. User and assistant turns
+// are labeled when the conversation has assistant turns, so that a lone user
+// message is rendered as is.
+func FlattenMessages(messages []Message) string {
+	labeled := false
+	for _, message := range messages {
+		if message.Role == RoleAssistant {
+			labeled = true
+			break
+		}
+	}
+
+	var parts []string
+	for _, message := range messages {
+		if message.Role == RoleSystem {
+			parts = append(parts, message.Content)
+		}
+	}
+	for _, message := range messages {
+		switch {
+		case message.Role == RoleSystem:
+		case !labeled:
+			parts = append(parts, message.Content)
+		case message.Role == RoleAssistant:
+			parts = append(parts, "Assistant: "+message.Content)
+		default:
+			parts = append(parts, "User: "+message.Content)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// promptConversations converts prompts to conversations of a single user
+// message.
+func promptConversations(prompts []string) [][]Message {
+	conversations := make([][]Message, len(prompts))
+	for i, prompt := range prompts {
+		conversations[i] = []Message{{Role: RoleUser, Content: prompt}}
+	}
+	return conversations
+}
+
+// splitSystemMessages separates the system messages of a conversation, for
+// APIs that take the system prompt apart from the turns. The contents of
+// multiple system messages are joined.
+func splitSystemMessages(messages []Message) (string, []Message) {
+	var system []string
+	turns := make([]Message, 0, len(messages))
+	for _, message := range messages {
+		if message.Role == RoleSystem {
+			system = append(system, message.Content)
+			continue
+		}
+		turns = append(turns, message)
+	}
+	return strings.Join(system, "\n\n"), turns
+}
+
+// messagesText returns the contents of a conversation, for token estimates
+// and content checks.
+func messagesText(messages []Message) string {
+	if len(messages) == 1 {
+		return messages[0].Content
+	}
+	contents := make([]string, len(messages))
+	for i, message := range messages {
+		contents[i] = message.Content
+	}
+	return strings.Join(contents, "\n")
+}
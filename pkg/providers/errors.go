@@ -0,0 +1,147 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RefusalError is returned when a model declines to answer a prompt instead
+// of producing output.
+type RefusalError struct {
+	Provider string // Provider name
+	Refusal  string // Refusal message from the model
+}
+
+// Error implements the error interface.
+func (r *RefusalError) Error() string {
+	return fmt.Sprintf("%s model refused the request: %s", r.Provider, r.Refusal)
+}
+
+// SchemaViolationError is returned when model output does not conform to the
+// schema applied with ApplySchema.
+type SchemaViolationError struct {
+	Provider string // Provider name
+	Output   string // Raw model output
+	Reason   string // Why the output was rejected
+	Err      error  // Underlying error, if any
+}
+
+// Error implements the error interface.
+func (s *SchemaViolationError) Error() string {
+	if s.Err != nil {
+		return fmt.Sprintf("%s output violates schema: %s: %v", s.Provider, s.Reason, s.Err)
+	}
+	return fmt.Sprintf("%s output violates schema: %s", s.Provider, s.Reason)
+}
+
+// Unwrap returns the underlying error.
+func (s *SchemaViolationError) Unwrap() error {
+	return s.Err
+}
+
+// ErrCassetteNotFound is returned by a ReplayProvider in replay mode for a
+// prompt that has no recorded response.
+var ErrCassetteNotFound = errors.New("cassette not found")
+
+// PromptError is the error of one prompt of a batch.
+type PromptError struct {
+	Index int   // Index of the prompt in the batch
+	Err   error // Underlying error
+}
+
+// Error implements the error interface.
+func (p *PromptError) Error() string {
+	return fmt.Sprintf("prompt %d: %v", p.Index, p.Err)
+}
+
+// Unwrap returns the underlying error.
+func (p *PromptError) Unwrap() error {
+	return p.Err
+}
+
+// BatchError is returned by Infer when some prompts of a batch failed. The
+// results of the other prompts are returned alongside it, with nil entries
+// for the failed prompts.
+type BatchError struct {
+	Errors []*PromptError // Errors of the failed prompts, in prompt order
+	Total  int            // Number of prompts in the batch
+}
+
+// Error implements the error interface.
+func (b *BatchError) Error() string {
+	if len(b.Errors) == 1 {
+		return b.Errors[0].Error()
+	}
+	return fmt.Sprintf("%d of %d prompts failed, first: %v", len(b.Errors), b.Total, b.Errors[0])
+}
+
+// Unwrap returns the prompt errors, so errors.Is and errors.As match the
+// error of any failed prompt.
+func (b *BatchError) Unwrap() []error {
+	errs := make([]error, len(b.Errors))
+	for i, err := range b.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Classes of provider API errors. Retryable errors are transient and may
+// succeed on a later attempt; the others are terminal. Use errors.Is to test
+// an error for a class.
+var (
+	ErrRateLimited           = errors.New("rate limited")            // retryable
+	ErrServerError           = errors.New("server error")            // retryable
+	ErrTimeout               = errors.New("request timed out")       // retryable
+	ErrAuthentication        = errors.New("authentication failed")   // terminal
+	ErrBadRequest            = errors.New("bad request")             // terminal
+	ErrContextLengthExceeded = errors.New("context length exceeded") // terminal
+)
+
+// IsRetryable reports whether err is a transient error that may succeed if
+// the request is retried: a rate limit, server error or timeout.
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServerError) || errors.Is(err, ErrTimeout)
+}
+
+// APIError is returned when a provider API responds with an error status.
+// It matches its error class with errors.Is.
+type APIError struct {
+	Provider   string        // Provider name
+	StatusCode int           // HTTP status code
+	Message    string        // Error message from the response body
+	RetryAfter time.Duration // Delay requested by a Retry-After header, zero if absent
+	Kind       error         // Error class, one of the Err* errors, nil if unclassified
+}
+
+// Error implements the error interface.
+func (a *APIError) Error() string {
+	if a.Provider != "" {
+		return fmt.Sprintf("%s API request failed with status %d: %s", a.Provider, a.StatusCode, a.Message)
+	}
+	return fmt.Sprintf("API request failed with status %d: %s", a.StatusCode, a.Message)
+}
+
+// Unwrap returns the error class.
+func (a *APIError) Unwrap() error {
+	return a.Kind
+}
+
+// PluginError is returned when a provider plugin answers a request with an
+// error. It matches its error class with errors.Is.
+type PluginError struct {
+	Plugin  string // Plugin name
+	Code    int    // JSON-RPC error code
+	Message string // Error message from the plugin
+	Kind    error  // Error class, one of the Err* errors, nil if unclassified
+}
+
+// Error implements the error interface.
+func (p *PluginError) Error() string {
+	return fmt.Sprintf("plugin %s failed with code %d: %s", p.Plugin, p.Code, p.Message)
+}
+
+// Unwrap returns the error class.
+func (p *PluginError) Unwrap() error {
+	return p.Kind
+}
@@ -73,6 +73,24 @@ func CreateGeminiFlash(apiKey string) (BaseLanguageModel, error) {
 	return CreateGemini("gemini-2.5-flash", opts)
 }
 
+// CreateAnthropic creates an Anthropic provider with the given model ID and options.
+func CreateAnthropic(modelID string, opts *ProviderOptions) (BaseLanguageModel, error) {
+	config := NewModelConfig(modelID).WithProvider("anthropic")
+	
+	if opts != nil {
+		kwargs := make(map[string]any)
+		if opts.APIKey != "" {
+			kwargs["api_key"] = opts.APIKey
+		}
+		if opts.BaseURL != "" {
+			kwargs["base_url"] = opts.BaseURL
+		}
+		config.WithProviderKwargs(kwargs)
+	}
+	
+	return CreateModel(config)
+}
+
 // CreateOllama creates an Ollama provider with the given model ID and options.
 func CreateOllama(modelID string, opts *ProviderOptions) (BaseLanguageModel, error) {
 	config := NewModelConfig(modelID).WithProvider("ollama")
@@ -106,18 +124,12 @@ func MustCreateModel(config *ModelConfig) BaseLanguageModel {
 
 // CreateModelFromEnv creates a model using environment variables for configuration.
 func CreateModelFromEnv(modelID string) (BaseLanguageModel, error) {
-	// Detect provider from model ID
-	var provider string
-	switch {
-	case isOpenAIModel(modelID):
-		provider = "openai"
-	case isGeminiModel(modelID):
-		provider = "gemini"
-	case isOllamaModel(modelID):
-		provider = "ollama"
-	default:
+	// Detect provider from the model catalog
+	model, ok := LookupModel(modelID)
+	if !ok {
 		return nil, fmt.Errorf("cannot determine provider for model ID: %s", modelID)
 	}
+	provider := model.Provider
 	
 	config := NewModelConfig(modelID).WithProvider(provider)
 	
@@ -146,6 +158,16 @@ func CreateModelFromEnv(modelID string) (BaseLanguageModel, error) {
 		}
 	}
 	
+	// Anthropic configuration
+	if provider == "anthropic" {
+		if apiKey := os.Getenv("ANTHROPIC_API_KEY"); apiKey != "" {
+			kwargs["api_key"] = apiKey
+		}
+		if baseURL := os.Getenv("ANTHROPIC_BASE_URL"); baseURL != "" {
+			kwargs["base_url"] = baseURL
+		}
+	}
+	
 	// Ollama configuration
 	if provider == "ollama" {
 		if baseURL := os.Getenv("OLLAMA_BASE_URL"); baseURL != "" {
@@ -162,50 +184,3 @@ func CreateModelFromEnv(modelID string) (BaseLanguageModel, error) {
 	
 	return CreateModel(config)
 }
-
-// isOpenAIModel checks if a model ID belongs to OpenAI.
-func isOpenAIModel(modelID string) bool {
-	openaiModels := []string{
-		"gpt-4", "gpt-4-turbo", "gpt-3.5-turbo", 
-		"gpt-4o", "gpt-4o-mini", "text-davinci-003",
-	}
-	
-	for _, model := range openaiModels {
-		if modelID == model {
-			return true
-		}
-	}
-	return false
-}
-
-// isGeminiModel checks if a model ID belongs to Google Gemini.
-func isGeminiModel(modelID string) bool {
-	geminiModels := []string{
-		"gemini-2.5-flash", "gemini-2.5-pro",
-		"gemini-1.5-pro", "gemini-1.5-flash", 
-		"gemini-pro",
-	}
-	
-	for _, model := range geminiModels {
-		if modelID == model {
-			return true
-		}
-	}
-	return false
-}
-
-// isOllamaModel checks if a model ID belongs to Ollama.
-func isOllamaModel(modelID string) bool {
-	ollamaModels := []string{
-		"llama3.2", "llama3.2:1b", "llama3.2:3b",
-		"llama3.1", "llama3.1:8b", "llama3.1:70b",
-		"codellama", "mistral", "qwen2.5",
-	}
-	
-	for _, model := range ollamaModels {
-		if modelID == model {
-			return true
-		}
-	}
-	return false
-}
\ No newline at end of file
@@ -0,0 +1,340 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeProviderName is the provider name of FakeLanguageModel in the registry.
+const FakeProviderName = "fake"
+
+// FakeResponse is a canned response of a FakeLanguageModel.
+type FakeResponse struct {
+	Output       string
+	Err          error         // Returned instead of the output if set
+	Latency      time.Duration // Delay before responding, the model's latency if zero
+	Usage        Usage         // Token usage, estimated from the prompt and output if zero
+	FinishReason FinishReason  // Finish reason, FinishReasonStop if empty
+}
+
+// fakeRule answers prompts matching a pattern.
+type fakeRule struct {
+	pattern  *regexp.Regexp
+	response FakeResponse
+}
+
+// FakeLanguageModel is a scriptable language model for tests, which answers
+// without any network access. Each prompt is answered by the next scripted
+// response if any remain, then by the first rule whose pattern matches the
+// prompt, then by the default response. A prompt that nothing answers fails.
+//
+// A FakeLanguageModel is safe for concurrent use. Prompts of a batch are
+// answered in order, so scripted responses are consumed deterministically.
+type FakeLanguageModel struct {
+	mu          sync.Mutex
+	modelID     string
+	script      []FakeResponse
+	rules       []fakeRule
+	fallback    *FakeResponse
+	latency     time.Duration
+	prompts     []string
+	schema      any
+	fenceOutput bool
+}
+
+// NewFakeLanguageModel creates a fake language model without responses.
+func NewFakeLanguageModel(modelID string) *FakeLanguageModel {
+	if modelID == "" {
+		modelID = FakeProviderName
+	}
+	return &FakeLanguageModel{modelID: modelID}
+}
+
+// WithRule answers prompts matching a regular expression with a response.
+// Rules are tried in the order they were added. It panics if the pattern
+// does not compile, like regexp.MustCompile.
+func (f *FakeLanguageModel) WithRule(pattern string, response FakeResponse) *FakeLanguageModel {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = append(f.rules, fakeRule{pattern: regexp.MustCompile(pattern), response: response})
+	return f
+}
+
+// WithOutput answers prompts matching a regular expression with an output.
+func (f *FakeLanguageModel) WithOutput(pattern, output string) *FakeLanguageModel {
+	return f.WithRule(pattern, FakeResponse{Output: output})
+}
+
+// WithScript queues responses that answer the next prompts in order, ahead
+// of the rules.
+func (f *FakeLanguageModel) WithScript(responses ...FakeResponse) *FakeLanguageModel {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.script = append(f.script, responses...)
+	return f
+}
+
+// WithDefault sets the response to prompts that no script or rule answers.
+func (f *FakeLanguageModel) WithDefault(response FakeResponse) *FakeLanguageModel {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fallback = &response
+	return f
+}
+
+// WithLatency sets the delay of responses that set no latency of their own.
+func (f *FakeLanguageModel) WithLatency(latency time.Duration) *FakeLanguageModel {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.latency = latency
+	return f
+}
+
+// Prompts returns the prompts received so far, in order.
+func (f *FakeLanguageModel) Prompts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.prompts...)
+}
+
+// Schema returns the schema last applied with ApplySchema.
+func (f *FakeLanguageModel) Schema() any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.schema
+}
+
+// Infer generates model output for the given prompts.
+func (f *FakeLanguageModel) Infer(ctx context.Context, prompts []string, options map[string]any) ([][]ScoredOutput, error) {
+	return inferOutputs(f.InferDetailed(ctx, prompts, options))
+}
+
+// InferDetailed answers the given prompts with their canned responses. Failed
+// prompts are reported in a *BatchError, like the other providers.
+func (f *FakeLanguageModel) InferDetailed(ctx context.Context, prompts []string, options map[string]any) ([]*InferenceResult, error) {
+	results := make([]*InferenceResult, len(prompts))
+	var batchErr *BatchError
+	for i, prompt := range prompts {
+		result, err := f.respond(ctx, prompt)
+		if err != nil {
+			if batchErr == nil {
+				batchErr = &BatchError{Total: len(prompts)}
+			}
+			batchErr.Errors = append(batchErr.Errors, &PromptError{Index: i, Err: err})
+			continue
+		}
+		results[i] = result
+	}
+	if batchErr != nil {
+		return results, batchErr
+	}
+	return results, nil
+}
+
+// InferStream answers a prompt as a stream of a single delta followed by the
+// final chunk.
+func (f *FakeLanguageModel) InferStream(ctx context.Context, prompt string, options map[string]any) (<-chan StreamChunk, error) {
+	result, err := f.respond(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make(chan StreamChunk, 2)
+	chunks <- StreamChunk{Delta: result.Outputs[0].Output}
+	chunks <- StreamChunk{Done: true, FinishReason: result.FinishReason, Usage: result.Usage}
+	close(chunks)
+	return chunks, nil
+}
+
+// respond answers one prompt, waiting for the response latency.
+func (f *FakeLanguageModel) respond(ctx context.Context, prompt string) (*InferenceResult, error) {
+	response, err := f.next(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Latency > 0 {
+		timer := time.NewTimer(response.Latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	} else if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if response.Err != nil {
+		return nil, response.Err
+	}
+
+	usage := response.Usage
+	if usage == (Usage{}) {
+		usage.PromptTokens = estimateTokens(prompt)
+		usage.CompletionTokens = estimateTokens(response.Output)
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	finishReason := response.FinishReason
+	if finishReason == "" {
+		finishReason = FinishReasonStop
+	}
+
+	return &InferenceResult{
+		Outputs:      []ScoredOutput{{Output: response.Output, Score: 1.0}},
+		Usage:        usage,
+		FinishReason: finishReason,
+		Latency:      response.Latency,
+	}, nil
+}
+
+// next records a prompt and selects its response.
+func (f *FakeLanguageModel) next(prompt string) (FakeResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.prompts = append(f.prompts, prompt)
+
+	var response FakeResponse
+	switch {
+	case len(f.script) > 0:
+		response = f.script[0]
+		f.script = f.script[1:]
+	default:
+		matched := false
+		for _, rule := range f.rules {
+			if rule.pattern.MatchString(prompt) {
+				response, matched = rule.response, true
+				break
+			}
+		}
+		if !matched {
+			if f.fallback == nil {
+				return FakeResponse{}, fmt.Errorf("fake model %s has no response for prompt %q", f.modelID, truncatePrompt(prompt))
+			}
+			response = *f.fallback
+		}
+	}
+
+	if response.Latency == 0 {
+		response.Latency = f.latency
+	}
+	return response, nil
+}
+
+// truncatePrompt shortens a prompt for error messages.
+func truncatePrompt(prompt string) string {
+	const maxLength = 80
+	if len(prompt) <= maxLength {
+		return prompt
+	}
+	return prompt[:maxLength] + "..."
+}
+
+// ParseOutput processes raw model output into structured format.
+func (f *FakeLanguageModel) ParseOutput(output string) (any, error) {
+	cleanOutput := strings.TrimSpace(output)
+	cleanOutput = strings.TrimPrefix(cleanOutput, "```json")
+	cleanOutput = strings.TrimPrefix(cleanOutput, "```")
+	cleanOutput = strings.TrimSpace(strings.TrimSuffix(cleanOutput, "```"))
+
+	var result any
+	if err := json.Unmarshal([]byte(cleanOutput), &result); err != nil {
+		return cleanOutput, nil
+	}
+	return result, nil
+}
+
+// ApplySchema records the schema, which can be read back with Schema.
+func (f *FakeLanguageModel) ApplySchema(schema any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.schema = schema
+}
+
+// SetFenceOutput configures whether output should be fenced.
+func (f *FakeLanguageModel) SetFenceOutput(enabled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fenceOutput = enabled
+}
+
+// GetModelID returns the model identifier.
+func (f *FakeLanguageModel) GetModelID() string {
+	return f.modelID
+}
+
+// IsAvailable reports that the fake model is always available.
+func (f *FakeLanguageModel) IsAvailable() bool {
+	return true
+}
+
+// NewFakeProvider creates a fake language model from a model config, for the
+// "fake" provider. It is not registered by default; tests register it with
+// Register(FakeProviderName, NewFakeProvider). The model is configured with
+// provider kwargs:
+//
+//   - "output": default output, for prompts no script or rule answers
+//   - "responses": list of outputs answering the first prompts in order
+//   - "rules": map of regular expressions to outputs, tried in sorted order
+//   - "latency": latency of each response, as a duration string such as "50ms"
+func NewFakeProvider(config *ModelConfig) (BaseLanguageModel, error) {
+	model := NewFakeLanguageModel(config.ModelID)
+	kwargs := config.ProviderKwargs
+	if kwargs == nil {
+		return model, nil
+	}
+
+	if output, ok := kwargs["output"].(string); ok {
+		model.WithDefault(FakeResponse{Output: output})
+	}
+
+	switch responses := kwargs["responses"].(type) {
+	case []string:
+		for _, output := range responses {
+			model.WithScript(FakeResponse{Output: output})
+		}
+	case []any:
+		for _, value := range responses {
+			output, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("fake provider responses must be strings, got %T", value)
+			}
+			model.WithScript(FakeResponse{Output: output})
+		}
+	}
+
+	if rules, ok := kwargs["rules"].(map[string]any); ok {
+		patterns := make([]string, 0, len(rules))
+		for pattern := range rules {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+		for _, pattern := range patterns {
+			output, ok := rules[pattern].(string)
+			if !ok {
+				return nil, fmt.Errorf("fake provider rule %q must map to a string", pattern)
+			}
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("invalid fake provider rule %q: %w", pattern, err)
+			}
+			model.WithOutput(pattern, output)
+		}
+	}
+
+	if latency, ok := kwargs["latency"].(string); ok {
+		d, err := time.ParseDuration(latency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fake provider latency: %w", err)
+		}
+		model.WithLatency(d)
+	}
+
+	return model, nil
+}
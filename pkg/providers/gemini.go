@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"os"
 	"strings"
@@ -18,18 +19,28 @@ type GeminiProvider struct {
 	apiKey      string
 	baseURL     string
 	client      *http.Client
+	timeout     time.Duration // timeout of requests whose context has no deadline
 	schema      any
 	fenceOutput bool
+	limit       requestLimit
+	rate        *rateLimiter
+	retry       *RetryPolicy
+
+	// Gemini form of the applied schema, nil if it could not be translated
+	responseSchema *GeminiSchema
 }
 
 // GeminiRequest represents a Gemini API request.
 type GeminiRequest struct {
-	Contents         []GeminiContent         `json:"contents"`
-	GenerationConfig *GeminiGenerationConfig `json:"generationConfig,omitempty"`
+	SystemInstruction *GeminiContent          `json:"systemInstruction,omitempty"`
+	Contents          []GeminiContent         `json:"contents"`
+	GenerationConfig  *GeminiGenerationConfig `json:"generationConfig,omitempty"`
 }
 
-// GeminiContent represents the content part of a Gemini request.
+// GeminiContent represents the content part of a Gemini request. The role
+// is "user" or "model", and is omitted for the system instruction.
 type GeminiContent struct {
+	Role  string       `json:"role,omitempty"`
 	Parts []GeminiPart `json:"parts"`
 }
 
@@ -40,22 +51,25 @@ type GeminiPart struct {
 
 // GeminiGenerationConfig represents generation configuration.
 type GeminiGenerationConfig struct {
-	Temperature      *float64 `json:"temperature,omitempty"`
-	TopP             *float64 `json:"topP,omitempty"`
-	MaxOutputTokens  *int     `json:"maxOutputTokens,omitempty"`
-	ResponseMimeType string   `json:"responseMimeType,omitempty"`
+	Temperature      *float64      `json:"temperature,omitempty"`
+	TopP             *float64      `json:"topP,omitempty"`
+	MaxOutputTokens  *int          `json:"maxOutputTokens,omitempty"`
+	ResponseMimeType string        `json:"responseMimeType,omitempty"`
+	ResponseSchema   *GeminiSchema `json:"responseSchema,omitempty"`
 }
 
 // GeminiResponse represents a Gemini API response.
 type GeminiResponse struct {
 	Candidates    []GeminiCandidate `json:"candidates"`
 	UsageMetadata GeminiUsage       `json:"usageMetadata"`
+	ResponseID    string            `json:"responseId,omitempty"`
 }
 
 // GeminiCandidate represents a response candidate.
 type GeminiCandidate struct {
-	Content GeminiContent `json:"content"`
-	Index   int           `json:"index"`
+	Content      GeminiContent `json:"content"`
+	Index        int           `json:"index"`
+	FinishReason string        `json:"finishReason,omitempty"`
 }
 
 // GeminiUsage represents token usage information.
@@ -91,26 +105,44 @@ func NewGeminiProvider(config *ModelConfig) (BaseLanguageModel, error) {
 		}
 	}
 
+	client, timeout, err := newHTTPClient(config, 30*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP configuration: %w", err)
+	}
+
 	return &GeminiProvider{
 		config:  config,
 		apiKey:  apiKey,
 		baseURL: baseURL,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		limit:   newRequestLimit(config),
+		rate:    sharedRateLimiter("gemini", config),
+		retry:   retryPolicy(config),
+		client:  client,
+		timeout: timeout,
 	}, nil
 }
 
 // Infer generates model output for the given prompts.
 func (p *GeminiProvider) Infer(ctx context.Context, prompts []string, options map[string]any) ([][]ScoredOutput, error) {
-	results := make([][]ScoredOutput, len(prompts))
-	
-	for i, prompt := range prompts {
-		response, err := p.generateCompletion(ctx, prompt)
+	return inferOutputs(p.InferDetailed(ctx, prompts, options))
+}
+
+// InferDetailed generates model output for the given prompts, with token
+// usage, finish reason and response ID.
+func (p *GeminiProvider) InferDetailed(ctx context.Context, prompts []string, options map[string]any) ([]*InferenceResult, error) {
+	return p.InferMessages(ctx, promptConversations(prompts), options)
+}
+
+// InferMessages generates model output for each conversation. System
+// messages are sent as the systemInstruction and assistant messages as
+// model turns.
+func (p *GeminiProvider) InferMessages(ctx context.Context, conversations [][]Message, options map[string]any) ([]*InferenceResult, error) {
+	return inferBatch(ctx, p.limit, p.rate, conversations, func(ctx context.Context, messages []Message) (*InferenceResult, error) {
+		response, err := p.generateCompletion(ctx, messages)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate completion for prompt %d: %w", i, err)
+			return nil, fmt.Errorf("failed to generate completion: %w", err)
 		}
-		
+
 		outputs := make([]ScoredOutput, len(response.Candidates))
 		for j, candidate := range response.Candidates {
 			text := ""
@@ -122,22 +154,55 @@ func (p *GeminiProvider) Infer(ctx context.Context, prompts []string, options ma
 				Score:  1.0, // Gemini doesn't provide scores, use default
 			}
 		}
-		results[i] = outputs
+
+		result := &InferenceResult{
+			Outputs: outputs,
+			Usage: Usage{
+				PromptTokens:     response.UsageMetadata.PromptTokenCount,
+				CompletionTokens: response.UsageMetadata.CandidatesTokenCount,
+				TotalTokens:      response.UsageMetadata.TotalTokenCount,
+			},
+			ResponseID: response.ResponseID,
+		}
+		if len(response.Candidates) > 0 {
+			result.FinishReason = geminiFinishReason(response.Candidates[0].FinishReason)
+		}
+		return result, nil
+	})
+}
+
+// geminiFinishReason normalizes a Gemini candidate finish reason.
+func geminiFinishReason(reason string) FinishReason {
+	switch reason {
+	case "STOP":
+		return FinishReasonStop
+	case "MAX_TOKENS":
+		return FinishReasonLength
+	case "SAFETY", "RECITATION", "BLOCKLIST", "PROHIBITED_CONTENT", "SPII", "IMAGE_SAFETY":
+		return FinishReasonSafety
+	case "":
+		return ""
 	}
-	
-	return results, nil
+	return FinishReasonOther
 }
 
-// generateCompletion makes a request to the Gemini API.
-func (p *GeminiProvider) generateCompletion(ctx context.Context, prompt string) (*GeminiResponse, error) {
-	request := GeminiRequest{
-		Contents: []GeminiContent{
-			{
-				Parts: []GeminiPart{
-					{Text: prompt},
-				},
-			},
-		},
+// newHTTPRequest creates the HTTP request for a conversation. The method is
+// "generateContent" or "streamGenerateContent".
+func (p *GeminiProvider) newHTTPRequest(ctx context.Context, messages []Message, method string) (*http.Request, error) {
+	var request GeminiRequest
+	system, turns := splitSystemMessages(messages)
+	if system != "" {
+		request.SystemInstruction = &GeminiContent{Parts: []GeminiPart{{Text: system}}}
+	}
+	for _, message := range turns {
+		role := "user"
+		if message.Role == RoleAssistant {
+			role = "model"
+		}
+		request.Contents = append(request.Contents, GeminiContent{
+			Role:  role,
+			Parts: []GeminiPart{{Text: message.Content}},
+		})
 	}
 
 	// Add generation config if needed
@@ -155,12 +220,13 @@ func (p *GeminiProvider) generateCompletion(ctx context.Context, prompt string)
 		}
 	}
 
-	// Enable JSON output if schema is applied
+	// Enable JSON output constrained to the schema if one is applied
 	if p.schema != nil {
 		if request.GenerationConfig == nil {
 			request.GenerationConfig = &GeminiGenerationConfig{}
 		}
 		request.GenerationConfig.ResponseMimeType = "application/json"
+		request.GenerationConfig.ResponseSchema = p.responseSchema
 	}
 
 	requestBody, err := json.Marshal(request)
@@ -168,7 +234,10 @@ func (p *GeminiProvider) generateCompletion(ctx context.Context, prompt string)
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/models/%s:generateContent", p.baseURL, p.config.ModelID)
+	url := fmt.Sprintf("%s/models/%s:%s", p.baseURL, p.config.ModelID, method)
+	if method == "streamGenerateContent" {
+		url += "?alt=sse"
+	}
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(requestBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -176,17 +245,21 @@ func (p *GeminiProvider) generateCompletion(ctx context.Context, prompt string)
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("x-goog-api-key", p.apiKey)
+	return req, nil
+}
 
-	resp, err := p.client.Do(req)
+// generateCompletion makes a request to the Gemini API.
+func (p *GeminiProvider) generateCompletion(ctx context.Context, messages []Message) (*GeminiResponse, error) {
+	req, err := p.newHTTPRequest(ctx, messages, "generateContent")
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	resp, err := doRequest(p.client, p.retry, p.timeout, "gemini", req)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
 	var response GeminiResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
@@ -196,6 +269,78 @@ func (p *GeminiProvider) generateCompletion(ctx context.Context, prompt string)
 	return &response, nil
 }
 
+// InferStream streams the output for a prompt with streamGenerateContent,
+// using server-sent events.
+func (p *GeminiProvider) InferStream(ctx context.Context, prompt string, options map[string]any) (<-chan StreamChunk, error) {
+	slot, err := acquireStream(ctx, p.limit, p.rate, estimateTokens(prompt))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := p.newHTTPRequest(ctx, []Message{{Role: RoleUser, Content: prompt}}, "streamGenerateContent")
+	if err != nil {
+		slot.finish(Usage{}, err)
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	body, err := streamEvents(p.client, p.retry, "gemini", req)
+	if err != nil {
+		slot.finish(Usage{}, err)
+		return nil, fmt.Errorf("gemini stream failed: %w", err)
+	}
+
+	return startStream(ctx, body, slot, func(r io.Reader, send func(StreamChunk) bool) error {
+		final := StreamChunk{Done: true}
+		finishReason := ""
+
+		err := readSSE(r, func(data []byte) error {
+			var response GeminiResponse
+			if err := json.Unmarshal(data, &response); err != nil {
+				return fmt.Errorf("failed to decode stream event: %w", err)
+			}
+			if response.ResponseID != "" {
+				final.ResponseID = response.ResponseID
+			}
+			// Usage metadata is cumulative, so the last report wins
+			if response.UsageMetadata.TotalTokenCount > 0 {
+				final.Usage = Usage{
+					PromptTokens:     response.UsageMetadata.PromptTokenCount,
+					CompletionTokens: response.UsageMetadata.CandidatesTokenCount,
+					TotalTokens:      response.UsageMetadata.TotalTokenCount,
+				}
+			}
+			if len(response.Candidates) == 0 {
+				return nil
+			}
+
+			candidate := response.Candidates[0]
+			if candidate.FinishReason != "" {
+				finishReason = candidate.FinishReason
+			}
+			var delta strings.Builder
+			for _, part := range candidate.Content.Parts {
+				delta.WriteString(part.Text)
+			}
+			if delta.Len() > 0 && !send(StreamChunk{Delta: delta.String()}) {
+				return errStreamStopped
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if finishReason == "" {
+			return fmt.Errorf("stream ended before the response was complete")
+		}
+
+		final.FinishReason = geminiFinishReason(finishReason)
+		slot.finish(final.Usage, nil)
+		send(final)
+		return nil
+	}), nil
+}
+
 // ParseOutput processes raw model output into structured format.
 func (p *GeminiProvider) ParseOutput(output string) (any, error) {
 	// Try to parse as JSON first
@@ -233,9 +378,22 @@ func (p *GeminiProvider) cleanOutput(output string) string {
 	return output
 }
 
-// ApplySchema applies schema constraints to the model.
+// ApplySchema applies schema constraints to the model. The schema is
+// translated with ToGeminiSchema and sent as responseSchema; schemas that
+// cannot be translated are logged and only enable JSON output.
 func (p *GeminiProvider) ApplySchema(schema any) {
 	p.schema = schema
+	p.responseSchema = nil
+
+	if schema == nil {
+		return
+	}
+	responseSchema, err := ToGeminiSchema(schema)
+	if err != nil {
+		log.Printf("gemini: schema not supported as responseSchema, requesting plain JSON: %v", err)
+		return
+	}
+	p.responseSchema = responseSchema
 }
 
 // SetFenceOutput configures whether output should be fenced.
@@ -0,0 +1,189 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GeminiSchema is the OpenAPI 3.0 subset accepted by Gemini as responseSchema.
+type GeminiSchema struct {
+	Type             string                   `json:"type,omitempty"`
+	Format           string                   `json:"format,omitempty"`
+	Description      string                   `json:"description,omitempty"`
+	Nullable         bool                     `json:"nullable,omitempty"`
+	Enum             []string                 `json:"enum,omitempty"`
+	Properties       map[string]*GeminiSchema `json:"properties,omitempty"`
+	Required         []string                 `json:"required,omitempty"`
+	PropertyOrdering []string                 `json:"propertyOrdering,omitempty"`
+	Items            *GeminiSchema            `json:"items,omitempty"`
+	MinItems         *int                     `json:"minItems,omitempty"`
+	MaxItems         *int                     `json:"maxItems,omitempty"`
+	Minimum          *float64                 `json:"minimum,omitempty"`
+	Maximum          *float64                 `json:"maximum,omitempty"`
+}
+
+// ToGeminiSchema translates a JSON Schema, such as the output of
+// BasicExtractionSchema.ToJSONSchema, into Gemini's schema format.
+//
+// The translation keeps type, description, enum, properties, required, items,
+// minItems, maxItems, minimum and maximum. Types are upper-cased, a type list
+// of one type and "null" becomes a nullable type, and enums become string
+// enums. Object properties are ordered with the required properties first, in
+// their declared order, followed by the others by name.
+//
+// The following constructs have no Gemini equivalent and are dropped:
+//   - $schema, $id, $ref, $defs and definitions
+//   - title, default, examples and const
+//   - additionalProperties and patternProperties
+//   - pattern, minLength, maxLength and format of strings
+//   - exclusiveMinimum, exclusiveMaximum and multipleOf
+//   - uniqueItems, minProperties and maxProperties
+//   - allOf, oneOf, not and anyOf other than a nullable type
+//
+// Arrays without items get string items, since Gemini requires them. Schemas
+// without a type, such as those of interface fields, and objects without
+// properties, which Gemini rejects, become strings.
+func ToGeminiSchema(schema any) (*GeminiSchema, error) {
+	root, err := schemaToMap(schema)
+	if err != nil {
+		return nil, err
+	}
+	return translateGeminiSchema(root, "$")
+}
+
+func translateGeminiSchema(node map[string]any, path string) (*GeminiSchema, error) {
+	result := &GeminiSchema{}
+	result.Description, _ = node["description"].(string)
+
+	// anyOf is only supported as a nullable wrapper: {"anyOf": [schema, {"type": "null"}]}
+	if options := schemaList(node["anyOf"]); len(options) > 0 {
+		var inner map[string]any
+		nullable := false
+		for _, option := range options {
+			if option["type"] == "null" {
+				nullable = true
+			} else if inner == nil {
+				inner = option
+			} else {
+				return nil, fmt.Errorf("%s: anyOf with several schemas is not supported", path)
+			}
+		}
+		if inner == nil {
+			return nil, fmt.Errorf("%s: anyOf has no non-null schema", path)
+		}
+		translated, err := translateGeminiSchema(inner, path)
+		if err != nil {
+			return nil, err
+		}
+		translated.Nullable = translated.Nullable || nullable
+		if translated.Description == "" {
+			translated.Description = result.Description
+		}
+		return translated, nil
+	}
+
+	typ := ""
+	for _, t := range typeList(node["type"]) {
+		switch {
+		case t == "null":
+			result.Nullable = true
+		case typ == "":
+			typ = t
+		default:
+			return nil, fmt.Errorf("%s: type union %v is not supported", path, node["type"])
+		}
+	}
+
+	if enum, ok := node["enum"].([]any); ok && len(enum) > 0 {
+		for _, value := range enum {
+			if value == nil {
+				result.Nullable = true
+				continue
+			}
+			result.Enum = append(result.Enum, fmt.Sprint(value))
+		}
+		result.Type = "STRING"
+		result.Format = "enum"
+		return result, nil
+	}
+
+	switch typ {
+	case "string", "number", "integer", "boolean":
+		result.Type = strings.ToUpper(typ)
+		result.Minimum = numberPointer(node["minimum"])
+		result.Maximum = numberPointer(node["maximum"])
+
+	case "array":
+		result.Type = "ARRAY"
+		result.MinItems = intPointer(node["minItems"])
+		result.MaxItems = intPointer(node["maxItems"])
+		items, ok := node["items"].(map[string]any)
+		if !ok {
+			result.Items = &GeminiSchema{Type: "STRING"}
+			break
+		}
+		translated, err := translateGeminiSchema(items, path+"[]")
+		if err != nil {
+			return nil, err
+		}
+		result.Items = translated
+
+	case "object", "":
+		properties, _ := node["properties"].(map[string]any)
+		if len(properties) == 0 {
+			result.Type = "STRING"
+			break
+		}
+		result.Type = "OBJECT"
+
+		result.Properties = make(map[string]*GeminiSchema, len(properties))
+		for name, value := range properties {
+			property, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s.%s: property schema must be an object", path, name)
+			}
+			translated, err := translateGeminiSchema(property, path+"."+name)
+			if err != nil {
+				return nil, err
+			}
+			result.Properties[name] = translated
+		}
+
+		seen := make(map[string]bool)
+		for _, name := range stringList(node["required"]) {
+			if _, ok := result.Properties[name]; ok && !seen[name] {
+				result.Required = append(result.Required, name)
+				seen[name] = true
+			}
+		}
+		var optional []string
+		for name := range result.Properties {
+			if !seen[name] {
+				optional = append(optional, name)
+			}
+		}
+		sort.Strings(optional)
+		result.PropertyOrdering = append(append([]string(nil), result.Required...), optional...)
+
+	default:
+		return nil, fmt.Errorf("%s: unsupported type %q", path, typ)
+	}
+
+	return result, nil
+}
+
+func numberPointer(value any) *float64 {
+	if n, ok := value.(float64); ok {
+		return &n
+	}
+	return nil
+}
+
+func intPointer(value any) *int {
+	if n, ok := value.(float64); ok {
+		i := int(n)
+		return &i
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package providers
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultMaxConcurrency is the number of concurrent requests of a provider
+// when ModelConfig.MaxConcurrency is not set.
+const defaultMaxConcurrency = 4
+
+// FinishReason is the normalized reason a model stopped generating.
+type FinishReason string
+
+const (
+	FinishReasonStop   FinishReason = "stop"   // natural end, stop sequence or tool call
+	FinishReasonLength FinishReason = "length" // token limit reached
+	FinishReasonSafety FinishReason = "safety" // blocked by safety filters or refused
+	FinishReasonOther  FinishReason = "other"  // any other reason reported by the provider
+)
+
+// InferenceResult is the result of one prompt with the metadata reported by
+// the provider.
+type InferenceResult struct {
+	Outputs      []ScoredOutput
+	Usage        Usage         // Token usage, zero if not reported
+	FinishReason FinishReason  // Finish reason of the first output, empty if not reported
+	ResponseID   string        // Provider response identifier, empty if not reported
+	Latency      time.Duration // Time taken by the provider
+}
+
+// DetailedLanguageModel is implemented by providers that report token usage,
+// finish reasons and response identifiers.
+type DetailedLanguageModel interface {
+	BaseLanguageModel
+
+	// InferDetailed generates model output for the given prompts, returning
+	// one result per prompt in prompt order. If some prompts fail, the other
+	// results are returned with a *BatchError.
+	InferDetailed(ctx context.Context, prompts []string, options map[string]any) ([]*InferenceResult, error)
+}
+
+// InferDetailed runs inference and returns results with metadata. Providers
+// that do not implement DetailedLanguageModel get results without usage or
+// finish reasons, and with the latency of the call spread evenly over the
+// prompts. As with Infer, a *BatchError may come with partial results.
+func InferDetailed(ctx context.Context, model BaseLanguageModel, prompts []string, options map[string]any) ([]*InferenceResult, error) {
+	if detailed, ok := model.(DetailedLanguageModel); ok {
+		return detailed.InferDetailed(ctx, prompts, options)
+	}
+
+	start := time.Now()
+	outputs, err := model.Infer(ctx, prompts, options)
+	if outputs == nil {
+		return nil, err
+	}
+
+	results := make([]*InferenceResult, len(outputs))
+	for i, output := range outputs {
+		if output != nil {
+			results[i] = &InferenceResult{Outputs: output}
+		}
+	}
+	if len(results) > 0 {
+		latency := time.Since(start) / time.Duration(len(results))
+		for _, result := range results {
+			if result != nil {
+				result.Latency = latency
+			}
+		}
+	}
+	return results, err
+}
+
+// inferOutputs converts detailed results into the outputs returned by Infer.
+// Partial results of a *BatchError are kept.
+func inferOutputs(results []*InferenceResult, err error) ([][]ScoredOutput, error) {
+	if results == nil {
+		return nil, err
+	}
+	outputs := make([][]ScoredOutput, len(results))
+	for i, result := range results {
+		if result != nil {
+			outputs[i] = result.Outputs
+		}
+	}
+	return outputs, err
+}
+
+// requestLimit bounds the requests a provider has in flight. It is shared by
+// all Infer calls on the provider.
+type requestLimit chan struct{}
+
+// newRequestLimit creates the request limit for a model config.
+func newRequestLimit(config *ModelConfig) requestLimit {
+	limit := config.MaxConcurrency
+	if limit <= 0 {
+		limit = defaultMaxConcurrency
+	}
+	return make(requestLimit, limit)
+}
+
+// inferBatch runs infer for each conversation concurrently, within the
+// request limit and the rate limits, and returns the results in order with
+// their latency set. Prompts are passed as conversations of a single user
+// message. Failed conversations have nil results and are reported together
+// in a *BatchError; those not started when ctx is cancelled fail with the
+// context error.
+func inferBatch(ctx context.Context, limit requestLimit, rate *rateLimiter, conversations [][]Message, infer func(ctx context.Context, messages []Message) (*InferenceResult, error)) ([]*InferenceResult, error) {
+	results := make([]*InferenceResult, len(conversations))
+	errs := make([]error, len(conversations))
+
+	workers := cap(limit)
+	if workers > len(conversations) {
+		workers = len(conversations)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				// The slot may be held by other calls, so it is taken before
+				// reserving from the rate limits
+				select {
+				case limit <- struct{}{}:
+				case <-ctx.Done():
+					errs[i] = ctx.Err()
+					continue
+				}
+				reserved, err := rate.wait(ctx, estimateTokens(messagesText(conversations[i])))
+				if err != nil {
+					<-limit
+					errs[i] = err
+					continue
+				}
+				start := time.Now()
+				result, err := infer(ctx, conversations[i])
+				<-limit
+
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				rate.reconcile(reserved, result.Usage)
+				result.Latency = time.Since(start)
+				results[i] = result
+			}
+		}()
+	}
+	for i := range conversations {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var batchErr *BatchError
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if batchErr == nil {
+			batchErr = &BatchError{Total: len(conversations)}
+		}
+		batchErr.Errors = append(batchErr.Errors, &PromptError{Index: i, Err: err})
+	}
+	if batchErr != nil {
+		return results, batchErr
+	}
+	return results, nil
+}
@@ -0,0 +1,274 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// schemaNamePattern matches the characters allowed in a structured output schema name.
+var schemaNamePattern = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// schemaToMap converts a schema given as a map or struct into a generic map.
+func schemaToMap(schema any) (map[string]any, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("schema must be a JSON object: %w", err)
+	}
+	return result, nil
+}
+
+// strictJSONSchema converts a JSON Schema into the subset accepted by strict
+// structured outputs: every object lists all of its properties as required and
+// forbids additional properties, and properties that were optional become
+// nullable. It returns the schema name, taken from the root title.
+func strictJSONSchema(schema any) (string, map[string]any, error) {
+	root, err := schemaToMap(schema)
+	if err != nil {
+		return "", nil, err
+	}
+	if root["type"] != "object" {
+		return "", nil, fmt.Errorf("schema root must be an object, got type %v", root["type"])
+	}
+
+	name, _ := root["title"].(string)
+	name = schemaNamePattern.ReplaceAllString(name, "_")
+	if name == "" || name == "_" {
+		name = "extractions"
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	delete(root, "$schema")
+	makeStrict(root)
+	return name, root, nil
+}
+
+// makeStrict rewrites a schema node in place for strict mode.
+func makeStrict(node map[string]any) {
+	// Keywords rejected by strict mode
+	delete(node, "minLength")
+	delete(node, "maxLength")
+	delete(node, "default")
+
+	for _, option := range schemaList(node["anyOf"]) {
+		makeStrict(option)
+	}
+
+	switch node["type"] {
+	case "object":
+		properties, _ := node["properties"].(map[string]any)
+		if properties == nil {
+			properties = make(map[string]any)
+			node["properties"] = properties
+		}
+
+		required := make(map[string]bool)
+		for _, name := range stringList(node["required"]) {
+			required[name] = true
+		}
+
+		names := make([]string, 0, len(properties))
+		for name, value := range properties {
+			property, ok := value.(map[string]any)
+			if !ok {
+				continue
+			}
+			makeStrict(property)
+			if !required[name] {
+				properties[name] = nullable(property)
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		node["required"] = names
+		node["additionalProperties"] = false
+
+	case "array":
+		items, ok := node["items"].(map[string]any)
+		if !ok {
+			// Array attributes hold scalar values
+			items = map[string]any{"anyOf": []any{
+				map[string]any{"type": "string"},
+				map[string]any{"type": "number"},
+				map[string]any{"type": "boolean"},
+			}}
+			node["items"] = items
+		}
+		makeStrict(items)
+	}
+}
+
+// nullable allows a schema node to also be null.
+func nullable(node map[string]any) map[string]any {
+	if typ, ok := node["type"].(string); ok && node["enum"] == nil {
+		node["type"] = []any{typ, "null"}
+		return node
+	}
+	return map[string]any{"anyOf": []any{node, map[string]any{"type": "null"}}}
+}
+
+// validateJSONSchema checks a decoded JSON value against a schema. It supports
+// the keywords produced by strictJSONSchema: type, properties, required,
+// additionalProperties, items, enum and anyOf.
+func validateJSONSchema(schema map[string]any, value any) error {
+	return validateNode(schema, value, "$")
+}
+
+func validateNode(schema map[string]any, value any, path string) error {
+	if options := schemaList(schema["anyOf"]); len(options) > 0 {
+		for _, option := range options {
+			if validateNode(option, value, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: value does not match any allowed schema", path)
+	}
+
+	if types := typeList(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, typ := range types {
+			if matchesType(typ, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: expected %s, got %s", path, strings.Join(types, " or "), jsonTypeName(value))
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && value != nil {
+		allowed := false
+		for _, option := range enum {
+			if reflect.DeepEqual(option, value) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("%s: value %v is not one of %v", path, value, enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		for _, name := range stringList(schema["required"]) {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		for name, propertyValue := range v {
+			property, ok := properties[name].(map[string]any)
+			if !ok {
+				if schema["additionalProperties"] == false {
+					return fmt.Errorf("%s: unexpected property %q", path, name)
+				}
+				continue
+			}
+			if err := validateNode(property, propertyValue, path+"."+name); err != nil {
+				return err
+			}
+		}
+
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				if err := validateNode(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func matchesType(typ string, value any) bool {
+	switch typ {
+	case "null":
+		return value == nil
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	}
+	return true
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func typeList(value any) []string {
+	if typ, ok := value.(string); ok {
+		return []string{typ}
+	}
+	return stringList(value)
+}
+
+func stringList(value any) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []any:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func schemaList(value any) []map[string]any {
+	list, _ := value.([]any)
+	result := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		if node, ok := item.(map[string]any); ok {
+			result = append(result, node)
+		}
+	}
+	return result
+}
@@ -7,42 +7,70 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+// ollamaOptionKwargs are the provider kwargs passed through as Ollama model options.
+var ollamaOptionKwargs = []string{"num_ctx", "num_predict", "seed"}
+
 // OllamaProvider implements the BaseLanguageModel interface for Ollama local models.
 type OllamaProvider struct {
 	config      *ModelConfig
 	baseURL     string
 	client      *http.Client
+	timeout     time.Duration // timeout of requests whose context has no deadline
 	schema      any
 	fenceOutput bool
+	limit       requestLimit
+	rate        *rateLimiter
+	retry       *RetryPolicy
+
+	// Whether the server accepts a JSON schema as format, detected from the
+	// server version unless set with the "structured_outputs" kwarg
+	schemaFormatMu    sync.Mutex
+	schemaFormat      bool
+	schemaFormatKnown bool
+
+	usageMu sync.Mutex
+	usage   Usage
 }
 
-// OllamaRequest represents an Ollama API request.
+// OllamaRequest represents an Ollama API request, to the generate endpoint
+// with a Prompt or to the chat endpoint with Messages.
 type OllamaRequest struct {
 	Model    string                 `json:"model"`
-	Prompt   string                 `json:"prompt"`
+	Prompt   string                 `json:"prompt,omitempty"`
+	Messages []Message              `json:"messages,omitempty"`
 	Stream   bool                   `json:"stream"`
+	Format   any                    `json:"format,omitempty"` // "json" or a JSON schema
 	Options  map[string]interface{} `json:"options,omitempty"`
 	Template string                 `json:"template,omitempty"`
 }
 
 // OllamaResponse represents an Ollama API response.
 type OllamaResponse struct {
-	Model              string `json:"model"`
-	CreatedAt          string `json:"created_at"`
-	Response           string `json:"response"`
-	Done               bool   `json:"done"`
-	DoneReason         string `json:"done_reason,omitempty"`
-	Context            []int  `json:"context,omitempty"`
-	TotalDuration      int64  `json:"total_duration,omitempty"`
-	LoadDuration       int64  `json:"load_duration,omitempty"`
-	PromptEvalCount    int    `json:"prompt_eval_count,omitempty"`
-	PromptEvalDuration int64  `json:"prompt_eval_duration,omitempty"`
-	EvalCount          int    `json:"eval_count,omitempty"`
-	EvalDuration       int64  `json:"eval_duration,omitempty"`
+	Model              string   `json:"model"`
+	CreatedAt          string   `json:"created_at"`
+	Response           string   `json:"response"`
+	Message            *Message `json:"message,omitempty"` // Reply of the chat endpoint
+	Done               bool     `json:"done"`
+	DoneReason         string   `json:"done_reason,omitempty"`
+	Context            []int    `json:"context,omitempty"`
+	TotalDuration      int64    `json:"total_duration,omitempty"`
+	LoadDuration       int64    `json:"load_duration,omitempty"`
+	PromptEvalCount    int      `json:"prompt_eval_count,omitempty"`
+	PromptEvalDuration int64    `json:"prompt_eval_duration,omitempty"`
+	EvalCount          int      `json:"eval_count,omitempty"`
+	EvalDuration       int64    `json:"eval_duration,omitempty"`
+	Error              string   `json:"error,omitempty"`
+}
+
+// OllamaVersionResponse represents the response from /api/version endpoint.
+type OllamaVersionResponse struct {
+	Version string `json:"version"`
 }
 
 // OllamaTagsResponse represents the response from /api/tags endpoint.
@@ -59,6 +87,9 @@ type OllamaModel struct {
 }
 
 // NewOllamaProvider creates a new Ollama provider instance.
+// The "num_ctx", "num_predict" and "seed" provider kwargs are passed to the
+// model as options, and "structured_outputs" (bool) overrides whether an
+// applied schema is sent as the format.
 func NewOllamaProvider(config *ModelConfig) (BaseLanguageModel, error) {
 	baseURL := "http://localhost:11434"
 	if config.ProviderKwargs != nil {
@@ -70,12 +101,25 @@ func NewOllamaProvider(config *ModelConfig) (BaseLanguageModel, error) {
 	// Remove trailing slash if present
 	baseURL = strings.TrimSuffix(baseURL, "/")
 
+	// Longer default timeout for local models
+	client, timeout, err := newHTTPClient(config, 60*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP configuration: %w", err)
+	}
+
 	provider := &OllamaProvider{
 		config:  config,
 		baseURL: baseURL,
-		client: &http.Client{
-			Timeout: 60 * time.Second, // Longer timeout for local models
-		},
+		limit:   newRequestLimit(config),
+		rate:    sharedRateLimiter("ollama", config),
+		retry:   retryPolicy(config),
+		client:  client,
+		timeout: timeout,
+	}
+
+	if structured, ok := config.ProviderKwargs["structured_outputs"].(bool); ok {
+		provider.schemaFormat = structured
+		provider.schemaFormatKnown = true // skip version detection
 	}
 
 	// Check if Ollama is available
@@ -107,32 +151,79 @@ func (p *OllamaProvider) checkAvailability() bool {
 
 // Infer generates model output for the given prompts.
 func (p *OllamaProvider) Infer(ctx context.Context, prompts []string, options map[string]any) ([][]ScoredOutput, error) {
-	results := make([][]ScoredOutput, len(prompts))
-	
-	for i, prompt := range prompts {
-		response, err := p.generateCompletion(ctx, prompt, options)
+	return inferOutputs(p.InferDetailed(ctx, prompts, options))
+}
+
+// InferDetailed generates model output for the given prompts, with token
+// usage from the evaluation counts and the done reason. Ollama has no
+// response IDs.
+func (p *OllamaProvider) InferDetailed(ctx context.Context, prompts []string, options map[string]any) ([]*InferenceResult, error) {
+	return p.infer(ctx, promptConversations(prompts), options, false)
+}
+
+// InferMessages generates model output for each conversation with the chat
+// endpoint, like InferDetailed.
+func (p *OllamaProvider) InferMessages(ctx context.Context, conversations [][]Message, options map[string]any) ([]*InferenceResult, error) {
+	return p.infer(ctx, conversations, options, true)
+}
+
+// infer generates model output for each conversation with the chat
+// endpoint, or else for the prompt of its single message with the generate
+// endpoint.
+func (p *OllamaProvider) infer(ctx context.Context, conversations [][]Message, options map[string]any, chat bool) ([]*InferenceResult, error) {
+	return inferBatch(ctx, p.limit, p.rate, conversations, func(ctx context.Context, messages []Message) (*InferenceResult, error) {
+		response, err := p.generateCompletion(ctx, messages, options, chat)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate completion for prompt %d: %w", i, err)
+			return nil, fmt.Errorf("failed to generate completion: %w", err)
 		}
-		
-		outputs := []ScoredOutput{
-			{
-				Output: response.Response,
-				Score:  1.0, // Ollama doesn't provide scores, use default
-			},
+
+		output := response.Response
+		if response.Message != nil {
+			output = response.Message.Content
 		}
-		results[i] = outputs
+		return &InferenceResult{
+			Outputs: []ScoredOutput{
+				{
+					Output: output,
+					Score:  1.0, // Ollama doesn't provide scores, use default
+				},
+			},
+			Usage: Usage{
+				PromptTokens:     response.PromptEvalCount,
+				CompletionTokens: response.EvalCount,
+				TotalTokens:      response.PromptEvalCount + response.EvalCount,
+			},
+			FinishReason: ollamaFinishReason(response.DoneReason),
+		}, nil
+	})
+}
+
+// ollamaFinishReason normalizes an Ollama done reason.
+func ollamaFinishReason(reason string) FinishReason {
+	switch reason {
+	case "stop":
+		return FinishReasonStop
+	case "length":
+		return FinishReasonLength
+	case "":
+		return ""
 	}
-	
-	return results, nil
+	return FinishReasonOther
 }
 
-// generateCompletion makes a request to the Ollama API.
-func (p *OllamaProvider) generateCompletion(ctx context.Context, prompt string, options map[string]any) (*OllamaResponse, error) {
+// newHTTPRequest creates the chat request for a conversation, or else the
+// generate request for the prompt of its single message.
+func (p *OllamaProvider) newHTTPRequest(ctx context.Context, messages []Message, options map[string]any, chat, stream bool) (*http.Request, error) {
 	request := OllamaRequest{
 		Model:  p.config.ModelID,
-		Prompt: prompt,
-		Stream: false, // Non-streaming for simplicity
+		Stream: stream,
+	}
+	endpoint := "/api/generate"
+	if chat {
+		request.Messages = messages
+		endpoint = "/api/chat"
+	} else {
+		request.Prompt = FlattenMessages(messages)
 	}
 
 	// Build options from config
@@ -146,6 +237,11 @@ func (p *OllamaProvider) generateCompletion(ctx context.Context, prompt string,
 	if p.config.TopP != 0 {
 		ollamaOptions["top_p"] = p.config.TopP
 	}
+	for _, key := range ollamaOptionKwargs {
+		if value, ok := p.config.ProviderKwargs[key]; ok {
+			ollamaOptions[key] = value
+		}
+	}
 
 	// Add any additional options
 	if options != nil {
@@ -158,9 +254,19 @@ func (p *OllamaProvider) generateCompletion(ctx context.Context, prompt string,
 		request.Options = ollamaOptions
 	}
 
-	// Add JSON output instruction if schema is applied
+	// Constrain output to the schema, or to JSON on servers that cannot use it
 	if p.schema != nil {
-		request.Prompt = prompt + "\n\nPlease respond with valid JSON only."
+		const instruction = "\n\nPlease respond with valid JSON only."
+		if chat {
+			request.Messages = append([]Message(nil), messages...)
+			request.Messages[len(messages)-1].Content += instruction
+		} else {
+			request.Prompt += instruction
+		}
+		request.Format = "json"
+		if p.supportsSchemaFormat(ctx) {
+			request.Format = p.schema
+		}
 	}
 
 	requestBody, err := json.Marshal(request)
@@ -168,32 +274,180 @@ func (p *OllamaProvider) generateCompletion(ctx context.Context, prompt string,
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/generate", bytes.NewBuffer(requestBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+endpoint, bytes.NewBuffer(requestBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
 
-	resp, err := p.client.Do(req)
+// generateCompletion makes a request to the Ollama API.
+func (p *OllamaProvider) generateCompletion(ctx context.Context, messages []Message, options map[string]any, chat bool) (*OllamaResponse, error) {
+	req, err := p.newHTTPRequest(ctx, messages, options, chat, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	resp, err := doRequest(p.client, p.retry, p.timeout, "ollama", req)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
 	var response OllamaResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	p.recordUsage(&response)
 	return &response, nil
 }
 
+// InferStream streams the output for a prompt, reading the newline-delimited
+// JSON responses of the generate endpoint.
+func (p *OllamaProvider) InferStream(ctx context.Context, prompt string, options map[string]any) (<-chan StreamChunk, error) {
+	slot, err := acquireStream(ctx, p.limit, p.rate, estimateTokens(prompt))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := p.newHTTPRequest(ctx, []Message{{Role: RoleUser, Content: prompt}}, options, false, true)
+	if err != nil {
+		slot.finish(Usage{}, err)
+		return nil, err
+	}
+
+	body, err := streamEvents(p.client, p.retry, "ollama", req)
+	if err != nil {
+		slot.finish(Usage{}, err)
+		return nil, fmt.Errorf("ollama stream failed: %w", err)
+	}
+
+	return startStream(ctx, body, slot, func(r io.Reader, send func(StreamChunk) bool) error {
+		var final *OllamaResponse
+
+		err := readNDJSON(r, func(line []byte) error {
+			var response OllamaResponse
+			if err := json.Unmarshal(line, &response); err != nil {
+				return fmt.Errorf("failed to decode stream line: %w", err)
+			}
+			if response.Error != "" {
+				return fmt.Errorf("ollama stream error: %s", response.Error)
+			}
+			if response.Response != "" && !send(StreamChunk{Delta: response.Response}) {
+				return errStreamStopped
+			}
+			if response.Done {
+				final = &response
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if final == nil {
+			return fmt.Errorf("stream ended before the response was complete")
+		}
+
+		p.recordUsage(final)
+		usage := Usage{
+			PromptTokens:     final.PromptEvalCount,
+			CompletionTokens: final.EvalCount,
+			TotalTokens:      final.PromptEvalCount + final.EvalCount,
+		}
+		slot.finish(usage, nil)
+		send(StreamChunk{
+			Done:         true,
+			FinishReason: ollamaFinishReason(final.DoneReason),
+			Usage:        usage,
+		})
+		return nil
+	}), nil
+}
+
+// recordUsage adds the evaluation counts of a response to the usage totals.
+func (p *OllamaProvider) recordUsage(response *OllamaResponse) {
+	p.usageMu.Lock()
+	p.usage.PromptTokens += response.PromptEvalCount
+	p.usage.CompletionTokens += response.EvalCount
+	p.usage.TotalTokens += response.PromptEvalCount + response.EvalCount
+	p.usageMu.Unlock()
+}
+
+// supportsSchemaFormat reports whether the server accepts a JSON schema as
+// format, which Ollama supports since version 0.5.0. The answer is cached
+// once the version is known; if it cannot be fetched, the request falls back
+// to JSON format and detection is tried again on the next one.
+func (p *OllamaProvider) supportsSchemaFormat(ctx context.Context) bool {
+	p.schemaFormatMu.Lock()
+	defer p.schemaFormatMu.Unlock()
+
+	if !p.schemaFormatKnown {
+		version, err := p.GetVersion(ctx)
+		if err != nil {
+			return false
+		}
+		p.schemaFormat = ollamaVersionAtLeast(version, 0, 5)
+		p.schemaFormatKnown = true
+	}
+	return p.schemaFormat
+}
+
+// GetVersion returns the version of the Ollama server.
+func (p *OllamaProvider) GetVersion(ctx context.Context) (string, error) {
+	ctx, cancel := requestContext(ctx, p.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/api/version", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var versionResponse OllamaVersionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&versionResponse); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	return versionResponse.Version, nil
+}
+
+// ollamaVersionAtLeast compares a "major.minor.patch" version string.
+func ollamaVersionAtLeast(version string, major, minor int) bool {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return false
+	}
+	gotMajor, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	gotMinor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false
+	}
+	return gotMajor > major || (gotMajor == major && gotMinor >= minor)
+}
+
+// Usage returns the total token usage of all requests made by the provider,
+// from the prompt and response evaluation counts.
+func (p *OllamaProvider) Usage() Usage {
+	p.usageMu.Lock()
+	defer p.usageMu.Unlock()
+	return p.usage
+}
+
 // ParseOutput processes raw model output into structured format.
 func (p *OllamaProvider) ParseOutput(output string) (any, error) {
 	// Try to parse as JSON first
@@ -253,6 +507,9 @@ func (p *OllamaProvider) IsAvailable() bool {
 
 // GetAvailableModels returns a list of locally available models.
 func (p *OllamaProvider) GetAvailableModels(ctx context.Context) ([]string, error) {
+	ctx, cancel := requestContext(ctx, p.timeout)
+	defer cancel()
+
 	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/api/tags", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -18,8 +18,23 @@ type OpenAIProvider struct {
 	apiKey      string
 	baseURL     string
 	client      *http.Client
+	timeout     time.Duration // timeout of requests whose context has no deadline
 	schema      any
 	fenceOutput bool
+	limit       requestLimit
+	rate        *rateLimiter
+	retry       *RetryPolicy
+
+	// Strict form of the applied schema, nil if structured outputs are not used
+	strictSchema map[string]any
+	schemaName   string
+
+	// Settings of OpenAI-compatible servers
+	name              string            // provider name used in errors
+	headers           map[string]string // extra request headers
+	jsonMode          bool              // server supports response_format json_object
+	structuredOutputs bool              // server supports response_format json_schema
+	keyOptional       bool              // requests may be sent without an API key
 }
 
 // OpenAIRequest represents an OpenAI API request.
@@ -29,12 +44,50 @@ type OpenAIRequest struct {
 	Temperature float64   `json:"temperature,omitempty"`
 	MaxTokens   int       `json:"max_tokens,omitempty"`
 	TopP        float64   `json:"top_p,omitempty"`
+
+	ResponseFormat *OpenAIResponseFormat `json:"response_format,omitempty"`
+
+	Stream        bool                 `json:"stream,omitempty"`
+	StreamOptions *OpenAIStreamOptions `json:"stream_options,omitempty"`
+}
+
+// OpenAIStreamOptions configures a streamed response.
+type OpenAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// OpenAIStreamChunk represents one server-sent event of a streamed response.
+type OpenAIStreamChunk struct {
+	ID      string               `json:"id"`
+	Choices []OpenAIStreamChoice `json:"choices"`
+	Usage   *Usage               `json:"usage,omitempty"`
+}
+
+// OpenAIStreamChoice represents the delta of a choice in a streamed response.
+type OpenAIStreamChoice struct {
+	Index        int     `json:"index"`
+	Delta        Message `json:"delta"`
+	FinishReason string  `json:"finish_reason,omitempty"`
+}
+
+// OpenAIResponseFormat constrains the format of the model output.
+type OpenAIResponseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *OpenAIJSONSchema `json:"json_schema,omitempty"`
+}
+
+// OpenAIJSONSchema is the schema of a json_schema response format.
+type OpenAIJSONSchema struct {
+	Name   string         `json:"name"`
+	Schema map[string]any `json:"schema"`
+	Strict bool           `json:"strict"`
 }
 
 // Message represents a chat message.
 type Message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	Refusal string `json:"refusal,omitempty"`
 }
 
 // OpenAIResponse represents an OpenAI API response.
@@ -47,8 +100,9 @@ type OpenAIResponse struct {
 
 // Choice represents a response choice.
 type Choice struct {
-	Index   int     `json:"index"`
-	Message Message `json:"message"`
+	Index        int     `json:"index"`
+	Message      Message `json:"message"`
+	FinishReason string  `json:"finish_reason,omitempty"`
 }
 
 // Usage represents token usage information.
@@ -80,54 +134,85 @@ func NewOpenAIProvider(config *ModelConfig) (BaseLanguageModel, error) {
 		}
 	}
 
+	client, timeout, err := newHTTPClient(config, 30*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP configuration: %w", err)
+	}
+
 	return &OpenAIProvider{
-		config:  config,
-		apiKey:  apiKey,
-		baseURL: baseURL,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		config:            config,
+		apiKey:            apiKey,
+		baseURL:           baseURL,
+		name:              "openai",
+		jsonMode:          true,
+		structuredOutputs: true,
+		limit:             newRequestLimit(config),
+		rate:              sharedRateLimiter("openai", config),
+		retry:             retryPolicy(config),
+		client:            client,
+		timeout:           timeout,
 	}, nil
 }
 
 // Infer generates model output for the given prompts.
 func (p *OpenAIProvider) Infer(ctx context.Context, prompts []string, options map[string]any) ([][]ScoredOutput, error) {
-	results := make([][]ScoredOutput, len(prompts))
-	
-	for i, prompt := range prompts {
-		response, err := p.generateCompletion(ctx, prompt)
+	return inferOutputs(p.InferDetailed(ctx, prompts, options))
+}
+
+// InferDetailed generates model output for the given prompts, with token
+// usage, finish reason and completion ID.
+func (p *OpenAIProvider) InferDetailed(ctx context.Context, prompts []string, options map[string]any) ([]*InferenceResult, error) {
+	return p.InferMessages(ctx, promptConversations(prompts), options)
+}
+
+// InferMessages generates model output for each conversation, sending its
+// messages as chat messages with their roles.
+func (p *OpenAIProvider) InferMessages(ctx context.Context, conversations [][]Message, options map[string]any) ([]*InferenceResult, error) {
+	return inferBatch(ctx, p.limit, p.rate, conversations, func(ctx context.Context, messages []Message) (*InferenceResult, error) {
+		response, err := p.generateCompletion(ctx, messages)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate completion for prompt %d: %w", i, err)
+			return nil, fmt.Errorf("failed to generate completion: %w", err)
 		}
-		
+
 		outputs := make([]ScoredOutput, len(response.Choices))
 		for j, choice := range response.Choices {
+			if err := p.checkChoice(choice); err != nil {
+				return nil, fmt.Errorf("invalid response: %w", err)
+			}
 			outputs[j] = ScoredOutput{
 				Output: choice.Message.Content,
 				Score:  1.0, // OpenAI doesn't provide scores, use default
 			}
 		}
-		results[i] = outputs
-	}
-	
-	return results, nil
+
+		result := &InferenceResult{
+			Outputs:    outputs,
+			Usage:      response.Usage,
+			ResponseID: response.ID,
+		}
+		if len(response.Choices) > 0 {
+			result.FinishReason = openAIFinishReason(response.Choices[0].FinishReason)
+		}
+		return result, nil
+	})
 }
 
-// generateCompletion makes a request to the OpenAI API.
-func (p *OpenAIProvider) generateCompletion(ctx context.Context, prompt string) (*OpenAIResponse, error) {
-	request := OpenAIRequest{
-		Model: p.config.ModelID,
-		Messages: []Message{
-			{
-				Role:    "user", 
-				Content: prompt,
-			},
-		},
+// buildRequest creates the chat completion request for a conversation.
+func (p *OpenAIProvider) buildRequest(messages []Message) *OpenAIRequest {
+	request := &OpenAIRequest{
+		Model:       p.config.ModelID,
+		Messages:    messages,
 		Temperature: p.config.Temperature,
 		MaxTokens:   p.config.MaxTokens,
 		TopP:        p.config.TopP,
 	}
 
+	request.ResponseFormat = p.responseFormat(messagesText(messages))
+	return request
+}
+
+// newHTTPRequest creates the HTTP request for a chat completion request.
+func (p *OpenAIProvider) newHTTPRequest(ctx context.Context, request *OpenAIRequest) (*http.Request, error) {
 	requestBody, err := json.Marshal(request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -139,18 +224,27 @@ func (p *OpenAIProvider) generateCompletion(ctx context.Context, prompt string)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	for key, value := range p.headers {
+		req.Header.Set(key, value)
+	}
+	return req, nil
+}
 
-	resp, err := p.client.Do(req)
+// generateCompletion makes a request to the OpenAI API.
+func (p *OpenAIProvider) generateCompletion(ctx context.Context, messages []Message) (*OpenAIResponse, error) {
+	req, err := p.newHTTPRequest(ctx, p.buildRequest(messages))
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	resp, err := doRequest(p.client, p.retry, p.timeout, p.name, req)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
 	var response OpenAIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
@@ -160,6 +254,152 @@ func (p *OpenAIProvider) generateCompletion(ctx context.Context, prompt string)
 	return &response, nil
 }
 
+// InferStream streams the output for a prompt using server-sent events. With
+// a schema applied, the complete output is checked like in Infer before the
+// final chunk, and a refusal or violation is reported as the stream error.
+func (p *OpenAIProvider) InferStream(ctx context.Context, prompt string, options map[string]any) (<-chan StreamChunk, error) {
+	request := p.buildRequest([]Message{{Role: RoleUser, Content: prompt}})
+	request.Stream = true
+	if p.name == "openai" {
+		// OpenAI-compatible servers do not all accept stream options
+		request.StreamOptions = &OpenAIStreamOptions{IncludeUsage: true}
+	}
+
+	slot, err := acquireStream(ctx, p.limit, p.rate, estimateTokens(prompt))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := p.newHTTPRequest(ctx, request)
+	if err != nil {
+		slot.finish(Usage{}, err)
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	body, err := streamEvents(p.client, p.retry, p.name, req)
+	if err != nil {
+		slot.finish(Usage{}, err)
+		return nil, fmt.Errorf("%s stream failed: %w", p.name, err)
+	}
+
+	return startStream(ctx, body, slot, func(r io.Reader, send func(StreamChunk) bool) error {
+		var output, refusal strings.Builder
+		final := StreamChunk{Done: true}
+		finishReason := ""
+
+		err := readSSE(r, func(data []byte) error {
+			var chunk OpenAIStreamChunk
+			if err := json.Unmarshal(data, &chunk); err != nil {
+				return fmt.Errorf("failed to decode stream event: %w", err)
+			}
+			if chunk.ID != "" {
+				final.ResponseID = chunk.ID
+			}
+			if chunk.Usage != nil {
+				final.Usage = *chunk.Usage
+			}
+			for _, choice := range chunk.Choices {
+				if choice.Index != 0 {
+					continue
+				}
+				if choice.FinishReason != "" {
+					finishReason = choice.FinishReason
+				}
+				refusal.WriteString(choice.Delta.Refusal)
+				if choice.Delta.Content == "" {
+					continue
+				}
+				output.WriteString(choice.Delta.Content)
+				if !send(StreamChunk{Delta: choice.Delta.Content}) {
+					return errStreamStopped
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if finishReason == "" {
+			return fmt.Errorf("stream ended before the response was complete")
+		}
+
+		choice := Choice{
+			Message:      Message{Content: output.String(), Refusal: refusal.String()},
+			FinishReason: finishReason,
+		}
+		if err := p.checkChoice(choice); err != nil {
+			return err
+		}
+
+		final.FinishReason = openAIFinishReason(finishReason)
+		slot.finish(final.Usage, nil)
+		send(final)
+		return nil
+	}), nil
+}
+
+// openAIFinishReason normalizes a chat completion finish reason.
+func openAIFinishReason(reason string) FinishReason {
+	switch reason {
+	case "stop", "tool_calls", "function_call":
+		return FinishReasonStop
+	case "length":
+		return FinishReasonLength
+	case "content_filter":
+		return FinishReasonSafety
+	case "":
+		return ""
+	}
+	return FinishReasonOther
+}
+
+// responseFormat selects the response format of a request: the applied schema
+// in strict mode if the server supports structured outputs, otherwise JSON
+// mode. JSON mode without a schema is only requested for prompts that ask for
+// JSON, since the API rejects it otherwise.
+func (p *OpenAIProvider) responseFormat(prompt string) *OpenAIResponseFormat {
+	if p.strictSchema != nil {
+		return &OpenAIResponseFormat{
+			Type: "json_schema",
+			JSONSchema: &OpenAIJSONSchema{
+				Name:   p.schemaName,
+				Schema: p.strictSchema,
+				Strict: true,
+			},
+		}
+	}
+	if p.jsonMode && (p.schema != nil || strings.Contains(strings.ToLower(prompt), "json")) {
+		return &OpenAIResponseFormat{Type: "json_object"}
+	}
+	return nil
+}
+
+// checkChoice reports refusals, and output that does not conform to the
+// strict schema.
+func (p *OpenAIProvider) checkChoice(choice Choice) error {
+	if choice.Message.Refusal != "" {
+		return &RefusalError{Provider: p.name, Refusal: choice.Message.Refusal}
+	}
+	if p.strictSchema == nil {
+		return nil
+	}
+
+	output := choice.Message.Content
+	if choice.FinishReason == "length" {
+		return &SchemaViolationError{Provider: p.name, Output: output, Reason: "output truncated at the token limit"}
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(output), &value); err != nil {
+		return &SchemaViolationError{Provider: p.name, Output: output, Reason: "output is not valid JSON", Err: err}
+	}
+	if err := validateJSONSchema(p.strictSchema, value); err != nil {
+		return &SchemaViolationError{Provider: p.name, Output: output, Reason: "output does not match schema", Err: err}
+	}
+	return nil
+}
+
 // ParseOutput processes raw model output into structured format.
 func (p *OpenAIProvider) ParseOutput(output string) (any, error) {
 	// Try to parse as JSON first
@@ -197,9 +437,22 @@ func (p *OpenAIProvider) cleanOutput(output string) string {
 	return output
 }
 
-// ApplySchema applies schema constraints to the model.
+// ApplySchema applies schema constraints to the model. If the server supports
+// structured outputs, the schema is sent in strict mode and responses are
+// validated against it. Schemas that cannot be converted to strict mode fall
+// back to JSON mode.
 func (p *OpenAIProvider) ApplySchema(schema any) {
 	p.schema = schema
+	p.strictSchema = nil
+	p.schemaName = ""
+
+	if schema == nil || !p.structuredOutputs {
+		return
+	}
+	if name, strict, err := strictJSONSchema(schema); err == nil {
+		p.strictSchema = strict
+		p.schemaName = name
+	}
 }
 
 // SetFenceOutput configures whether output should be fenced.
@@ -214,7 +467,7 @@ func (p *OpenAIProvider) GetModelID() string {
 
 // IsAvailable checks if the provider is ready for use.
 func (p *OpenAIProvider) IsAvailable() bool {
-	return p.apiKey != ""
+	return p.apiKey != "" || p.keyOptional
 }
 
 // init registers the OpenAI provider with the global registry.
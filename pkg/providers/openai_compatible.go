@@ -0,0 +1,207 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// OpenAICompatibleType is the provider type of servers that implement the
+// OpenAI chat completions API, such as vLLM, llama.cpp server and LM Studio.
+const OpenAICompatibleType = "openai-compatible"
+
+// OpenAICompatibleConfig configures an OpenAI-compatible server. Each server
+// is registered under its own provider name with RegisterOpenAICompatible.
+type OpenAICompatibleConfig struct {
+	// Name is the provider name the server is registered under
+	Name string
+
+	// BaseURL is the API base URL, e.g. "http://localhost:8000/v1"
+	BaseURL string
+
+	// APIKey is sent as a bearer token when set
+	APIKey string
+
+	// APIKeyEnv names an environment variable holding the API key, used when
+	// APIKey is empty
+	APIKeyEnv string
+
+	// Headers are added to every request
+	Headers map[string]string
+
+	// Models lists the model IDs served. They are registered as aliases of
+	// the provider, and other model IDs are rejected. An empty list accepts
+	// any model ID.
+	Models []string
+
+	// SupportsJSONMode enables response_format {"type": "json_object"} when a
+	// schema is applied or the prompt asks for JSON
+	SupportsJSONMode bool
+
+	// SupportsStructuredOutputs sends an applied schema as response_format
+	// {"type": "json_schema"} in strict mode
+	SupportsStructuredOutputs bool
+
+	// Timeout limits each request
+	// Default: 60 seconds
+	Timeout time.Duration
+}
+
+// Validate checks if the configuration is valid.
+func (c *OpenAICompatibleConfig) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("provider name is required")
+	}
+	if c.BaseURL == "" {
+		return fmt.Errorf("base URL is required for provider %s", c.Name)
+	}
+	if !strings.HasPrefix(c.BaseURL, "http://") && !strings.HasPrefix(c.BaseURL, "https://") {
+		return fmt.Errorf("base URL for provider %s must start with http:// or https://", c.Name)
+	}
+	return nil
+}
+
+// NewOpenAICompatibleProvider creates a provider for an OpenAI-compatible server.
+// Per-model overrides can be given in the model config's provider kwargs:
+// "api_key", "base_url", "headers" (map[string]string), "json_mode" (bool) and
+// "structured_outputs" (bool).
+func NewOpenAICompatibleProvider(server *OpenAICompatibleConfig, config *ModelConfig) (BaseLanguageModel, error) {
+	if err := server.Validate(); err != nil {
+		return nil, err
+	}
+
+	if len(server.Models) > 0 && !containsModel(server.Models, config.ModelID) {
+		return nil, fmt.Errorf("model %s is not served by provider %s (available: %s)",
+			config.ModelID, server.Name, strings.Join(server.Models, ", "))
+	}
+
+	apiKey := server.APIKey
+	if apiKey == "" && server.APIKeyEnv != "" {
+		apiKey = os.Getenv(server.APIKeyEnv)
+	}
+
+	headers := make(map[string]string, len(server.Headers))
+	for key, value := range server.Headers {
+		headers[key] = value
+	}
+
+	timeout := server.Timeout
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	client, timeout, err := newHTTPClient(config, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP configuration: %w", err)
+	}
+
+	provider := &OpenAIProvider{
+		config:            config,
+		apiKey:            apiKey,
+		baseURL:           server.BaseURL,
+		name:              server.Name,
+		headers:           headers,
+		jsonMode:          server.SupportsJSONMode,
+		structuredOutputs: server.SupportsStructuredOutputs,
+		keyOptional:       true,
+		limit:             newRequestLimit(config),
+		rate:              sharedRateLimiter(server.Name, config),
+		retry:             retryPolicy(config),
+		client:            client,
+		timeout:           timeout,
+	}
+
+	if config.ProviderKwargs != nil {
+		if key, ok := config.ProviderKwargs["api_key"].(string); ok {
+			provider.apiKey = key
+		}
+		if url, ok := config.ProviderKwargs["base_url"].(string); ok {
+			provider.baseURL = url
+		}
+		if jsonMode, ok := config.ProviderKwargs["json_mode"].(bool); ok {
+			provider.jsonMode = jsonMode
+		}
+		if structured, ok := config.ProviderKwargs["structured_outputs"].(bool); ok {
+			provider.structuredOutputs = structured
+		}
+		switch extra := config.ProviderKwargs["headers"].(type) {
+		case map[string]string:
+			for key, value := range extra {
+				provider.headers[key] = value
+			}
+		case map[string]any:
+			for key, value := range extra {
+				if s, ok := value.(string); ok {
+					provider.headers[key] = s
+				}
+			}
+		}
+	}
+	provider.baseURL = strings.TrimSuffix(provider.baseURL, "/")
+
+	return provider, nil
+}
+
+// newOpenAICompatibleFromKwargs creates a provider of the generic
+// "openai-compatible" type, configured entirely through provider kwargs.
+func newOpenAICompatibleFromKwargs(config *ModelConfig) (BaseLanguageModel, error) {
+	server := &OpenAICompatibleConfig{Name: OpenAICompatibleType}
+	if config.ProviderKwargs != nil {
+		server.BaseURL, _ = config.ProviderKwargs["base_url"].(string)
+	}
+	if server.BaseURL == "" {
+		return nil, fmt.Errorf("base_url provider kwarg is required for provider %s", OpenAICompatibleType)
+	}
+	return NewOpenAICompatibleProvider(server, config)
+}
+
+// RegisterOpenAICompatible registers an OpenAI-compatible server under its
+// name, along with its models as aliases. The same server software can be
+// registered several times under different names. Nothing is registered if
+// one of the models is already an alias of another provider.
+func (r *ProviderRegistry) RegisterOpenAICompatible(server OpenAICompatibleConfig) error {
+	if err := server.Validate(); err != nil {
+		return err
+	}
+	for _, modelID := range server.Models {
+		if providerName, exists := r.aliasedProvider(modelID); exists && providerName != server.Name {
+			return fmt.Errorf("model %s is already served by provider %s", modelID, providerName)
+		}
+	}
+
+	// Copy the slices and maps so later changes by the caller have no effect
+	server.Models = append([]string(nil), server.Models...)
+	headers := make(map[string]string, len(server.Headers))
+	for key, value := range server.Headers {
+		headers[key] = value
+	}
+	server.Headers = headers
+
+	r.Register(server.Name, func(config *ModelConfig) (BaseLanguageModel, error) {
+		return NewOpenAICompatibleProvider(&server, config)
+	})
+	for _, modelID := range server.Models {
+		r.RegisterAlias(modelID, server.Name)
+	}
+	return nil
+}
+
+// RegisterOpenAICompatible registers an OpenAI-compatible server with the
+// default registry.
+func RegisterOpenAICompatible(server OpenAICompatibleConfig) error {
+	return defaultRegistry.RegisterOpenAICompatible(server)
+}
+
+func containsModel(models []string, modelID string) bool {
+	for _, model := range models {
+		if model == modelID {
+			return true
+		}
+	}
+	return false
+}
+
+// init registers the generic OpenAI-compatible provider type with the global registry.
+func init() {
+	Register(OpenAICompatibleType, newOpenAICompatibleFromKwargs)
+}
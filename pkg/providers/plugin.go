@@ -0,0 +1,551 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PluginProtocolVersion is the version of the plugin protocol implemented by
+// PluginProvider and ServePlugin. Plugins report the version they implement
+// in their capabilities, and plugins of another version are rejected.
+//
+// The protocol is JSON-RPC 2.0 over the standard streams of the plugin
+// process, with one message per line. The host writes requests to the
+// plugin's stdin and the plugin writes responses to its stdout, in any order,
+// so that it may answer requests concurrently. Anything the plugin writes to
+// stderr is passed through to the host's stderr. The plugin exits when its
+// stdin is closed. See docs/plugin-protocol.md for the methods.
+const PluginProtocolVersion = 1
+
+// PluginType is the provider name for running a plugin executable that is
+// not installed, given by the "command" and "args" provider kwargs. It is
+// only registered with the global registry, where a model config must name
+// it; RegisterDefaultProviders leaves it out, so engines never select it
+// without a configured command.
+const PluginType = "plugin"
+
+// Methods of the plugin protocol.
+const (
+	PluginMethodCapabilities = "capabilities"
+	PluginMethodHealth       = "health"
+	PluginMethodInfer        = "infer"
+)
+
+// JSON-RPC error codes used by the plugin protocol.
+const (
+	pluginCodeParseError     = -32700
+	pluginCodeMethodNotFound = -32601
+	pluginCodeInvalidParams  = -32602
+	pluginCodeInternalError  = -32603
+)
+
+// PluginCapabilities is the result of the capabilities method.
+type PluginCapabilities struct {
+	Name            string   `json:"name"`              // Provider name the plugin is installed as
+	Version         string   `json:"version,omitempty"` // Plugin version
+	ProtocolVersion int      `json:"protocol_version"`  // Must be PluginProtocolVersion
+	Models          []string `json:"models,omitempty"`  // Model IDs routed to the plugin
+}
+
+// PluginHealth is the result of the health method.
+type PluginHealth struct {
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"` // Why the plugin is not ready
+}
+
+// PluginInferParams are the parameters of the infer method, which answers
+// one prompt. Conversations are also sent as messages, for plugins that map
+// roles natively, with the prompt holding the flattened conversation.
+type PluginInferParams struct {
+	ModelID        string         `json:"model_id"`
+	Prompt         string         `json:"prompt"`
+	Messages       []Message      `json:"messages,omitempty"` // Conversation the prompt was flattened from
+	Temperature    float64        `json:"temperature"`
+	MaxTokens      int            `json:"max_tokens,omitempty"`
+	TopP           float64        `json:"top_p,omitempty"`
+	Schema         any            `json:"schema,omitempty"`          // Schema applied with ApplySchema
+	FenceOutput    bool           `json:"fence_output,omitempty"`    // Whether output should be fenced
+	Options        map[string]any `json:"options,omitempty"`         // Options passed to Infer
+	ProviderKwargs map[string]any `json:"provider_kwargs,omitempty"` // Provider kwargs of the model config
+}
+
+// PluginInferResult is the result of the infer method.
+type PluginInferResult struct {
+	Outputs      []ScoredOutput `json:"outputs"`
+	Usage        Usage          `json:"usage"`
+	FinishReason FinishReason   `json:"finish_reason,omitempty"`
+	ResponseID   string         `json:"response_id,omitempty"`
+}
+
+// pluginErrorKinds are the error classes plugins report in the "kind" of
+// the error data, so that the host can retry transient errors.
+var pluginErrorKinds = map[string]error{
+	"rate_limited":            ErrRateLimited,
+	"server_error":            ErrServerError,
+	"timeout":                 ErrTimeout,
+	"authentication":          ErrAuthentication,
+	"bad_request":             ErrBadRequest,
+	"context_length_exceeded": ErrContextLengthExceeded,
+}
+
+// rpcRequest is a JSON-RPC request.
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse is a JSON-RPC response.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC error.
+type rpcError struct {
+	Code    int           `json:"code"`
+	Message string        `json:"message"`
+	Data    *rpcErrorData `json:"data,omitempty"`
+}
+
+// rpcErrorData classifies a plugin error.
+type rpcErrorData struct {
+	Kind string `json:"kind,omitempty"` // Key of pluginErrorKinds
+}
+
+// PluginProvider adapts a plugin executable speaking the plugin protocol to
+// BaseLanguageModel. The plugin process is started on first use and
+// restarted if it exits; Close stops it.
+type PluginProvider struct {
+	name    string
+	command string
+	args    []string
+	config  *ModelConfig
+	limit   requestLimit
+	rate    *rateLimiter
+
+	mu          sync.Mutex
+	process     *pluginProcess
+	schema      any
+	fenceOutput bool
+}
+
+// NewPluginProvider creates a provider for an installed plugin.
+func NewPluginProvider(manifest *PluginManifest, config *ModelConfig) (*PluginProvider, error) {
+	if manifest.Command == "" {
+		return nil, fmt.Errorf("plugin %s has no command", manifest.Name)
+	}
+
+	return &PluginProvider{
+		name:    manifest.Name,
+		command: manifest.Command,
+		args:    manifest.Args,
+		config:  config,
+		limit:   newRequestLimit(config),
+		rate:    sharedRateLimiter(manifest.Name, config),
+	}, nil
+}
+
+// newPluginFromKwargs creates a plugin provider for the generic plugin type
+// from the "command" and "args" provider kwargs.
+func newPluginFromKwargs(config *ModelConfig) (BaseLanguageModel, error) {
+	manifest := &PluginManifest{Name: PluginType}
+	if config.ProviderKwargs != nil {
+		if command, ok := config.ProviderKwargs["command"].(string); ok {
+			manifest.Command = command
+		}
+		switch args := config.ProviderKwargs["args"].(type) {
+		case []string:
+			manifest.Args = args
+		case []any:
+			for _, arg := range args {
+				manifest.Args = append(manifest.Args, fmt.Sprint(arg))
+			}
+		}
+		if name, ok := config.ProviderKwargs["name"].(string); ok && name != "" {
+			manifest.Name = name
+		}
+	}
+	return NewPluginProvider(manifest, config)
+}
+
+// Infer generates model output for the given prompts.
+func (p *PluginProvider) Infer(ctx context.Context, prompts []string, options map[string]any) ([][]ScoredOutput, error) {
+	return inferOutputs(p.InferDetailed(ctx, prompts, options))
+}
+
+// InferDetailed sends each prompt to the plugin in an infer request, with at
+// most MaxConcurrency requests in flight.
+func (p *PluginProvider) InferDetailed(ctx context.Context, prompts []string, options map[string]any) ([]*InferenceResult, error) {
+	return p.infer(ctx, promptConversations(prompts), options, false)
+}
+
+// InferMessages sends each conversation to the plugin in an infer request,
+// with both its messages and the flattened prompt.
+func (p *PluginProvider) InferMessages(ctx context.Context, conversations [][]Message, options map[string]any) ([]*InferenceResult, error) {
+	if err := ValidateMessages(conversations...); err != nil {
+		return nil, err
+	}
+	return p.infer(ctx, conversations, options, true)
+}
+
+// infer sends the conversations to the plugin, with their messages if chat
+// is set.
+func (p *PluginProvider) infer(ctx context.Context, conversations [][]Message, options map[string]any, chat bool) ([]*InferenceResult, error) {
+	p.mu.Lock()
+	params := PluginInferParams{
+		ModelID:        p.config.ModelID,
+		Temperature:    p.config.Temperature,
+		MaxTokens:      p.config.MaxTokens,
+		TopP:           p.config.TopP,
+		Schema:         p.schema,
+		FenceOutput:    p.fenceOutput,
+		Options:        options,
+		ProviderKwargs: p.pluginKwargs(),
+	}
+	p.mu.Unlock()
+
+	return inferBatch(ctx, p.limit, p.rate, conversations, func(ctx context.Context, messages []Message) (*InferenceResult, error) {
+		process, err := p.start(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		params := params
+		params.Prompt = FlattenMessages(messages)
+		if chat {
+			params.Messages = messages
+		}
+		var result PluginInferResult
+		if err := process.call(ctx, PluginMethodInfer, params, &result); err != nil {
+			return nil, err
+		}
+		if len(result.Outputs) == 0 {
+			return nil, fmt.Errorf("plugin %s returned no outputs", p.name)
+		}
+
+		return &InferenceResult{
+			Outputs:      result.Outputs,
+			Usage:        result.Usage,
+			FinishReason: result.FinishReason,
+			ResponseID:   result.ResponseID,
+		}, nil
+	})
+}
+
+// pluginKwargs returns the provider kwargs passed on to the plugin, without
+// those that configure the generic plugin type.
+func (p *PluginProvider) pluginKwargs() map[string]any {
+	if len(p.config.ProviderKwargs) == 0 {
+		return nil
+	}
+	kwargs := make(map[string]any, len(p.config.ProviderKwargs))
+	for key, value := range p.config.ProviderKwargs {
+		if p.config.Provider == PluginType && (key == "command" || key == "args" || key == "name") {
+			continue
+		}
+		kwargs[key] = value
+	}
+	return kwargs
+}
+
+// Capabilities returns the capabilities the plugin reported when it started.
+func (p *PluginProvider) Capabilities(ctx context.Context) (*PluginCapabilities, error) {
+	process, err := p.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return process.capabilities, nil
+}
+
+// Health asks the plugin whether it is ready to answer prompts.
+func (p *PluginProvider) Health(ctx context.Context) (*PluginHealth, error) {
+	process, err := p.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var health PluginHealth
+	params := map[string]any{"model_id": p.config.ModelID}
+	if err := process.call(ctx, PluginMethodHealth, params, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// start returns the running plugin process, starting it if it is not
+// running.
+func (p *PluginProvider) start(ctx context.Context) (*pluginProcess, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.process != nil && !p.process.exited() {
+		return p.process, nil
+	}
+
+	process, err := startPluginProcess(ctx, p.name, p.command, p.args)
+	if err != nil {
+		return nil, err
+	}
+	p.process = process
+	return process, nil
+}
+
+// Close stops the plugin process, if it is running.
+func (p *PluginProvider) Close() error {
+	p.mu.Lock()
+	process := p.process
+	p.process = nil
+	p.mu.Unlock()
+
+	if process == nil {
+		return nil
+	}
+	return process.stop()
+}
+
+// ParseOutput processes raw model output into structured format.
+func (p *PluginProvider) ParseOutput(output string) (any, error) {
+	cleanOutput := strings.TrimSpace(output)
+	cleanOutput = strings.TrimPrefix(cleanOutput, "```json")
+	cleanOutput = strings.TrimPrefix(cleanOutput, "```")
+	cleanOutput = strings.TrimSpace(strings.TrimSuffix(cleanOutput, "```"))
+
+	var result any
+	if err := json.Unmarshal([]byte(cleanOutput), &result); err != nil {
+		return cleanOutput, nil
+	}
+	return result, nil
+}
+
+// ApplySchema sets the schema sent to the plugin with each prompt.
+func (p *PluginProvider) ApplySchema(schema any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.schema = schema
+}
+
+// SetFenceOutput configures whether output should be fenced.
+func (p *PluginProvider) SetFenceOutput(enabled bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fenceOutput = enabled
+}
+
+// GetModelID returns the model identifier.
+func (p *PluginProvider) GetModelID() string {
+	return p.config.ModelID
+}
+
+// IsAvailable checks if the plugin starts and reports itself healthy.
+func (p *PluginProvider) IsAvailable() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	health, err := p.Health(ctx)
+	return err == nil && health.OK
+}
+
+// pluginProcess is a running plugin, with the requests awaiting responses.
+type pluginProcess struct {
+	name         string
+	cmd          *exec.Cmd
+	stdin        io.WriteCloser
+	capabilities *PluginCapabilities
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	nextID  int64
+	pending map[string]chan *rpcResponse
+
+	done chan struct{} // Closed when the process has exited
+	err  error         // Why the process exited, set before done is closed
+}
+
+// startPluginProcess starts a plugin and checks its capabilities.
+func startPluginProcess(ctx context.Context, name, command string, args []string) (*pluginProcess, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start plugin %s: %w", name, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start plugin %s: %w", name, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start plugin %s: %w", name, err)
+	}
+
+	process := &pluginProcess{
+		name:    name,
+		cmd:     cmd,
+		stdin:   stdin,
+		pending: make(map[string]chan *rpcResponse),
+		done:    make(chan struct{}),
+	}
+	go process.read(stdout)
+
+	var capabilities PluginCapabilities
+	if err := process.call(ctx, PluginMethodCapabilities, struct{}{}, &capabilities); err != nil {
+		process.stop()
+		return nil, fmt.Errorf("failed to get plugin %s capabilities: %w", name, err)
+	}
+	if capabilities.ProtocolVersion != PluginProtocolVersion {
+		process.stop()
+		return nil, fmt.Errorf("plugin %s uses protocol version %d, expected %d", name, capabilities.ProtocolVersion, PluginProtocolVersion)
+	}
+	process.capabilities = &capabilities
+	return process, nil
+}
+
+// read dispatches the responses of the plugin until its stdout is closed,
+// then waits for the process to exit.
+func (p *pluginProcess) read(stdout io.Reader) {
+	decoder := json.NewDecoder(stdout)
+	var readErr error
+	for {
+		var response rpcResponse
+		if err := decoder.Decode(&response); err != nil {
+			if !errors.Is(err, io.EOF) {
+				readErr = fmt.Errorf("invalid plugin output: %w", err)
+				p.cmd.Process.Kill()
+			}
+			break
+		}
+
+		p.mu.Lock()
+		if ch, ok := p.pending[string(response.ID)]; ok {
+			ch <- &response
+			delete(p.pending, string(response.ID))
+		}
+		p.mu.Unlock()
+	}
+
+	waitErr := p.cmd.Wait()
+	switch {
+	case readErr != nil:
+		p.err = fmt.Errorf("plugin %s stopped: %w", p.name, readErr)
+	case waitErr != nil:
+		p.err = fmt.Errorf("plugin %s exited: %w", p.name, waitErr)
+	default:
+		p.err = fmt.Errorf("plugin %s exited", p.name)
+	}
+	close(p.done)
+}
+
+// call sends a request to the plugin and decodes the result of its
+// response.
+func (p *pluginProcess) call(ctx context.Context, method string, params, result any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+
+	p.mu.Lock()
+	p.nextID++
+	id := json.RawMessage(fmt.Sprint(p.nextID))
+	response := make(chan *rpcResponse, 1)
+	p.pending[string(id)] = response
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.pending, string(id))
+		p.mu.Unlock()
+	}()
+
+	request, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: id, Method: method, Params: data})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+	p.writeMu.Lock()
+	_, err = p.stdin.Write(append(request, '\n'))
+	p.writeMu.Unlock()
+	if err != nil {
+		select {
+		case <-p.done:
+			return p.err
+		default:
+			return fmt.Errorf("failed to send %s request to plugin %s: %w", method, p.name, err)
+		}
+	}
+
+	select {
+	case resp := <-response:
+		if resp.Error != nil {
+			return p.error(resp.Error)
+		}
+		if result != nil {
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				return fmt.Errorf("invalid %s response from plugin %s: %w", method, p.name, err)
+			}
+		}
+		return nil
+	case <-p.done:
+		return p.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// error converts a JSON-RPC error of the plugin into a *PluginError.
+func (p *pluginProcess) error(rpcErr *rpcError) error {
+	err := &PluginError{
+		Plugin:  p.name,
+		Code:    rpcErr.Code,
+		Message: rpcErr.Message,
+	}
+	if rpcErr.Data != nil {
+		err.Kind = pluginErrorKinds[rpcErr.Data.Kind]
+	}
+	return err
+}
+
+// exited reports whether the process has exited.
+func (p *pluginProcess) exited() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop closes the plugin's stdin, asking it to exit, and kills it if it has
+// not exited after a grace period.
+func (p *pluginProcess) stop() error {
+	p.writeMu.Lock()
+	p.stdin.Close()
+	p.writeMu.Unlock()
+
+	timer := time.NewTimer(5 * time.Second)
+	defer timer.Stop()
+	select {
+	case <-p.done:
+		return nil
+	case <-timer.C:
+		if err := p.cmd.Process.Kill(); err != nil {
+			return fmt.Errorf("failed to stop plugin %s: %w", p.name, err)
+		}
+		<-p.done
+		return nil
+	}
+}
+
+// init registers the generic plugin type with the global registry. Creating
+// a model with it fails unless the config sets a command.
+func init() {
+	Register(PluginType, newPluginFromKwargs)
+}
@@ -0,0 +1,218 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PluginManifest describes an installed plugin. Manifests are stored as
+// <name>.json in the plugin directory, where ProviderRegistry.RegisterPlugins
+// discovers them.
+type PluginManifest struct {
+	Name        string    `json:"name"`              // Provider name
+	Command     string    `json:"command"`           // Absolute path of the plugin executable
+	Args        []string  `json:"args,omitempty"`    // Arguments of the plugin executable
+	Version     string    `json:"version,omitempty"` // Plugin version reported at installation
+	Models      []string  `json:"models,omitempty"`  // Model IDs routed to the plugin
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// pluginNamePattern matches valid plugin names, which are used as file
+// names in the plugin directory.
+var pluginNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// PluginDir returns the plugin directory within a configuration directory.
+func PluginDir(configDir string) string {
+	return filepath.Join(configDir, "plugins")
+}
+
+// InstallPlugin installs a plugin executable into the plugin directory. The
+// plugin is started to check that it speaks the plugin protocol, and its
+// capabilities supply the provider name, unless name is given, and the
+// models routed to it. Names of built-in providers are rejected before
+// anything is written. An installed plugin of the same name is replaced only
+// if force is set.
+func InstallPlugin(ctx context.Context, dir, name, command string, args []string, force bool) (*PluginManifest, error) {
+	if name != "" {
+		if err := checkPluginName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return nil, fmt.Errorf("plugin executable not found: %w", err)
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return nil, fmt.Errorf("failed to resolve plugin path: %w", err)
+	}
+
+	process, err := startPluginProcess(ctx, filepath.Base(path), path, args)
+	if err != nil {
+		return nil, err
+	}
+	capabilities := process.capabilities
+	process.stop()
+
+	if name == "" {
+		name = capabilities.Name
+		if err := checkPluginName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest := &PluginManifest{
+		Name:        name,
+		Command:     path,
+		Args:        args,
+		Version:     capabilities.Version,
+		Models:      capabilities.Models,
+		InstalledAt: time.Now().UTC(),
+	}
+
+	manifestPath := filepath.Join(dir, name+".json")
+	if _, err := os.Stat(manifestPath); err == nil && !force {
+		return nil, fmt.Errorf("plugin %s is already installed (use force to replace it)", name)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal plugin manifest: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create plugin directory: %w", err)
+	}
+	if err := os.WriteFile(manifestPath, append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write plugin manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// checkPluginName checks that a plugin name is valid and not the name of a
+// built-in provider, which the plugin could not be registered as.
+func checkPluginName(name string) error {
+	if !pluginNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid plugin name %q: must be lowercase letters, digits, '.', '_' or '-'", name)
+	}
+	builtin := NewProviderRegistry()
+	RegisterDefaultProviders(builtin)
+	if builtin.HasProvider(name) || name == PluginType {
+		return fmt.Errorf("plugin name %s conflicts with a built-in provider", name)
+	}
+	return nil
+}
+
+// UninstallPlugin removes an installed plugin from the plugin directory. The
+// plugin executable itself is left in place.
+func UninstallPlugin(dir, name string) error {
+	if !pluginNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid plugin name %q", name)
+	}
+	err := os.Remove(filepath.Join(dir, name+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("plugin %s is not installed", name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove plugin manifest: %w", err)
+	}
+	return nil
+}
+
+// LoadPluginManifests reads the manifests of the plugins installed in a
+// plugin directory, sorted by name. A missing directory has no plugins.
+func LoadPluginManifests(dir string) ([]*PluginManifest, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin directory: %w", err)
+	}
+
+	var manifests []*PluginManifest
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read plugin manifest: %w", err))
+			continue
+		}
+		var manifest PluginManifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			errs = append(errs, fmt.Errorf("invalid plugin manifest %s: %w", path, err))
+			continue
+		}
+		if manifest.Name == "" || manifest.Command == "" {
+			errs = append(errs, fmt.Errorf("invalid plugin manifest %s: name and command are required", path))
+			continue
+		}
+		manifests = append(manifests, &manifest)
+	}
+
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].Name < manifests[j].Name
+	})
+	return manifests, errors.Join(errs...)
+}
+
+// RegisterPlugins registers the plugins installed in a plugin directory as
+// providers, with aliases for their models. Plugins named like an already
+// registered provider are skipped, as are models aliased to another provider
+// or found in the model catalog, so that plugins cannot replace built-in
+// providers or models. Invalid manifests and skipped plugins and models are
+// reported in the error, and the rest is registered regardless.
+func (r *ProviderRegistry) RegisterPlugins(dir string) error {
+	manifests, err := LoadPluginManifests(dir)
+	errs := []error{err}
+
+	for _, manifest := range manifests {
+		if r.HasProvider(manifest.Name) {
+			errs = append(errs, fmt.Errorf("plugin %s conflicts with a registered provider", manifest.Name))
+			continue
+		}
+
+		manifest := manifest
+		r.Register(manifest.Name, func(config *ModelConfig) (BaseLanguageModel, error) {
+			return NewPluginProvider(manifest, config)
+		})
+		for _, modelID := range manifest.Models {
+			if err := r.checkPluginModel(manifest.Name, modelID); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			r.RegisterAlias(modelID, manifest.Name)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkPluginModel reports an error if a model of a plugin is already served
+// by another provider.
+func (r *ProviderRegistry) checkPluginModel(name, modelID string) error {
+	if providerName, exists := r.aliasedProvider(modelID); exists && providerName != name {
+		return fmt.Errorf("plugin %s: model %s conflicts with provider %s", name, modelID, providerName)
+	}
+	if model, exists := LookupModel(modelID); exists && model.Provider != name {
+		return fmt.Errorf("plugin %s: model %s conflicts with catalog provider %s", name, modelID, model.Provider)
+	}
+	return nil
+}
+
+// RegisterPlugins registers the plugins installed in a plugin directory with
+// the default registry.
+func RegisterPlugins(dir string) error {
+	return defaultRegistry.RegisterPlugins(dir)
+}
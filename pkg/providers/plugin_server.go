@@ -0,0 +1,164 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// ServePlugin serves a language model over the plugin protocol, reading
+// requests from in and writing responses to out, so that a Go program can
+// be installed as a provider plugin:
+//
+//	func main() {
+//		model := newMyModel()
+//		if err := providers.ServePlugin(context.Background(), "my-provider", model, os.Stdin, os.Stdout); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//
+// Requests are answered concurrently. ServePlugin returns when in is closed
+// and the requests in flight are answered, or when a request cannot be
+// parsed.
+func ServePlugin(ctx context.Context, name string, model BaseLanguageModel, in io.Reader, out io.Writer) error {
+	server := &pluginServer{
+		name:    name,
+		model:   model,
+		encoder: json.NewEncoder(out),
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	decoder := json.NewDecoder(in)
+	for {
+		var request rpcRequest
+		if err := decoder.Decode(&request); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			server.respond(nil, nil, &rpcError{Code: pluginCodeParseError, Message: err.Error()})
+			return fmt.Errorf("invalid plugin request: %w", err)
+		}
+		if len(request.ID) == 0 {
+			// Notifications expect no response, and none are defined
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, rpcErr := server.handle(ctx, &request)
+			server.respond(request.ID, result, rpcErr)
+		}()
+	}
+}
+
+// pluginServer answers plugin protocol requests with a model.
+type pluginServer struct {
+	name  string
+	model BaseLanguageModel
+
+	mu          sync.Mutex // Guards the model settings and the encoder
+	encoder     *json.Encoder
+	schema      any
+	fenceOutput bool
+}
+
+// handle answers a request.
+func (s *pluginServer) handle(ctx context.Context, request *rpcRequest) (any, *rpcError) {
+	switch request.Method {
+	case PluginMethodCapabilities:
+		return &PluginCapabilities{
+			Name:            s.name,
+			ProtocolVersion: PluginProtocolVersion,
+			Models:          []string{s.model.GetModelID()},
+		}, nil
+
+	case PluginMethodHealth:
+		health := &PluginHealth{OK: s.model.IsAvailable()}
+		if !health.OK {
+			health.Message = "model is not available"
+		}
+		return health, nil
+
+	case PluginMethodInfer:
+		var params PluginInferParams
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return nil, &rpcError{Code: pluginCodeInvalidParams, Message: err.Error()}
+		}
+		return s.infer(ctx, &params)
+
+	default:
+		return nil, &rpcError{Code: pluginCodeMethodNotFound, Message: "unknown method: " + request.Method}
+	}
+}
+
+// infer answers an infer request, applying the schema and output fencing of
+// the request to the model when they change. Requests with messages are
+// answered with InferMessages.
+func (s *pluginServer) infer(ctx context.Context, params *PluginInferParams) (any, *rpcError) {
+	s.mu.Lock()
+	if !reflect.DeepEqual(params.Schema, s.schema) {
+		s.schema = params.Schema
+		s.model.ApplySchema(params.Schema)
+	}
+	if params.FenceOutput != s.fenceOutput {
+		s.fenceOutput = params.FenceOutput
+		s.model.SetFenceOutput(params.FenceOutput)
+	}
+	s.mu.Unlock()
+
+	var results []*InferenceResult
+	var err error
+	if len(params.Messages) > 0 {
+		results, err = InferMessages(ctx, s.model, [][]Message{params.Messages}, params.Options)
+	} else {
+		results, err = InferDetailed(ctx, s.model, []string{params.Prompt}, params.Options)
+	}
+	if err != nil {
+		rpcErr := &rpcError{Code: pluginCodeInternalError, Message: err.Error()}
+		for kind, class := range pluginErrorKinds {
+			if errors.Is(err, class) {
+				rpcErr.Data = &rpcErrorData{Kind: kind}
+				break
+			}
+		}
+		return nil, rpcErr
+	}
+
+	result := results[0]
+	return &PluginInferResult{
+		Outputs:      result.Outputs,
+		Usage:        result.Usage,
+		FinishReason: result.FinishReason,
+		ResponseID:   result.ResponseID,
+	}, nil
+}
+
+// respond writes the response to a request.
+func (s *pluginServer) respond(id json.RawMessage, result any, rpcErr *rpcError) {
+	response := rpcResponse{JSONRPC: "2.0", ID: id, Error: rpcErr}
+	if id == nil {
+		response.ID = json.RawMessage("null")
+	}
+	if rpcErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			response.Error = &rpcError{Code: pluginCodeInternalError, Message: err.Error()}
+		} else {
+			response.Result = data
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.encoder.Encode(response)
+}
@@ -2,6 +2,7 @@ package providers
 
 import (
 	"context"
+	"net/http"
 )
 
 // BaseLanguageModel defines the core interface that all language model providers must implement.
@@ -43,6 +44,35 @@ type ModelConfig struct {
 	TopP            float64                `json:"top_p,omitempty"`
 	FrequencyPenalty float64               `json:"frequency_penalty,omitempty"`
 	PresencePenalty  float64               `json:"presence_penalty,omitempty"`
+
+	// MaxConcurrency limits the requests a provider has in flight at once,
+	// across all Infer calls. Zero uses the default of 4.
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+
+	// RequestsPerMinute and TokensPerMinute rate limit the calls to the
+	// model. The limits are shared by all providers created for the same
+	// provider and model. Zero is unlimited.
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+	TokensPerMinute   int `json:"tokens_per_minute,omitempty"`
+
+	// Retry controls the retries of failed requests. Nil uses
+	// DefaultRetryPolicy.
+	Retry *RetryPolicy `json:"retry,omitempty"`
+
+	// ReplayMode records the model's responses to, or replays them from,
+	// cassettes in CassetteDir. See ReplayProvider.
+	ReplayMode  ReplayMode `json:"replay_mode,omitempty"`
+	CassetteDir string     `json:"cassette_dir,omitempty"`
+
+	// HTTP configures the proxy, TLS, headers, connection pool and default
+	// request timeout of the built-in providers. Nil uses the "http"
+	// provider kwarg, if any, and otherwise the defaults.
+	HTTP *HTTPConfig `json:"http,omitempty"`
+
+	// HTTPClient, when set, sends the requests of the built-in providers
+	// instead of a client of the shared transport. Request timeouts are taken
+	// from the request context, or else from HTTP.Timeout.
+	HTTPClient *http.Client `json:"-"`
 }
 
 // NewModelConfig creates a new ModelConfig with defaults.
@@ -77,4 +107,42 @@ func (c *ModelConfig) WithTemperature(temp float64) *ModelConfig {
 func (c *ModelConfig) WithMaxTokens(maxTokens int) *ModelConfig {
 	c.MaxTokens = maxTokens
 	return c
-}
\ No newline at end of file
+}
+
+// WithMaxConcurrency sets the maximum number of concurrent requests.
+func (c *ModelConfig) WithMaxConcurrency(maxConcurrency int) *ModelConfig {
+	c.MaxConcurrency = maxConcurrency
+	return c
+}
+
+// WithRateLimit sets the requests and tokens per minute limits.
+func (c *ModelConfig) WithRateLimit(requestsPerMinute, tokensPerMinute int) *ModelConfig {
+	c.RequestsPerMinute = requestsPerMinute
+	c.TokensPerMinute = tokensPerMinute
+	return c
+}
+
+// WithRetryPolicy sets the retry policy of failed requests.
+func (c *ModelConfig) WithRetryPolicy(policy *RetryPolicy) *ModelConfig {
+	c.Retry = policy
+	return c
+}
+
+// WithHTTPConfig sets the HTTP transport configuration of the provider.
+func (c *ModelConfig) WithHTTPConfig(config *HTTPConfig) *ModelConfig {
+	c.HTTP = config
+	return c
+}
+
+// WithHTTPClient sets the HTTP client used to send the provider's requests.
+func (c *ModelConfig) WithHTTPClient(client *http.Client) *ModelConfig {
+	c.HTTPClient = client
+	return c
+}
+
+// WithReplay records responses to, or replays them from, cassettes in dir.
+func (c *ModelConfig) WithReplay(mode ReplayMode, dir string) *ModelConfig {
+	c.ReplayMode = mode
+	c.CassetteDir = dir
+	return c
+}
@@ -0,0 +1,188 @@
+package providers
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiters holds the rate limiters shared by all providers created for
+// the same provider and model.
+var (
+	rateLimitersMu sync.Mutex
+	rateLimiters   = make(map[string]*rateLimiter)
+)
+
+// sharedRateLimiter returns the rate limiter for a provider's model, creating
+// it on first use. Providers created later for the same model share it, and
+// the limits of the latest config apply. It returns nil if the config sets no
+// limits.
+func sharedRateLimiter(provider string, config *ModelConfig) *rateLimiter {
+	if config.RequestsPerMinute <= 0 && config.TokensPerMinute <= 0 {
+		return nil
+	}
+
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+
+	key := provider + "/" + config.ModelID
+	limiter, ok := rateLimiters[key]
+	if !ok {
+		limiter = &rateLimiter{}
+		rateLimiters[key] = limiter
+	}
+	limiter.setLimits(config.RequestsPerMinute, config.TokensPerMinute)
+	return limiter
+}
+
+// estimateTokens estimates the number of tokens in a prompt, using the
+// common approximation of four bytes per token.
+func estimateTokens(prompt string) int {
+	return (len(prompt) + 3) / 4
+}
+
+// rateLimiter throttles requests with token buckets for requests and tokens
+// per minute. Each bucket holds up to a minute's worth of capacity, so bursts
+// are allowed up to the limit. A nil rateLimiter does not throttle.
+type rateLimiter struct {
+	mu       sync.Mutex
+	requests tokenBucket
+	tokens   tokenBucket
+}
+
+// setLimits updates the per-minute limits. A limit of zero is unlimited.
+func (l *rateLimiter) setLimits(requestsPerMinute, tokensPerMinute int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.requests.setLimit(float64(requestsPerMinute), now)
+	l.tokens.setLimit(float64(tokensPerMinute), now)
+}
+
+// wait blocks until a request with the estimated number of tokens fits in the
+// limits, and returns the number of tokens reserved for it, to be passed to
+// reconcile once the actual usage is known. If ctx is cancelled first, the
+// reservation is released and the context error is returned.
+func (l *rateLimiter) wait(ctx context.Context, tokens int) (int, error) {
+	if l == nil {
+		return 0, nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	tokens = l.tokens.clamp(tokens)
+	delay := l.requests.take(1, now)
+	if d := l.tokens.take(float64(tokens), now); d > delay {
+		delay = d
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return tokens, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return tokens, nil
+	case <-ctx.Done():
+		l.release(tokens)
+		return 0, ctx.Err()
+	}
+}
+
+// release returns the reservation of a request that did not complete, so
+// that it does not count against the limits.
+func (l *rateLimiter) release(reserved int) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.requests.give(1)
+	l.tokens.give(float64(reserved))
+}
+
+// reconcile adjusts the token bucket once the usage of a request is known,
+// returning unused tokens or charging the excess to later requests. If the
+// provider did not report usage, the estimate stands.
+func (l *rateLimiter) reconcile(reserved int, usage Usage) {
+	if l == nil || usage.TotalTokens == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens.give(float64(reserved - usage.TotalTokens))
+}
+
+// tokenBucket is a token bucket refilled continuously at limit per minute.
+// Its level may go negative, in which case callers wait until it refills.
+type tokenBucket struct {
+	limit   float64 // Capacity and refill rate per minute, zero if unlimited
+	level   float64
+	updated time.Time
+}
+
+// setLimit changes the limit, starting with a full bucket the first time.
+func (b *tokenBucket) setLimit(limit float64, now time.Time) {
+	if b.limit <= 0 {
+		b.level = limit
+	} else {
+		b.refill(now)
+		if b.level > limit {
+			b.level = limit
+		}
+	}
+	b.limit = limit
+	b.updated = now
+}
+
+// refill adds the tokens accumulated since the last update.
+func (b *tokenBucket) refill(now time.Time) {
+	if b.limit <= 0 {
+		return
+	}
+	b.level += now.Sub(b.updated).Minutes() * b.limit
+	if b.level > b.limit {
+		b.level = b.limit
+	}
+	b.updated = now
+}
+
+// clamp bounds a reservation by the capacity, so that a request larger than
+// the limit waits for a full bucket instead of forever.
+func (b *tokenBucket) clamp(n int) int {
+	if b.limit > 0 && float64(n) > b.limit {
+		return int(b.limit)
+	}
+	return n
+}
+
+// take removes n tokens and returns how long until the bucket is no longer
+// in debt.
+func (b *tokenBucket) take(n float64, now time.Time) time.Duration {
+	if b.limit <= 0 {
+		return 0
+	}
+	b.refill(now)
+	b.level -= n
+	if b.level >= 0 {
+		return 0
+	}
+	return time.Duration(-b.level / b.limit * float64(time.Minute))
+}
+
+// give returns n tokens to the bucket, or takes them if n is negative.
+func (b *tokenBucket) give(n float64) {
+	if b.limit <= 0 {
+		return
+	}
+	b.level += n
+	if b.level > b.limit {
+		b.level = b.limit
+	}
+}
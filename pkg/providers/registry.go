@@ -38,8 +38,29 @@ func (r *ProviderRegistry) RegisterAlias(modelID, providerName string) {
 	r.aliases[modelID] = providerName
 }
 
+// Unregister removes a provider and the model aliases that map to it.
+func (r *ProviderRegistry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.providers, name)
+	for modelID, providerName := range r.aliases {
+		if providerName == name {
+			delete(r.aliases, modelID)
+		}
+	}
+}
+
+// aliasedProvider returns the provider that a model ID is aliased to.
+func (r *ProviderRegistry) aliasedProvider(modelID string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	providerName, exists := r.aliases[modelID]
+	return providerName, exists
+}
+
 // CreateModel creates a language model instance based on the configuration.
 // This mirrors the create_model function from the Python implementation.
+// If the config sets a replay mode, the model is wrapped in a ReplayProvider.
 func (r *ProviderRegistry) CreateModel(config *ModelConfig) (BaseLanguageModel, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -47,9 +68,11 @@ func (r *ProviderRegistry) CreateModel(config *ModelConfig) (BaseLanguageModel,
 	// Determine provider name
 	providerName := config.Provider
 	if providerName == "" {
-		// Try to find alias for model ID
+		// Try to find alias for model ID, then the model catalog
 		if alias, exists := r.aliases[config.ModelID]; exists {
 			providerName = alias
+		} else if model, exists := LookupModel(config.ModelID); exists {
+			providerName = model.Provider
 		} else {
 			return nil, fmt.Errorf("no provider specified and no alias found for model ID: %s", config.ModelID)
 		}
@@ -62,7 +85,19 @@ func (r *ProviderRegistry) CreateModel(config *ModelConfig) (BaseLanguageModel,
 	}
 
 	// Create model instance
-	return factory(config)
+	model, err := factory(config)
+	if config.ReplayMode == "" {
+		return model, err
+	}
+	if err != nil {
+		if config.ReplayMode != ReplayModeReplay {
+			return nil, err
+		}
+		// Replaying needs no credentials, so a model that cannot be
+		// created is not an error
+		model = nil
+	}
+	return NewReplayProvider(model, config.ModelID, config.ReplayMode, config.CassetteDir)
 }
 
 // GetAvailableProviders returns a list of registered provider names.
@@ -98,6 +133,11 @@ func RegisterAlias(modelID, providerName string) {
 	defaultRegistry.RegisterAlias(modelID, providerName)
 }
 
+// Unregister removes a provider and its aliases from the default registry.
+func Unregister(name string) {
+	defaultRegistry.Unregister(name)
+}
+
 // CreateModel creates a model using the default registry.
 func CreateModel(config *ModelConfig) (BaseLanguageModel, error) {
 	return defaultRegistry.CreateModel(config)
@@ -125,6 +165,15 @@ func RegisterDefaultProviders(registry *ProviderRegistry) {
 		return NewOllamaProvider(config)
 	})
 	
+	// Register Anthropic provider
+	registry.Register("anthropic", func(config *ModelConfig) (BaseLanguageModel, error) {
+		return NewAnthropicProvider(config)
+	})
+	
+	// Register the generic OpenAI-compatible provider type
+	registry.Register(OpenAICompatibleType, newOpenAICompatibleFromKwargs)
+
+	
 	// Register common model aliases
 	registry.RegisterAlias("gpt-4", "openai")
 	registry.RegisterAlias("gpt-3.5-turbo", "openai")
@@ -132,4 +181,7 @@ func RegisterDefaultProviders(registry *ProviderRegistry) {
 	registry.RegisterAlias("gemini-1.5-pro", "gemini")
 	registry.RegisterAlias("llama3.2", "ollama")
 	registry.RegisterAlias("mistral", "ollama")
+	for _, modelID := range anthropicModelAliases {
+		registry.RegisterAlias(modelID, "anthropic")
+	}
 }
\ No newline at end of file
@@ -0,0 +1,278 @@
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ReplayMode selects whether a ReplayProvider records or replays responses.
+type ReplayMode string
+
+const (
+	// ReplayModeRecord calls the wrapped model and writes each response to a
+	// cassette.
+	ReplayModeRecord ReplayMode = "record"
+	// ReplayModeReplay serves responses from cassettes without calling the
+	// wrapped model, failing on prompts that were not recorded.
+	ReplayModeReplay ReplayMode = "replay"
+)
+
+// Cassette is a recorded model response, stored as JSON in a file named
+// after the cassette key.
+type Cassette struct {
+	ModelID      string         `json:"model_id"`
+	Prompt       string         `json:"prompt"`
+	Outputs      []ScoredOutput `json:"outputs"`
+	Usage        Usage          `json:"usage"`
+	FinishReason FinishReason   `json:"finish_reason,omitempty"`
+	ResponseID   string         `json:"response_id,omitempty"`
+	RecordedAt   time.Time      `json:"recorded_at"`
+}
+
+// ReplayProvider wraps a language model to record its responses to a
+// directory of cassettes, or to replay them without network access, for
+// deterministic tests. Cassettes are keyed by the model ID and a hash of the
+// normalized prompt.
+type ReplayProvider struct {
+	model   BaseLanguageModel
+	modelID string
+	mode    ReplayMode
+	dir     string
+}
+
+// NewReplayProvider creates a replay provider for a model, storing cassettes
+// in dir. In replay mode, model may be nil if it cannot be created without
+// credentials; outputs are then parsed as JSON when possible.
+func NewReplayProvider(model BaseLanguageModel, modelID string, mode ReplayMode, dir string) (*ReplayProvider, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("cassette directory is required")
+	}
+
+	switch mode {
+	case ReplayModeRecord:
+		if model == nil {
+			return nil, fmt.Errorf("record mode requires a model")
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create cassette directory: %w", err)
+		}
+	case ReplayModeReplay:
+	default:
+		return nil, fmt.Errorf("unknown replay mode: %q", mode)
+	}
+
+	if modelID == "" && model != nil {
+		modelID = model.GetModelID()
+	}
+
+	return &ReplayProvider{
+		model:   model,
+		modelID: modelID,
+		mode:    mode,
+		dir:     dir,
+	}, nil
+}
+
+// CassetteKey returns the key of the cassette for a model and prompt. Line
+// endings and trailing whitespace of the prompt are normalized, so that
+// insignificant differences do not cause cache misses.
+func CassetteKey(modelID, prompt string) string {
+	lines := strings.Split(strings.ReplaceAll(prompt, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	normalized := strings.TrimSpace(strings.Join(lines, "\n"))
+
+	hash := sha256.Sum256([]byte(modelID + "\x00" + normalized))
+	return hex.EncodeToString(hash[:])
+}
+
+// Infer generates model output for the given prompts.
+func (p *ReplayProvider) Infer(ctx context.Context, prompts []string, options map[string]any) ([][]ScoredOutput, error) {
+	return inferOutputs(p.InferDetailed(ctx, prompts, options))
+}
+
+// InferDetailed records or replays the responses to the given prompts. In
+// replay mode, prompts without a cassette fail with ErrCassetteNotFound.
+func (p *ReplayProvider) InferDetailed(ctx context.Context, prompts []string, options map[string]any) ([]*InferenceResult, error) {
+	if p.mode == ReplayModeRecord {
+		results, err := InferDetailed(ctx, p.model, prompts, options)
+		return p.record(prompts, results, err)
+	}
+	return p.replayAll(prompts)
+}
+
+// InferMessages records or replays the responses to conversations, which
+// are passed on to the wrapped model as messages. Cassettes are keyed by the
+// conversation rendered by FlattenMessages, so a conversation of a single
+// user message shares the cassette of its prompt.
+func (p *ReplayProvider) InferMessages(ctx context.Context, conversations [][]Message, options map[string]any) ([]*InferenceResult, error) {
+	prompts := make([]string, len(conversations))
+	for i, messages := range conversations {
+		prompts[i] = FlattenMessages(messages)
+	}
+
+	if p.mode == ReplayModeRecord {
+		results, err := InferMessages(ctx, p.model, conversations, options)
+		return p.record(prompts, results, err)
+	}
+	return p.replayAll(prompts)
+}
+
+// record writes a cassette for each successful result of the wrapped model.
+func (p *ReplayProvider) record(prompts []string, results []*InferenceResult, err error) ([]*InferenceResult, error) {
+	for i, result := range results {
+		if result == nil {
+			continue
+		}
+		cassette := &Cassette{
+			ModelID:      p.modelID,
+			Prompt:       prompts[i],
+			Outputs:      result.Outputs,
+			Usage:        result.Usage,
+			FinishReason: result.FinishReason,
+			ResponseID:   result.ResponseID,
+			RecordedAt:   time.Now().UTC(),
+		}
+		if writeErr := p.writeCassette(cassette); writeErr != nil {
+			return nil, writeErr
+		}
+	}
+	return results, err
+}
+
+// replayAll reads the cassettes of the given prompts.
+func (p *ReplayProvider) replayAll(prompts []string) ([]*InferenceResult, error) {
+	results := make([]*InferenceResult, len(prompts))
+	var batchErr *BatchError
+	for i, prompt := range prompts {
+		result, err := p.replay(prompt)
+		if err != nil {
+			if batchErr == nil {
+				batchErr = &BatchError{Total: len(prompts)}
+			}
+			batchErr.Errors = append(batchErr.Errors, &PromptError{Index: i, Err: err})
+			continue
+		}
+		results[i] = result
+	}
+	if batchErr != nil {
+		return results, batchErr
+	}
+	return results, nil
+}
+
+// replay reads the cassette of a prompt.
+func (p *ReplayProvider) replay(prompt string) (*InferenceResult, error) {
+	path := p.cassettePath(prompt)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for model %s: %s (record it with replay mode %q)", ErrCassetteNotFound, p.modelID, path, ReplayModeRecord)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	return &InferenceResult{
+		Outputs:      cassette.Outputs,
+		Usage:        cassette.Usage,
+		FinishReason: cassette.FinishReason,
+		ResponseID:   cassette.ResponseID,
+	}, nil
+}
+
+// writeCassette writes a cassette atomically, so that concurrent runs never
+// read a partial file.
+func (p *ReplayProvider) writeCassette(cassette *Cassette) error {
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+
+	path := p.cassettePath(cassette.Prompt)
+	tmp, err := os.CreateTemp(p.dir, ".cassette-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// cassettePath returns the path of the cassette for a prompt.
+func (p *ReplayProvider) cassettePath(prompt string) string {
+	return filepath.Join(p.dir, CassetteKey(p.modelID, prompt)+".json")
+}
+
+// ParseOutput processes raw model output into structured format.
+func (p *ReplayProvider) ParseOutput(output string) (any, error) {
+	if p.model != nil {
+		return p.model.ParseOutput(output)
+	}
+
+	cleanOutput := p.cleanOutput(output)
+	var result any
+	if err := json.Unmarshal([]byte(cleanOutput), &result); err != nil {
+		return cleanOutput, nil
+	}
+	return result, nil
+}
+
+// cleanOutput removes markdown code fences and extra whitespace.
+func (p *ReplayProvider) cleanOutput(output string) string {
+	output = strings.TrimSpace(output)
+	output = strings.TrimPrefix(output, "```json")
+	output = strings.TrimPrefix(output, "```")
+	output = strings.TrimSuffix(output, "```")
+	return strings.TrimSpace(output)
+}
+
+// ApplySchema applies schema constraints to the wrapped model.
+func (p *ReplayProvider) ApplySchema(schema any) {
+	if p.model != nil {
+		p.model.ApplySchema(schema)
+	}
+}
+
+// SetFenceOutput configures output fencing of the wrapped model.
+func (p *ReplayProvider) SetFenceOutput(enabled bool) {
+	if p.model != nil {
+		p.model.SetFenceOutput(enabled)
+	}
+}
+
+// GetModelID returns the model identifier.
+func (p *ReplayProvider) GetModelID() string {
+	return p.modelID
+}
+
+// IsAvailable checks if the provider is ready for use. Replaying needs only
+// the cassettes, so it is always available.
+func (p *ReplayProvider) IsAvailable() bool {
+	if p.mode == ReplayModeReplay {
+		return true
+	}
+	return p.model.IsAvailable()
+}
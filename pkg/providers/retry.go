@@ -0,0 +1,238 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how provider HTTP requests are retried. Only
+// retryable errors (see IsRetryable) are retried, with exponential backoff
+// and jitter between attempts, or the delay requested by a Retry-After
+// header.
+type RetryPolicy struct {
+	MaxRetries     int           `json:"max_retries"`     // Retries after the first attempt, zero to disable
+	InitialBackoff time.Duration `json:"initial_backoff"` // Backoff before the first retry
+	MaxBackoff     time.Duration `json:"max_backoff"`     // Upper bound of the backoff
+}
+
+// DefaultRetryPolicy returns the retry policy used when a ModelConfig does
+// not set one.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// Backoff returns the delay before the given retry, counting from zero. The
+// delay doubles with each retry up to MaxBackoff, and a random jitter of up
+// to half of it spreads out clients retrying at the same time.
+func (p *RetryPolicy) Backoff(retry int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 0; i < retry && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
+
+// retryPolicy returns the retry policy of a model config.
+func retryPolicy(config *ModelConfig) *RetryPolicy {
+	if config.Retry != nil {
+		return config.Retry
+	}
+	return DefaultRetryPolicy()
+}
+
+// doRequest sends a request, retrying retryable failures according to the
+// policy, and returns the first 200 response. Error responses are returned
+// as *APIError. The request body is replayed from req.GetBody, which is set
+// for requests created with a bytes body. Each attempt, including the read
+// of its response body, is bounded by the timeout unless the request context
+// has a deadline.
+func doRequest(client *http.Client, policy *RetryPolicy, timeout time.Duration, provider string, req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for retry := 0; ; retry++ {
+		attemptCtx, cancel := requestContext(ctx, timeout)
+		attempt := req.WithContext(attemptCtx)
+		if retry > 0 {
+			attempt = req.Clone(attemptCtx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					cancel()
+					return nil, fmt.Errorf("failed to replay request body: %w", err)
+				}
+				attempt.Body = body
+			}
+		}
+
+		var retryAfter time.Duration
+		resp, err := client.Do(attempt)
+		if err == nil {
+			if resp.StatusCode == http.StatusOK {
+				resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+				return resp, nil
+			}
+			apiErr := newAPIError(provider, resp)
+			retryAfter = apiErr.RetryAfter
+			err = apiErr
+		} else {
+			err = requestError(attemptCtx, err)
+		}
+		cancel()
+
+		if retry >= policy.MaxRetries || !IsRetryable(err) {
+			return nil, err
+		}
+
+		delay := retryAfter
+		if delay <= 0 {
+			delay = policy.Backoff(retry)
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			// The retry could not complete in time
+			return nil, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// requestError wraps an error returned by http.Client.Do, classifying
+// timeouts that are not caused by the caller's context: those of the
+// transport and those of requestContext.
+func requestError(ctx context.Context, err error) error {
+	var netErr net.Error
+	transportTimeout := ctx.Err() == nil && errors.As(err, &netErr) && netErr.Timeout()
+	if transportTimeout || errors.Is(context.Cause(ctx), errRequestTimeout) {
+		return fmt.Errorf("failed to make request: %w: %w", ErrTimeout, err)
+	}
+	return fmt.Errorf("failed to make request: %w", err)
+}
+
+// newAPIError reads an error response into an *APIError, classifying it by
+// status code and message.
+func newAPIError(provider string, resp *http.Response) *APIError {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	apiErr := &APIError{
+		Provider:   provider,
+		StatusCode: resp.StatusCode,
+		Message:    apiErrorMessage(body),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+
+	switch status := resp.StatusCode; {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		apiErr.Kind = ErrAuthentication
+	case status == http.StatusRequestTimeout:
+		apiErr.Kind = ErrTimeout
+	case status == http.StatusTooManyRequests:
+		apiErr.Kind = ErrRateLimited
+	case status >= 500:
+		apiErr.Kind = ErrServerError
+	case status >= 400 && isContextLengthError(string(body)):
+		apiErr.Kind = ErrContextLengthExceeded
+	case status >= 400:
+		apiErr.Kind = ErrBadRequest
+	}
+	return apiErr
+}
+
+// apiErrorMessage extracts the error message from an error response body.
+// Providers wrap it in an "error" object with a message and type or status,
+// or report it as an "error" string; other bodies are returned as they are.
+func apiErrorMessage(body []byte) string {
+	var object struct {
+		Error struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &object) == nil && object.Error.Message != "" {
+		kind := object.Error.Type
+		if kind == "" {
+			kind = object.Error.Status
+		}
+		if kind != "" {
+			return kind + ": " + object.Error.Message
+		}
+		return object.Error.Message
+	}
+
+	var text struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &text) == nil && text.Error != "" {
+		return text.Error
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// contextLengthMessages are fragments of the errors providers return when a
+// prompt does not fit in the model's context window.
+var contextLengthMessages = []string{
+	"context_length_exceeded",
+	"maximum context length",
+	"context window",
+	"prompt is too long",
+	"input is too long",
+	"exceeds the maximum number of tokens",
+}
+
+// isContextLengthError reports whether an error body reports a prompt that
+// exceeds the context window.
+func isContextLengthError(body string) bool {
+	body = strings.ToLower(body)
+	for _, message := range contextLengthMessages {
+		if strings.Contains(body, message) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRetryAfter parses a Retry-After header, given in seconds or as an
+// HTTP date. It returns zero if the header is absent or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
@@ -0,0 +1,198 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// maxStreamLineSize bounds a single SSE or NDJSON line.
+const maxStreamLineSize = 1024 * 1024
+
+// StreamChunk is an incremental piece of a streamed model response.
+type StreamChunk struct {
+	Delta string // Output text since the previous chunk
+
+	// Done is set on the final chunk of a successful stream, which carries
+	// the response metadata
+	Done         bool
+	FinishReason FinishReason
+	Usage        Usage
+	ResponseID   string
+
+	// Err is set on the final chunk of a failed stream
+	Err error
+}
+
+// StreamingLanguageModel is implemented by providers that can stream output
+// as it is generated. The OpenAI, OpenAI-compatible, Gemini and Ollama
+// providers implement it.
+type StreamingLanguageModel interface {
+	BaseLanguageModel
+
+	// InferStream starts generating output for a prompt. Chunks are delivered
+	// on the returned channel, which is closed after a chunk with Done or Err
+	// set. Cancelling ctx aborts the request and closes the channel; the
+	// cancellation cause is then reported by ctx.Err(). A stream counts
+	// against MaxConcurrency and the rate limits until the channel is closed.
+	InferStream(ctx context.Context, prompt string, options map[string]any) (<-chan StreamChunk, error)
+}
+
+// streamEvents sends a streaming request, retrying like doRequest, and
+// returns the response body. Neither the client's nor the provider's timeout
+// is applied, since a stream may legitimately outlast them; the request
+// context bounds the stream.
+func streamEvents(client *http.Client, policy *RetryPolicy, provider string, req *http.Request) (io.ReadCloser, error) {
+	streamClient := *client
+	streamClient.Timeout = 0
+
+	resp, err := doRequest(&streamClient, policy, 0, provider, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// streamSlot holds the request slot and rate limit reservation of a stream
+// for its lifetime, so that streams count against MaxConcurrency and the rate
+// limits like other requests.
+type streamSlot struct {
+	limit    requestLimit
+	rate     *rateLimiter
+	reserved int
+	once     sync.Once
+}
+
+// acquireStream takes a request slot and then waits for the rate limits. The
+// slot must be freed with finish.
+func acquireStream(ctx context.Context, limit requestLimit, rate *rateLimiter, tokens int) (*streamSlot, error) {
+	select {
+	case limit <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	reserved, err := rate.wait(ctx, tokens)
+	if err != nil {
+		<-limit
+		return nil, err
+	}
+	return &streamSlot{limit: limit, rate: rate, reserved: reserved}, nil
+}
+
+// finish frees the request slot. The reservation is reconciled with the usage
+// of a completed stream and released if the stream failed or was cancelled.
+// Only the first call has an effect.
+func (s *streamSlot) finish(usage Usage, err error) {
+	s.once.Do(func() {
+		if err != nil {
+			s.rate.release(s.reserved)
+		} else {
+			s.rate.reconcile(s.reserved, usage)
+		}
+		<-s.limit
+	})
+}
+
+// startStream reads a response body in a goroutine, delivering the chunks
+// produced by read on the returned channel. The body is closed when ctx is
+// cancelled, which unblocks any pending read. The slot is freed when reading
+// ends, unless read already finished it with the usage of the stream.
+func startStream(ctx context.Context, body io.ReadCloser, slot *streamSlot, read func(body io.Reader, send func(StreamChunk) bool) error) <-chan StreamChunk {
+	chunks := make(chan StreamChunk)
+
+	go func() {
+		defer close(chunks)
+		defer body.Close()
+		stop := context.AfterFunc(ctx, func() { body.Close() })
+		defer stop()
+
+		send := func(chunk StreamChunk) bool {
+			select {
+			case chunks <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		err := read(body, send)
+		slot.finish(Usage{}, err)
+		if err != nil && ctx.Err() == nil {
+			send(StreamChunk{Err: err})
+		}
+	}()
+
+	return chunks
+}
+
+// readSSE reads a server-sent events stream, calling handle with the data of
+// each event. Reading stops at the end of the stream or at a "[DONE]" event.
+func readSSE(r io.Reader, handle func(data []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineSize)
+
+	var data bytes.Buffer
+	dispatch := func() (bool, error) {
+		if data.Len() == 0 {
+			return false, nil
+		}
+		payload := bytes.TrimSuffix(data.Bytes(), []byte("\n"))
+		defer data.Reset()
+		if string(payload) == "[DONE]" {
+			return true, nil
+		}
+		return false, handle(payload)
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		switch {
+		case line == "":
+			if done, err := dispatch(); done || err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, ":"):
+			// Comment, used as keep-alive
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			data.WriteByte('\n')
+		}
+		// Other fields (event, id, retry) are not used by the providers
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	_, err := dispatch()
+	return err
+}
+
+// readNDJSON reads a newline-delimited JSON stream, calling handle with each
+// non-empty line.
+func readNDJSON(r io.Reader, handle func(line []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineSize)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := handle(line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return nil
+}
+
+// errStreamStopped signals that the consumer went away and reading should stop.
+var errStreamStopped = errors.New("stream stopped")
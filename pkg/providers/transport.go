@@ -0,0 +1,364 @@
+package providers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// HTTPConfig configures the HTTP transport of the built-in providers, for
+// example to reach them through a corporate proxy. It is set with
+// ModelConfig.HTTP or the "http" provider kwarg. Providers created with the
+// same proxy, TLS and connection pool settings share a transport, and with
+// it a connection pool.
+type HTTPConfig struct {
+	// Timeout limits requests whose context has no deadline. Zero uses the
+	// provider's default timeout.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// ProxyURL is the proxy for all requests. Empty uses the HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY environment variables.
+	ProxyURL string `json:"proxy_url,omitempty"`
+
+	// CAFile is a PEM bundle of certificate authorities trusted in addition
+	// to the system roots
+	CAFile string `json:"ca_file,omitempty"`
+
+	// CertFile and KeyFile are the PEM client certificate and key presented
+	// for mutual TLS
+	CertFile string `json:"cert_file,omitempty"`
+	KeyFile  string `json:"key_file,omitempty"`
+
+	// InsecureSkipVerify disables the verification of server certificates.
+	// Use it only for testing.
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+
+	// Headers are added to every request. They do not replace the headers
+	// set by the provider, such as its API key.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Connection pool settings. Zero uses the net/http defaults.
+	MaxIdleConns        int           `json:"max_idle_conns,omitempty"`
+	MaxIdleConnsPerHost int           `json:"max_idle_conns_per_host,omitempty"`
+	MaxConnsPerHost     int           `json:"max_conns_per_host,omitempty"`
+	IdleConnTimeout     time.Duration `json:"idle_conn_timeout,omitempty"`
+}
+
+// transportKey holds the HTTPConfig settings that configure a transport.
+// Transports are shared between configs of equal keys.
+type transportKey struct {
+	proxyURL            string
+	caFile              string
+	certFile            string
+	keyFile             string
+	insecureSkipVerify  bool
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	maxConnsPerHost     int
+	idleConnTimeout     time.Duration
+}
+
+var (
+	transportsMu sync.Mutex
+	transports   = make(map[transportKey]*http.Transport)
+)
+
+// newHTTPClient returns the HTTP client of a provider and the timeout of
+// requests whose context has no deadline. The client is, in order of
+// precedence, ModelConfig.HTTPClient, the "http_client" provider kwarg or a
+// client of the shared transport for the config's HTTPConfig. Headers of the
+// HTTPConfig are added to the requests of any of them.
+func newHTTPClient(config *ModelConfig, defaultTimeout time.Duration) (*http.Client, time.Duration, error) {
+	httpConfig, err := modelHTTPConfig(config)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	timeout := defaultTimeout
+	if httpConfig.Timeout > 0 {
+		timeout = httpConfig.Timeout
+	}
+
+	client := config.HTTPClient
+	if client == nil && config.ProviderKwargs != nil {
+		client, _ = config.ProviderKwargs["http_client"].(*http.Client)
+	}
+	if client == nil {
+		transport, err := sharedTransport(httpConfig)
+		if err != nil {
+			return nil, 0, err
+		}
+		client = &http.Client{Transport: transport}
+	}
+
+	if len(httpConfig.Headers) > 0 {
+		withHeaders := *client
+		withHeaders.Transport = &headerTransport{base: client.Transport, headers: httpConfig.Headers}
+		client = &withHeaders
+	}
+	return client, timeout, nil
+}
+
+// modelHTTPConfig returns the HTTP settings of a model config, from
+// ModelConfig.HTTP or else the "http" provider kwarg, which may be an
+// HTTPConfig or a map of its JSON fields.
+func modelHTTPConfig(config *ModelConfig) (*HTTPConfig, error) {
+	if config.HTTP != nil {
+		return config.HTTP, nil
+	}
+	if config.ProviderKwargs == nil {
+		return &HTTPConfig{}, nil
+	}
+
+	switch value := config.ProviderKwargs["http"].(type) {
+	case nil:
+		return &HTTPConfig{}, nil
+	case *HTTPConfig:
+		return value, nil
+	case HTTPConfig:
+		return &value, nil
+	case map[string]any:
+		return parseHTTPConfig(value)
+	default:
+		return nil, fmt.Errorf("invalid http provider kwarg of type %T", value)
+	}
+}
+
+// parseHTTPConfig parses an HTTPConfig from a map of its JSON fields, as
+// found in configuration files. Durations are strings such as "30s" or
+// numbers of seconds.
+func parseHTTPConfig(values map[string]any) (*HTTPConfig, error) {
+	config := &HTTPConfig{}
+	for key, value := range values {
+		var err error
+		switch key {
+		case "timeout":
+			config.Timeout, err = kwargDuration(value)
+		case "idle_conn_timeout":
+			config.IdleConnTimeout, err = kwargDuration(value)
+		case "proxy_url":
+			config.ProxyURL, err = kwargString(value)
+		case "ca_file":
+			config.CAFile, err = kwargString(value)
+		case "cert_file":
+			config.CertFile, err = kwargString(value)
+		case "key_file":
+			config.KeyFile, err = kwargString(value)
+		case "insecure_skip_verify":
+			var ok bool
+			if config.InsecureSkipVerify, ok = value.(bool); !ok {
+				err = fmt.Errorf("expected a bool, got %T", value)
+			}
+		case "max_idle_conns":
+			config.MaxIdleConns, err = kwargInt(value)
+		case "max_idle_conns_per_host":
+			config.MaxIdleConnsPerHost, err = kwargInt(value)
+		case "max_conns_per_host":
+			config.MaxConnsPerHost, err = kwargInt(value)
+		case "headers":
+			config.Headers = make(map[string]string)
+			switch headers := value.(type) {
+			case map[string]string:
+				for name, v := range headers {
+					config.Headers[name] = v
+				}
+			case map[string]any:
+				for name, v := range headers {
+					if config.Headers[name], err = kwargString(v); err != nil {
+						break
+					}
+				}
+			default:
+				err = fmt.Errorf("expected a map of strings, got %T", value)
+			}
+		default:
+			err = fmt.Errorf("unknown setting")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid http setting %s: %w", key, err)
+		}
+	}
+	return config, nil
+}
+
+// kwargString converts a provider kwarg value to a string.
+func kwargString(value any) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("expected a string, got %T", value)
+	}
+	return s, nil
+}
+
+// kwargInt converts a provider kwarg value to an int, accepting the float64
+// numbers of decoded JSON.
+func kwargInt(value any) (int, error) {
+	switch n := value.(type) {
+	case int:
+		return n, nil
+	case float64:
+		return int(n), nil
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", value)
+	}
+}
+
+// kwargDuration converts a provider kwarg value to a duration. Strings are
+// parsed with time.ParseDuration and numbers are seconds.
+func kwargDuration(value any) (time.Duration, error) {
+	switch d := value.(type) {
+	case time.Duration:
+		return d, nil
+	case string:
+		return time.ParseDuration(d)
+	case int:
+		return time.Duration(d) * time.Second, nil
+	case float64:
+		return time.Duration(d * float64(time.Second)), nil
+	default:
+		return 0, fmt.Errorf("expected a duration, got %T", value)
+	}
+}
+
+// sharedTransport returns the transport for the settings of an HTTPConfig,
+// creating it on first use.
+func sharedTransport(config *HTTPConfig) (*http.Transport, error) {
+	key := transportKey{
+		proxyURL:            config.ProxyURL,
+		caFile:              config.CAFile,
+		certFile:            config.CertFile,
+		keyFile:             config.KeyFile,
+		insecureSkipVerify:  config.InsecureSkipVerify,
+		maxIdleConns:        config.MaxIdleConns,
+		maxIdleConnsPerHost: config.MaxIdleConnsPerHost,
+		maxConnsPerHost:     config.MaxConnsPerHost,
+		idleConnTimeout:     config.IdleConnTimeout,
+	}
+
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	if transport, ok := transports[key]; ok {
+		return transport, nil
+	}
+
+	transport, err := newTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	transports[key] = transport
+	return transport, nil
+}
+
+// newTransport creates a transport from the settings of an HTTPConfig,
+// starting from the defaults of http.DefaultTransport.
+func newTransport(config *HTTPConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.ProxyURL != "" {
+		proxy, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if config.CAFile != "" || config.CertFile != "" || config.KeyFile != "" || config.InsecureSkipVerify {
+		tlsConfig := &tls.Config{InsecureSkipVerify: config.InsecureSkipVerify}
+
+		if config.CAFile != "" {
+			pem, err := os.ReadFile(config.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+			}
+			roots, err := x509.SystemCertPool()
+			if err != nil {
+				roots = x509.NewCertPool()
+			}
+			if !roots.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in CA bundle %s", config.CAFile)
+			}
+			tlsConfig.RootCAs = roots
+		}
+
+		if config.CertFile != "" || config.KeyFile != "" {
+			cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if config.MaxIdleConns > 0 {
+		transport.MaxIdleConns = config.MaxIdleConns
+	}
+	if config.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	}
+	if config.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = config.MaxConnsPerHost
+	}
+	if config.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = config.IdleConnTimeout
+	}
+	return transport, nil
+}
+
+// headerTransport adds headers to the requests it sends, leaving headers
+// already set on a request in place.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		if req.Header.Get(name) == "" {
+			req.Header.Set(name, value)
+		}
+	}
+	return base.RoundTrip(req)
+}
+
+// errRequestTimeout is the cause of requests cancelled by the timeout of
+// requestContext. It is retryable, unlike the expiry of a caller's deadline.
+var errRequestTimeout = fmt.Errorf("provider request timeout: %w", ErrTimeout)
+
+// requestContext bounds a request by the timeout, unless ctx already has a
+// deadline, which then takes precedence.
+func requestContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, timeout, errRequestTimeout)
+}
+
+// cancelBody cancels the context of a request once its response body is
+// closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer.
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
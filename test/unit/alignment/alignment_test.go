@@ -2,6 +2,7 @@ package alignment_test
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -730,4 +731,62 @@ func TestAlignmentConcurrency(t *testing.T) {
 			t.Fatal("Concurrent alignment timed out")
 		}
 	}
-}
\ No newline at end of file
+}
+
+// TestNormalizedOffsetMapping tests that matches map back to exact source spans
+// when normalization changes the length of the text
+func TestNormalizedOffsetMapping(t *testing.T) {
+	source := "John Doe works at Google.  John  Doe lives in  New York City."
+	aligner := alignment.NewMultiAligner()
+	opts := alignment.DefaultAlignmentOptions()
+
+	tests := []struct {
+		extracted string
+		expected  string
+	}{
+		{"New York City", "New York City"},
+		{"new york city", "New York City"},
+		{"lives in New York", "lives in  New York"},
+		{"New Yrok City", "New York City"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.extracted, func(t *testing.T) {
+			interval, _, err := aligner.AlignWithBestMethod(context.Background(), tt.extracted, source, opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := source[interval.StartPos:interval.EndPos]; got != tt.expected {
+				t.Errorf("Expected span %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("IgnorePunctuation", func(t *testing.T) {
+		punctOpts := opts
+		punctOpts.IgnorePunctuation = true
+		interval, _, err := alignment.NewExactMatcher().AlignExtraction(context.Background(), "Doe lives", "Mr. Doe, lives here", punctOpts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := "Mr. Doe, lives here"[interval.StartPos:interval.EndPos]; got != "Doe, lives" {
+			t.Errorf("Expected span %q, got %q", "Doe, lives", got)
+		}
+	})
+}
+
+// TestAlignmentErrorsAreNotShared tests that decorating common errors does not
+// mutate them and that decorated errors still match with errors.Is
+func TestAlignmentErrorsAreNotShared(t *testing.T) {
+	decorated := alignment.ErrNoAlignment.WithMethod("ExactMatcher").WithDetail("extracted_text", "x")
+
+	if alignment.ErrNoAlignment.Method != "" || len(alignment.ErrNoAlignment.Details) != 0 {
+		t.Error("Expected common error to remain unmodified")
+	}
+	if !errors.Is(decorated, alignment.ErrNoAlignment) {
+		t.Error("Expected decorated error to match ErrNoAlignment")
+	}
+	if errors.Is(decorated, alignment.ErrLowConfidence) {
+		t.Error("Expected decorated error not to match ErrLowConfidence")
+	}
+}
@@ -490,4 +490,42 @@ func TestChunkingConcurrency(t *testing.T) {
 			t.Fatal("Concurrent chunking timed out")
 		}
 	}
-}
\ No newline at end of file
+}
+
+// TestChunkIntervals verifies that every chunk's text is the exact source span of its CharInterval
+func TestChunkIntervals(t *testing.T) {
+	text := "First paragraph with a sentence.  Another one follows!\n\n" +
+		"  Second paragraph, indented. It has Dr. Smith and two   spaces. Short.\n\n\n" +
+		"Third paragraph? Yes. However, the topic changes here. Therefore we end."
+
+	chunkers := []chunking.TextChunker{
+		chunking.NewSimpleChunker(),
+		chunking.NewSemanticChunker(),
+		chunking.NewAdaptiveChunker(),
+	}
+
+	for _, chunker := range chunkers {
+		for _, overlap := range []float64{0.0, 0.3} {
+			opts := chunking.DefaultChunkingOptions()
+			opts.MaxCharBuffer = 60
+			opts.MinChunkSize = 10
+			opts.OverlapRatio = overlap
+
+			chunks, err := chunker.ChunkText(context.Background(), text, opts)
+			if err != nil {
+				t.Fatalf("%s: ChunkText() error = %v", chunker.Name(), err)
+			}
+
+			for _, chunk := range chunks {
+				start, end := chunk.CharInterval.StartPos, chunk.CharInterval.EndPos
+				if start < 0 || end > len(text) || start > end {
+					t.Fatalf("%s: chunk %d has invalid interval [%d, %d)", chunker.Name(), chunk.ChunkIndex, start, end)
+				}
+				if text[start:end] != chunk.Text {
+					t.Errorf("%s (overlap %.1f): chunk %d text %q does not match source %q",
+						chunker.Name(), overlap, chunk.ChunkIndex, chunk.Text, text[start:end])
+				}
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/sehwan505/langextract-go/pkg/document"
+	"github.com/sehwan505/langextract-go/pkg/types"
 )
 
 func TestNewDocument(t *testing.T) {
@@ -265,4 +266,101 @@ func TestDocument_IDGeneration(t *testing.T) {
 	if doc1.DocumentID() == doc4.DocumentID() {
 		t.Error("Documents with same text but different context should have different IDs")
 	}
-}
\ No newline at end of file
+}
+
+func TestDocument_Metadata(t *testing.T) {
+	doc := document.NewDocument("text")
+
+	if _, ok := doc.GetMetadata(document.MetadataSourceURL); ok {
+		t.Error("Expected no metadata on a new document")
+	}
+
+	doc.SetMetadata(document.MetadataSourceURL, "https://example.com")
+	if value, ok := doc.GetMetadata(document.MetadataSourceURL); !ok || value != "https://example.com" {
+		t.Errorf("GetMetadata() = %q, %v, want https://example.com, true", value, ok)
+	}
+}
+
+func TestNewDocumentFromHTML(t *testing.T) {
+	tests := []struct {
+		name   string
+		markup string
+		want   string
+	}{
+		{
+			name:   "inline tags and whitespace",
+			markup: "<p>John   <b>Smith</b>\n works</p>",
+			want:   "John Smith works",
+		},
+		{
+			name:   "block elements",
+			markup: "<h1>Title</h1><p>First</p><ul><li>one</li><li>two</li></ul>line<br>break",
+			want:   "Title\n\nFirst\n\none\ntwo\n\nline\nbreak",
+		},
+		{
+			name:   "non-content elements and comments",
+			markup: "<html><head><title>T</title><style>p{}</style></head><body><!-- note --><script>var s = \"</p>\";</script>Body</body></html>",
+			want:   "Body",
+		},
+		{
+			name:   "entities",
+			markup: "Fish &amp; Chips&nbsp;&lt;3 &#169; &bogus; AT&T",
+			want:   "Fish & Chips <3 \u00a9 &bogus; AT&T",
+		},
+		{
+			name:   "preformatted text",
+			markup: "<pre>a  b\nc</pre>",
+			want:   "a  b\nc",
+		},
+		{
+			name:   "stray angle bracket",
+			markup: "<p>x < y</p>",
+			want:   "x < y",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := document.NewDocumentFromHTML(tt.markup)
+			if doc.Text != tt.want {
+				t.Errorf("Text = %q, want %q", doc.Text, tt.want)
+			}
+			if doc.SourceMap == nil || doc.SourceMap.Source != tt.markup {
+				t.Error("Expected SourceMap to hold the original markup")
+			}
+		})
+	}
+}
+
+func TestSourceMap_SourceInterval(t *testing.T) {
+	markup := "<h1>Hello &amp; World</h1><p>John <b>Smith</b> works</p>"
+	doc := document.NewDocumentFromHTML(markup)
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Smith", "Smith"},
+		{"Hello & World", "Hello &amp; World"},
+		{"John Smith", "John <b>Smith"},
+	}
+
+	for _, tt := range tests {
+		start := strings.Index(doc.Text, tt.text)
+		if start < 0 {
+			t.Fatalf("%q not found in %q", tt.text, doc.Text)
+		}
+
+		interval, ok := doc.SourceMap.SourceInterval(types.CharInterval{StartPos: start, EndPos: start + len(tt.text)})
+		if !ok {
+			t.Fatalf("SourceInterval(%q) failed", tt.text)
+		}
+		if got := markup[interval.StartPos:interval.EndPos]; got != tt.want {
+			t.Errorf("SourceInterval(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	if _, ok := doc.SourceMap.SourceInterval(types.CharInterval{StartPos: 0, EndPos: len(doc.Text) + 1}); ok {
+		t.Error("Expected out-of-range interval to fail")
+	}
+}
@@ -0,0 +1,253 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sehwan505/langextract-go/internal/engine"
+	"github.com/sehwan505/langextract-go/pkg/document"
+	"github.com/sehwan505/langextract-go/pkg/extraction"
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// usageProvider reports fixed token usage through InferDetailed
+type usageProvider struct{}
+
+func (p usageProvider) Infer(ctx context.Context, prompts []string, options map[string]any) ([][]providers.ScoredOutput, error) {
+	results, err := p.InferDetailed(ctx, prompts, options)
+	if err != nil {
+		return nil, err
+	}
+	outputs := make([][]providers.ScoredOutput, len(results))
+	for i, result := range results {
+		outputs[i] = result.Outputs
+	}
+	return outputs, nil
+}
+
+func (usageProvider) InferDetailed(ctx context.Context, prompts []string, options map[string]any) ([]*providers.InferenceResult, error) {
+	results := make([]*providers.InferenceResult, len(prompts))
+	for i := range prompts {
+		results[i] = &providers.InferenceResult{
+			Outputs:      []providers.ScoredOutput{{Output: `{"extractions": []}`, Score: 1.0}},
+			Usage:        providers.Usage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+			FinishReason: providers.FinishReasonStop,
+			ResponseID:   "resp-1",
+			Latency:      15 * time.Millisecond,
+		}
+	}
+	return results, nil
+}
+
+func (usageProvider) ParseOutput(output string) (any, error) { return output, nil }
+func (usageProvider) ApplySchema(schema any)                 {}
+func (usageProvider) SetFenceOutput(enabled bool)            {}
+func (usageProvider) GetModelID() string                     { return "usage-model" }
+func (usageProvider) IsAvailable() bool                      { return true }
+
+func TestProviderManagerTokenUsage(t *testing.T) {
+	manager := engine.NewProviderManager(nil)
+	defer manager.Close()
+
+	request := engine.NewExtractionRequest(document.NewDocument("Alice met Bob."), "Extract people")
+	request.Provider = usageProvider{}
+	request.ProviderID = "usage"
+
+	response, err := manager.ExecuteWithFailover(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ExecuteWithFailover() error = %v", err)
+	}
+	if response.TokensUsed != 120 || response.PromptTokens != 100 || response.CompletionTokens != 20 {
+		t.Errorf("Unexpected token usage %+v", response)
+	}
+	if response.FinishReason != providers.FinishReasonStop || response.ResponseID != "resp-1" || response.Latency != 15*time.Millisecond {
+		t.Errorf("Unexpected response metadata %+v", response)
+	}
+
+	// A cached response uses no tokens
+	cached, err := manager.ExecuteWithFailover(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ExecuteWithFailover() error = %v", err)
+	}
+	if cached.TokensUsed != 0 || cached.Output != response.Output {
+		t.Errorf("Expected a cache hit without tokens, got %+v", cached)
+	}
+
+	health := manager.GetProviderHealth()["usage"]
+	if health == nil {
+		t.Fatal("Expected health stats for the provider")
+	}
+	if health.TotalTokens != 120 || health.PromptTokens != 100 || health.CompletionTokens != 20 {
+		t.Errorf("Unexpected health token usage %+v", health)
+	}
+	if health.FinishReasons[providers.FinishReasonStop] != 1 {
+		t.Errorf("Expected one stop finish reason, got %v", health.FinishReasons)
+	}
+}
+
+func TestProviderManagerCacheKey(t *testing.T) {
+	manager := engine.NewProviderManager(nil)
+	defer manager.Close()
+
+	newRequest := func() *engine.ExtractionRequest {
+		request := engine.NewExtractionRequest(document.NewDocument("Alice met Bob."), "Extract people")
+		request.Provider = usageProvider{}
+		request.ProviderID = "usage"
+		return request
+	}
+	execute := func(request *engine.ExtractionRequest) bool {
+		t.Helper()
+		response, err := manager.ExecuteWithFailover(context.Background(), request)
+		if err != nil {
+			t.Fatalf("ExecuteWithFailover() error = %v", err)
+		}
+		return response.Cached
+	}
+
+	if execute(newRequest()) || !execute(newRequest()) {
+		t.Fatal("Expected the same request to be served from the cache")
+	}
+
+	withExamples := newRequest()
+	example := extraction.NewExampleData("Carol met Dan.")
+	example.AddExtraction(extraction.NewExtraction("person", "Carol"))
+	withExamples.Examples = append(withExamples.Examples, example)
+	if execute(withExamples) {
+		t.Error("Expected a request with other examples not to be a cache hit")
+	}
+
+	otherProvider := newRequest()
+	otherProvider.ProviderID = "other-server"
+	if execute(otherProvider) {
+		t.Error("Expected a request to another provider not to be a cache hit")
+	}
+}
+
+// failingProvider fails every request with the given error
+type failingProvider struct {
+	err   error
+	calls int32
+}
+
+func (p *failingProvider) Infer(ctx context.Context, prompts []string, options map[string]any) ([][]providers.ScoredOutput, error) {
+	atomic.AddInt32(&p.calls, 1)
+	return nil, p.err
+}
+
+func (p *failingProvider) ParseOutput(output string) (any, error) { return output, nil }
+func (p *failingProvider) ApplySchema(schema any)                 {}
+func (p *failingProvider) SetFenceOutput(enabled bool)            {}
+func (p *failingProvider) GetModelID() string                     { return "failing-model" }
+func (p *failingProvider) IsAvailable() bool                      { return true }
+
+func TestProviderManagerLeavesRetriesToProviders(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		calls int32
+	}{
+		{"terminal", &providers.APIError{StatusCode: 401, Message: "invalid key", Kind: providers.ErrAuthentication}, 1},
+		{"unclassified", errors.New("failed to decode response"), 1},
+		{"retryable", &providers.APIError{StatusCode: 503, Message: "overloaded", Kind: providers.ErrServerError, RetryAfter: time.Millisecond}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := engine.NewProviderManager(nil)
+			defer manager.Close()
+
+			provider := &failingProvider{err: tt.err}
+			request := engine.NewExtractionRequest(document.NewDocument("Alice met Bob."), "Extract people")
+			request.Provider = provider
+			request.RetryCount = 1
+
+			_, err := manager.ExecuteWithFailover(context.Background(), request)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected the provider error, got %v", err)
+			}
+			if calls := atomic.LoadInt32(&provider.calls); calls != tt.calls {
+				t.Errorf("Expected %d calls, got %d", tt.calls, calls)
+			}
+		})
+	}
+}
+
+func TestProviderManagerModelCatalog(t *testing.T) {
+	catalog := "version: 1\nmodels:\n  - id: usage-model\n    provider: usage\n    context_window: 1000\n    max_output_tokens: 100\n    input_price: 1\n    output_price: 2\n"
+	if err := providers.DefaultCatalog().Load([]byte(catalog)); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	manager := engine.NewProviderManager(nil)
+	defer manager.Close()
+
+	request := engine.NewExtractionRequest(document.NewDocument("Alice met Bob."), "Extract people")
+	request.Provider = usageProvider{}
+	response, err := manager.ExecuteWithFailover(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ExecuteWithFailover() error = %v", err)
+	}
+	// 100 prompt tokens at $1/1M and 20 completion tokens at $2/1M
+	if want := 140e-6; response.CostUSD < want-1e-12 || response.CostUSD > want+1e-12 {
+		t.Errorf("Expected a cost of %v, got %v", want, response.CostUSD)
+	}
+
+	long := engine.NewExtractionRequest(document.NewDocument(strings.Repeat("Alice met Bob. ", 400)), "Extract people")
+	long.Provider = usageProvider{}
+	if _, err := manager.ExecuteWithFailover(context.Background(), long); !errors.Is(err, providers.ErrContextLengthExceeded) {
+		t.Errorf("Expected a context length error, got %v", err)
+	}
+}
+
+// chatProvider records the conversations it receives through InferMessages
+type chatProvider struct {
+	usageProvider
+	conversations [][]providers.Message
+}
+
+func (p *chatProvider) InferMessages(ctx context.Context, conversations [][]providers.Message, options map[string]any) ([]*providers.InferenceResult, error) {
+	p.conversations = append(p.conversations, conversations...)
+	return p.InferDetailed(ctx, make([]string, len(conversations)), options)
+}
+
+func TestProviderManagerChatMessages(t *testing.T) {
+	manager := engine.NewProviderManager(nil)
+	defer manager.Close()
+
+	provider := &chatProvider{}
+	request := engine.NewExtractionRequest(document.NewDocument("Alice met Bob."), "Extract people")
+	request.Provider = provider
+	request.Examples = []*extraction.ExampleData{
+		extraction.NewExampleDataWithExtractions("Carol called Dave.", []*extraction.Extraction{extraction.NewExtraction("person", "Carol")}),
+	}
+
+	if _, err := manager.ExecuteWithFailover(context.Background(), request); err != nil {
+		t.Fatalf("ExecuteWithFailover() error = %v", err)
+	}
+	if len(provider.conversations) != 1 {
+		t.Fatalf("Expected one conversation, got %d", len(provider.conversations))
+	}
+
+	messages := provider.conversations[0]
+	var roles []string
+	for _, message := range messages {
+		roles = append(roles, message.Role)
+	}
+	want := []string{providers.RoleSystem, providers.RoleUser, providers.RoleAssistant, providers.RoleUser}
+	if strings.Join(roles, ",") != strings.Join(want, ",") {
+		t.Fatalf("Expected roles %v, got %v", want, roles)
+	}
+	if !strings.Contains(messages[0].Content, "Extract people") {
+		t.Errorf("Expected the task in the system message, got %q", messages[0].Content)
+	}
+	if !strings.Contains(messages[1].Content, "Carol called Dave.") || !strings.Contains(messages[2].Content, `"extractions"`) {
+		t.Errorf("Expected the example as user and assistant turns, got %q and %q", messages[1].Content, messages[2].Content)
+	}
+	if !strings.Contains(messages[3].Content, "Alice met Bob.") {
+		t.Errorf("Expected the text in the last user turn, got %q", messages[3].Content)
+	}
+}
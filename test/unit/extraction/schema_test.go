@@ -2,6 +2,7 @@ package extraction_test
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -234,6 +235,9 @@ func TestSchemaJSONSchemaGeneration(t *testing.T) {
 								"extraction_text": map[string]interface{}{
 									"type": "string",
 								},
+								"confidence": map[string]interface{}{
+									"type": "number",
+								},
 							},
 							"required": []string{"extraction_class", "extraction_text"},
 						},
@@ -270,6 +274,9 @@ func TestSchemaJSONSchemaGeneration(t *testing.T) {
 								"extraction_text": map[string]interface{}{
 									"type": "string",
 								},
+								"confidence": map[string]interface{}{
+									"type": "number",
+								},
 							},
 							"required": []string{"extraction_class", "extraction_text"},
 						},
@@ -310,6 +317,9 @@ func TestSchemaJSONSchemaGeneration(t *testing.T) {
 								"extraction_text": map[string]interface{}{
 									"type": "string",
 								},
+								"confidence": map[string]interface{}{
+									"type": "number",
+								},
 							},
 							"required": []string{"extraction_class", "extraction_text"},
 						},
@@ -348,6 +358,38 @@ func TestSchemaJSONSchemaGeneration(t *testing.T) {
 	}
 }
 
+// TestSchemaJSONSchemaRequiredFields tests that required fields are listed as
+// required properties of the extractions
+func TestSchemaJSONSchemaRequiredFields(t *testing.T) {
+	schema := extraction.NewBasicExtractionSchema("required_fields", "Required fields")
+	schema.AddGlobalField(&extraction.FieldDefinition{Name: "source", Type: "string", Required: true})
+	schema.AddClass(&extraction.ClassDefinition{
+		Name: "person",
+		Fields: []*extraction.FieldDefinition{
+			{Name: "name", Type: "string", Required: true},
+			{Name: "age", Type: "number", Required: true},
+		},
+	})
+	schema.AddClass(&extraction.ClassDefinition{
+		Name: "company",
+		Fields: []*extraction.FieldDefinition{
+			{Name: "name", Type: "string", Required: true},
+		},
+	})
+
+	jsonSchema, err := schema.ToJSONSchema()
+	if err != nil {
+		t.Fatalf("ToJSONSchema() error = %v", err)
+	}
+	items := jsonSchema["properties"].(map[string]interface{})["extractions"].(map[string]interface{})["items"].(map[string]interface{})
+
+	// age is only required for persons, so it stays optional
+	expected := []string{"extraction_class", "extraction_text", "source", "name"}
+	if !reflect.DeepEqual(items["required"], expected) {
+		t.Errorf("Expected required properties %v, got %v", expected, items["required"])
+	}
+}
+
 // TestExtractionTaskValidation tests extraction task validation
 func TestExtractionTaskValidation(t *testing.T) {
 	// Create a valid schema
@@ -0,0 +1,264 @@
+package langextract_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sehwan505/langextract-go/pkg/document"
+	"github.com/sehwan505/langextract-go/pkg/langextract"
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// trackingProvider echoes the first word of the document and records how many
+// calls are in flight at once.
+type trackingProvider struct {
+	stubProvider
+	delay       time.Duration
+	failOn      string
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (p *trackingProvider) Infer(ctx context.Context, prompts []string, options map[string]interface{}) ([][]providers.ScoredOutput, error) {
+	p.mu.Lock()
+	p.inFlight++
+	if p.inFlight > p.maxInFlight {
+		p.maxInFlight = p.inFlight
+	}
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		p.inFlight--
+		p.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(p.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	results := make([][]providers.ScoredOutput, len(prompts))
+	for i, prompt := range prompts {
+		text := prompt[strings.Index(prompt, "Text to process:\n")+len("Text to process:\n"):]
+		word := strings.Fields(text)[0]
+		if p.failOn != "" && word == p.failOn {
+			return nil, fmt.Errorf("cannot process %s", word)
+		}
+		output := fmt.Sprintf(`{"extractions": [{"extraction_class": "word", "extraction_text": %q}]}`, word)
+		results[i] = []providers.ScoredOutput{{Output: output, Score: 1.0}}
+	}
+	return results, nil
+}
+
+func newBatchConfig(maxConcurrency int) *langextract.Config {
+	config := langextract.DefaultConfig()
+	config.MaxConcurrency = maxConcurrency
+	return config
+}
+
+// TestExtractDocumentsOrderAndConcurrency verifies ordering and the concurrency cap
+func TestExtractDocumentsOrderAndConcurrency(t *testing.T) {
+	provider := &trackingProvider{stubProvider: stubProvider{modelID: "stub-model"}, delay: 20 * time.Millisecond}
+
+	texts := make([]string, 12)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("doc%02d is a document.", i)
+	}
+
+	var callbackMu sync.Mutex
+	completed := make(map[int]bool)
+	opts := newTestOptions(provider).WithDocumentCallback(func(result *langextract.DocumentResult) {
+		callbackMu.Lock()
+		defer callbackMu.Unlock()
+		if completed[result.Index] {
+			t.Errorf("Callback called twice for document %d", result.Index)
+		}
+		completed[result.Index] = true
+	})
+
+	batch, err := langextract.ExtractDocuments(texts, opts, newBatchConfig(3))
+	if err != nil {
+		t.Fatalf("ExtractDocuments() error = %v", err)
+	}
+
+	if len(batch.Results) != len(texts) {
+		t.Fatalf("Expected %d results, got %d", len(texts), len(batch.Results))
+	}
+	if batch.Succeeded != len(texts) || batch.Failed != 0 {
+		t.Errorf("Expected %d succeeded and 0 failed, got %d and %d", len(texts), batch.Succeeded, batch.Failed)
+	}
+
+	for i, result := range batch.Results {
+		if result.Index != i {
+			t.Errorf("Result %d has index %d", i, result.Index)
+		}
+		if result.Error != nil {
+			t.Fatalf("Result %d unexpected error: %v", i, result.Error)
+		}
+		want := fmt.Sprintf("doc%02d", i)
+		if result.Document.Extractions[0].ExtractionText != want {
+			t.Errorf("Result %d: expected extraction %q, got %q", i, want, result.Document.Extractions[0].ExtractionText)
+		}
+		if result.Document.Document.Text != texts[i] {
+			t.Errorf("Result %d: document text mismatch", i)
+		}
+	}
+
+	if len(completed) != len(texts) {
+		t.Errorf("Expected %d callbacks, got %d", len(texts), len(completed))
+	}
+	if provider.maxInFlight > 3 {
+		t.Errorf("Expected at most 3 documents in flight, got %d", provider.maxInFlight)
+	}
+	if provider.maxInFlight < 2 {
+		t.Errorf("Expected documents to be processed concurrently, max in flight was %d", provider.maxInFlight)
+	}
+}
+
+// TestExtractDocumentsSharesRequestLimit verifies that the chunk requests of
+// concurrent documents share the concurrency cap
+func TestExtractDocumentsSharesRequestLimit(t *testing.T) {
+	provider := &trackingProvider{stubProvider: stubProvider{modelID: "stub-model"}, delay: 20 * time.Millisecond}
+
+	text, _ := parallelTestText()
+	texts := make([]string, 4)
+	for i := range texts {
+		texts[i] = strings.ReplaceAll(text, " opened", fmt.Sprintf("%d opened", i))
+	}
+
+	batch, err := langextract.ExtractDocuments(texts, parallelTestOptions(provider, true), newBatchConfig(3))
+	if err != nil {
+		t.Fatalf("ExtractDocuments() error = %v", err)
+	}
+	if batch.Succeeded != len(texts) {
+		t.Fatalf("Expected %d documents to succeed, got %d: %v", len(texts), batch.Succeeded, batch.Errors())
+	}
+	if provider.maxInFlight > 3 {
+		t.Errorf("Expected at most 3 requests in flight, got %d", provider.maxInFlight)
+	}
+	if provider.maxInFlight < 2 {
+		t.Errorf("Expected requests to run concurrently, max in flight was %d", provider.maxInFlight)
+	}
+}
+
+// TestExtractDocumentsPartialFailure verifies that one failure does not fail the batch
+func TestExtractDocumentsPartialFailure(t *testing.T) {
+	provider := &trackingProvider{stubProvider: stubProvider{modelID: "stub-model"}, failOn: "bad"}
+
+	docs := []*document.Document{
+		document.NewDocument("good first document"),
+		document.NewDocument("bad second document"),
+		nil,
+		document.NewDocument("good last document"),
+	}
+
+	batch, err := langextract.ExtractDocuments(docs, newTestOptions(provider), newBatchConfig(2))
+	if err != nil {
+		t.Fatalf("ExtractDocuments() error = %v", err)
+	}
+
+	if batch.Succeeded != 2 || batch.Failed != 2 {
+		t.Errorf("Expected 2 succeeded and 2 failed, got %d and %d", batch.Succeeded, batch.Failed)
+	}
+
+	if batch.Results[0].Error != nil || batch.Results[3].Error != nil {
+		t.Errorf("Expected good documents to succeed: %v, %v", batch.Results[0].Error, batch.Results[3].Error)
+	}
+	if err := batch.Results[1].Error; err == nil || !strings.Contains(err.Error(), "cannot process bad") {
+		t.Errorf("Expected provider error for document 1, got %v", err)
+	}
+	if err := batch.Results[2].Error; err == nil || !strings.Contains(err.Error(), "document cannot be nil") {
+		t.Errorf("Expected nil document error for document 2, got %v", err)
+	}
+	if batch.Results[1].Document != nil {
+		t.Error("Expected no document for failed result")
+	}
+
+	if len(batch.Errors()) != 2 {
+		t.Errorf("Expected 2 errors, got %d", len(batch.Errors()))
+	}
+}
+
+// TestExtractDocumentsCancellation verifies that pending documents report cancellation
+func TestExtractDocumentsCancellation(t *testing.T) {
+	provider := &trackingProvider{stubProvider: stubProvider{modelID: "stub-model"}, delay: time.Second}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	texts := []string{"one", "two", "three", "four"}
+	start := time.Now()
+	batch, err := langextract.ExtractDocuments(texts, newTestOptions(provider).WithContext(ctx), newBatchConfig(1))
+	if err != nil {
+		t.Fatalf("ExtractDocuments() error = %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected batch to stop promptly after cancellation, took %v", elapsed)
+	}
+	if batch.Failed != len(texts) {
+		t.Errorf("Expected all %d documents to fail, got %d", len(texts), batch.Failed)
+	}
+	for i, result := range batch.Results {
+		if result == nil || result.Error == nil {
+			t.Errorf("Expected error for document %d", i)
+		}
+	}
+}
+
+// TestExtractMultipleDocumentsRequiresBatch verifies that Extract points to ExtractDocuments
+func TestExtractMultipleDocumentsRequiresBatch(t *testing.T) {
+	provider := &stubProvider{modelID: "stub-model", responses: []string{`{"extractions": []}`}}
+
+	_, err := langextract.Extract([]string{"one", "two"}, newTestOptions(provider))
+	if err == nil || !strings.Contains(err.Error(), "ExtractDocuments") {
+		t.Errorf("Expected error pointing to ExtractDocuments, got %v", err)
+	}
+}
+
+// TestExtractDocumentsBudget verifies that a budget stops a batch cleanly
+func TestExtractDocumentsBudget(t *testing.T) {
+	// A dollar per prompt token makes the cost of a document its token count
+	catalog := "version: 1\nmodels:\n  - id: budget-model\n    provider: budget\n    input_price: 1000000\n"
+	if err := providers.DefaultCatalog().Load([]byte(catalog)); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	provider := &trackingProvider{stubProvider: stubProvider{modelID: "budget-model"}}
+	texts := []string{"doc00 is a document.", "doc01 is a document.", "doc02 is a document.", "doc03 is a document."}
+
+	probe, err := langextract.ExtractDocuments(texts[:1], newTestOptions(provider), newBatchConfig(1))
+	if err != nil || probe.Succeeded != 1 {
+		t.Fatalf("ExtractDocuments() = %+v, %v", probe, err)
+	}
+	perDocument := probe.Spend.CostUSD
+	if perDocument <= 0 || probe.Spend.Requests != 1 || probe.Results[0].Metadata.EstimatedCostUSD != perDocument {
+		t.Fatalf("Expected the spend of one priced request, got %+v", probe.Spend)
+	}
+
+	budget := langextract.NewBudget(langextract.BudgetLimits{MaxCostUSD: perDocument * 1.5})
+	batch, err := langextract.ExtractDocuments(texts, newTestOptions(provider).WithBudget(budget), newBatchConfig(1))
+	if err != nil {
+		t.Fatalf("ExtractDocuments() error = %v", err)
+	}
+
+	if batch.Succeeded != 1 || !batch.BudgetExceeded {
+		t.Fatalf("Expected the budget to stop the batch after one document, got %d succeeded", batch.Succeeded)
+	}
+	for _, result := range batch.Results[1:] {
+		if !errors.Is(result.Error, langextract.ErrBudgetExceeded) {
+			t.Errorf("Expected document %d to fail with the budget error, got %v", result.Index, result.Error)
+		}
+	}
+	// The refused and skipped documents never reached the provider
+	if spent := budget.Spent(); spent.Requests != 1 || spent.CostUSD != perDocument || batch.Spend.Requests != 1 {
+		t.Errorf("Expected the budget and batch to record one request, got %+v and %+v", spent, batch.Spend)
+	}
+}
@@ -0,0 +1,132 @@
+package langextract_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sehwan505/langextract-go/pkg/langextract"
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// nameProvider extracts every capitalized name from the text it is given and
+// records the texts it saw.
+type nameProvider struct {
+	stubProvider
+	texts []string
+}
+
+func (p *nameProvider) Infer(ctx context.Context, prompts []string, options map[string]interface{}) ([][]providers.ScoredOutput, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	results := make([][]providers.ScoredOutput, len(prompts))
+	for i, prompt := range prompts {
+		text := prompt[strings.Index(prompt, "Text to process:\n")+len("Text to process:\n"):]
+		text = text[:strings.Index(text, "\n\nPlease extract")]
+		p.texts = append(p.texts, text)
+
+		var items []string
+		for _, word := range strings.Fields(text) {
+			word = strings.Trim(word, ".,")
+			if strings.HasPrefix(word, "Person") {
+				items = append(items, fmt.Sprintf(`{"extraction_class": "person", "extraction_text": %q}`, word))
+			}
+		}
+		output := `{"extractions": [` + strings.Join(items, ",") + `]}`
+		results[i] = []providers.ScoredOutput{{Output: output, Score: 1.0}}
+	}
+	return results, nil
+}
+
+func chunkingTestText() string {
+	var sentences []string
+	for i := 0; i < 12; i++ {
+		sentences = append(sentences, fmt.Sprintf("Person%02d met a colleague at the office today.", i))
+	}
+	return strings.Join(sentences, " ")
+}
+
+// TestExtractChunkedDocument verifies that chunk extractions are remapped to document positions
+func TestExtractChunkedDocument(t *testing.T) {
+	tests := []struct {
+		strategy langextract.ChunkingStrategy
+		overlaps bool
+	}{
+		{langextract.ChunkingSimple, false},
+		{langextract.ChunkingSemantic, true},
+		{langextract.ChunkingAdaptive, true},
+	}
+
+	text := chunkingTestText()
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			provider := &nameProvider{stubProvider: stubProvider{modelID: "stub-model"}}
+
+			chunkingOpts := langextract.DefaultChunkingOptions()
+			chunkingOpts.MaxCharBuffer = 150
+			chunkingOpts.OverlapRatio = 0.45
+			chunkingOpts.MinChunkSize = 10
+			opts := newTestOptions(provider).WithChunking(tt.strategy, chunkingOpts)
+
+			result, err := langextract.ExtractWithMetadata(text, opts)
+			if err != nil {
+				t.Fatalf("ExtractWithMetadata() error = %v", err)
+			}
+
+			if len(provider.texts) < 2 {
+				t.Fatalf("Expected the document to be split into several chunks, got %d provider calls", len(provider.texts))
+			}
+			if result.Metadata.ChunksProcessed != len(provider.texts) {
+				t.Errorf("Expected ChunksProcessed %d, got %d", len(provider.texts), result.Metadata.ChunksProcessed)
+			}
+			mentions := 0
+			for _, chunkText := range provider.texts {
+				if !strings.Contains(text, chunkText) {
+					t.Errorf("Chunk text is not a substring of the document: %q", chunkText)
+				}
+				mentions += strings.Count(chunkText, "Person")
+			}
+			if tt.overlaps && mentions <= 12 {
+				t.Errorf("Expected overlapping chunks to repeat some names, got %d mentions", mentions)
+			}
+
+			seen := make(map[string]bool)
+			for _, ext := range result.Document.Extractions {
+				if ext.CharInterval == nil {
+					t.Fatalf("Extraction %q is not grounded", ext.ExtractionText)
+				}
+				if got := text[ext.CharInterval.StartPos:ext.CharInterval.EndPos]; got != ext.ExtractionText {
+					t.Errorf("Interval of %q points at %q", ext.ExtractionText, got)
+				}
+				if seen[ext.ExtractionText] {
+					t.Errorf("Duplicate extraction %q from overlapping chunks", ext.ExtractionText)
+				}
+				seen[ext.ExtractionText] = true
+			}
+
+			if len(seen) != 12 {
+				t.Errorf("Expected 12 unique extractions, got %d", len(seen))
+			}
+		})
+	}
+}
+
+// TestExtractChunkingValidation verifies that chunking options are validated
+func TestExtractChunkingValidation(t *testing.T) {
+	provider := &stubProvider{modelID: "stub-model", responses: []string{`{"extractions": []}`}}
+
+	opts := newTestOptions(provider).WithChunkingStrategy("paragraphs")
+	if _, err := langextract.Extract("Some text.", opts); err == nil {
+		t.Error("Expected error for unknown chunking strategy")
+	}
+
+	chunkingOpts := langextract.DefaultChunkingOptions()
+	chunkingOpts.MaxCharBuffer = 0
+	opts = newTestOptions(provider).WithChunking(langextract.ChunkingSimple, chunkingOpts)
+	if _, err := langextract.Extract("Some text.", opts); err == nil {
+		t.Error("Expected error for invalid chunking options")
+	}
+}
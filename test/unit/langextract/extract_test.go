@@ -0,0 +1,442 @@
+package langextract_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sehwan505/langextract-go/pkg/document"
+	"github.com/sehwan505/langextract-go/pkg/langextract"
+	"github.com/sehwan505/langextract-go/pkg/providers"
+	"github.com/sehwan505/langextract-go/pkg/types"
+)
+
+// stubProvider is a minimal BaseLanguageModel returning canned responses.
+type stubProvider struct {
+	mu        sync.Mutex
+	modelID   string
+	responses []string
+	err       error
+	prompts   []string
+	schema    interface{}
+}
+
+func (s *stubProvider) Infer(ctx context.Context, prompts []string, options map[string]interface{}) ([][]providers.ScoredOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	results := make([][]providers.ScoredOutput, len(prompts))
+	for i, prompt := range prompts {
+		response := s.responses[len(s.prompts)%len(s.responses)]
+		s.prompts = append(s.prompts, prompt)
+		results[i] = []providers.ScoredOutput{{Output: response, Score: 1.0}}
+	}
+	return results, nil
+}
+
+func (s *stubProvider) ParseOutput(output string) (interface{}, error) {
+	return output, nil
+}
+
+func (s *stubProvider) ApplySchema(schema interface{}) {
+	s.schema = schema
+}
+
+func (s *stubProvider) SetFenceOutput(enabled bool) {}
+
+func (s *stubProvider) GetModelID() string {
+	return s.modelID
+}
+
+func (s *stubProvider) IsAvailable() bool {
+	return true
+}
+
+func (s *stubProvider) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.prompts)
+}
+
+func newTestOptions(provider providers.BaseLanguageModel) *langextract.ExtractOptions {
+	return langextract.NewExtractOptions().
+		WithPromptDescription("Extract people and organizations").
+		WithProvider(provider).
+		WithRetryCount(0)
+}
+
+// TestExtractUsesEngine verifies that Extract runs through the extraction engine
+func TestExtractUsesEngine(t *testing.T) {
+	provider := &stubProvider{
+		modelID: "stub-model",
+		responses: []string{"```json\n" + `{"extractions": [
+			{"extraction_class": "person", "extraction_text": "John Doe", "confidence": 0.9},
+			{"extraction_class": "organization", "extraction_text": "Google"}
+		]}` + "\n```"},
+	}
+
+	result, err := langextract.ExtractWithMetadata("John Doe works at Google.", newTestOptions(provider))
+	if err != nil {
+		t.Fatalf("ExtractWithMetadata() error = %v", err)
+	}
+
+	if got := len(result.Document.Extractions); got != 2 {
+		t.Fatalf("Expected 2 extractions, got %d", got)
+	}
+	if result.Document.Extractions[0].ExtractionText != "John Doe" {
+		t.Errorf("Expected first extraction 'John Doe', got %q", result.Document.Extractions[0].ExtractionText)
+	}
+	if conf, ok := result.Document.Extractions[0].GetConfidence(); !ok || conf != 0.9 {
+		t.Errorf("Expected confidence 0.9, got %v (present=%v)", conf, ok)
+	}
+
+	if provider.callCount() != 1 {
+		t.Errorf("Expected 1 provider call, got %d", provider.callCount())
+	}
+	if !strings.Contains(provider.prompts[0], "John Doe works at Google.") {
+		t.Error("Expected prompt to contain the document text")
+	}
+	if !strings.Contains(provider.prompts[0], "Extract people and organizations") {
+		t.Error("Expected prompt to contain the task description")
+	}
+
+	metadata := result.Metadata
+	if metadata.PassesCompleted != 1 {
+		t.Errorf("Expected 1 completed pass, got %d", metadata.PassesCompleted)
+	}
+	if metadata.ModelUsed != "stub-model" {
+		t.Errorf("Expected model 'stub-model', got %q", metadata.ModelUsed)
+	}
+	if metadata.ProviderUsed == "" {
+		t.Error("Expected provider used to be reported")
+	}
+	if metadata.ExtractionCount != 2 {
+		t.Errorf("Expected extraction count 2, got %d", metadata.ExtractionCount)
+	}
+
+	steps := make(map[string]bool)
+	for _, step := range metadata.ProcessingSteps {
+		steps[step.Name] = true
+	}
+	for _, name := range []string{"initialization", "preprocessing", "extraction", "aggregation", "finalization"} {
+		if !steps[name] {
+			t.Errorf("Expected processing step %q in metadata", name)
+		}
+	}
+}
+
+// TestExtractReturnsAnnotatedDocument verifies the document-only entry point
+func TestExtractReturnsAnnotatedDocument(t *testing.T) {
+	provider := &stubProvider{
+		modelID:   "stub-model",
+		responses: []string{`{"extractions": [{"extraction_class": "person", "extraction_text": "Ada"}]}`},
+	}
+
+	doc := document.NewDocumentWithContext("Ada wrote the first program.", "history")
+	result, err := langextract.Extract(doc, newTestOptions(provider))
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	if result.Document != doc {
+		t.Error("Expected annotated document to wrap the input document")
+	}
+	if result.ExtractionCount() != 1 {
+		t.Errorf("Expected 1 extraction, got %d", result.ExtractionCount())
+	}
+	if !strings.Contains(provider.prompts[0], "history") {
+		t.Error("Expected prompt to include additional context")
+	}
+}
+
+// TestExtractErrors verifies error propagation from the engine
+func TestExtractErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       langextract.TextOrDocuments
+		provider    *stubProvider
+		errorSubstr string
+	}{
+		{
+			name:        "provider failure",
+			input:       "Some text",
+			provider:    &stubProvider{modelID: "stub-model", err: fmt.Errorf("boom")},
+			errorSubstr: "boom",
+		},
+		{
+			name:        "malformed response",
+			input:       "Some text",
+			provider:    &stubProvider{modelID: "stub-model", responses: []string{"not json"}},
+			errorSubstr: "not valid JSON",
+		},
+		{
+			name:        "empty document",
+			input:       document.NewDocument("   "),
+			provider:    &stubProvider{modelID: "stub-model", responses: []string{`{"extractions": []}`}},
+			errorSubstr: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := langextract.Extract(tt.input, newTestOptions(tt.provider))
+			if err == nil {
+				t.Fatalf("Expected error containing %q, got nil", tt.errorSubstr)
+			}
+			if !strings.Contains(err.Error(), tt.errorSubstr) {
+				t.Errorf("Expected error containing %q, got %q", tt.errorSubstr, err.Error())
+			}
+			if result != nil {
+				t.Error("Expected nil result on error")
+			}
+		})
+	}
+}
+
+// TestExtractGroundsExtractions verifies that extractions are aligned to the source text
+func TestExtractGroundsExtractions(t *testing.T) {
+	text := "John Doe met Jane Roe.  Later, john doe called Acme  Corp."
+	provider := &stubProvider{
+		modelID: "stub-model",
+		responses: []string{`{"extractions": [
+			{"extraction_class": "person", "extraction_text": "John Doe"},
+			{"extraction_class": "person", "extraction_text": "Jane Roe"},
+			{"extraction_class": "person", "extraction_text": "John Doe"},
+			{"extraction_class": "organization", "extraction_text": "Acme Corp"},
+			{"extraction_class": "organization", "extraction_text": "Globex"}
+		]}`},
+	}
+
+	result, err := langextract.ExtractWithMetadata(text, newTestOptions(provider))
+	if err != nil {
+		t.Fatalf("ExtractWithMetadata() error = %v", err)
+	}
+
+	extractions := result.Document.Extractions
+	if len(extractions) != 5 {
+		t.Fatalf("Expected 5 extractions, got %d", len(extractions))
+	}
+
+	expected := []struct {
+		span   string
+		tokens [2]int
+		status types.AlignmentStatus
+	}{
+		{"John Doe", [2]int{0, 2}, types.AlignmentExact},
+		{"Jane Roe", [2]int{3, 5}, types.AlignmentExact},
+		{"john doe", [2]int{6, 8}, types.AlignmentExact},
+		{"Acme  Corp", [2]int{9, 11}, types.AlignmentExact},
+	}
+
+	for i, want := range expected {
+		ext := extractions[i]
+		if ext.CharInterval == nil {
+			t.Fatalf("Extraction %d (%s): expected char interval", i, ext.ExtractionText)
+		}
+		if got := text[ext.CharInterval.StartPos:ext.CharInterval.EndPos]; got != want.span {
+			t.Errorf("Extraction %d: expected span %q, got %q", i, want.span, got)
+		}
+		if ext.TokenInterval == nil || ext.TokenInterval.StartToken != want.tokens[0] || ext.TokenInterval.EndToken != want.tokens[1] {
+			t.Errorf("Extraction %d: expected tokens %v, got %v", i, want.tokens, ext.TokenInterval)
+		}
+		if ext.AlignmentStatus == nil || *ext.AlignmentStatus != want.status {
+			t.Errorf("Extraction %d: expected status %s, got %v", i, want.status, ext.AlignmentStatus)
+		}
+	}
+
+	ungrounded := extractions[4]
+	if ungrounded.CharInterval != nil {
+		t.Errorf("Expected ungroundable extraction to have no interval, got %v", ungrounded.CharInterval)
+	}
+	if ungrounded.AlignmentStatus == nil || *ungrounded.AlignmentStatus != types.AlignmentNone {
+		t.Errorf("Expected ungroundable extraction to have status none, got %v", ungrounded.AlignmentStatus)
+	}
+
+	if result.Metadata.TextCoverage <= 0 {
+		t.Error("Expected positive text coverage for grounded extractions")
+	}
+}
+
+// TestExtractUngroundedPolicy verifies the keep, drop and flag policies
+func TestExtractUngroundedPolicy(t *testing.T) {
+	response := `{"extractions": [
+		{"extraction_class": "person", "extraction_text": "Ada"},
+		{"extraction_class": "person", "extraction_text": "Grace Hopper"}
+	]}`
+
+	tests := []struct {
+		policy        langextract.UngroundedPolicy
+		expectedCount int
+		expectFlag    bool
+	}{
+		{langextract.UngroundedKeep, 2, false},
+		{langextract.UngroundedDrop, 1, false},
+		{langextract.UngroundedFlag, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			provider := &stubProvider{modelID: "stub-model", responses: []string{response}}
+			opts := newTestOptions(provider).WithUngroundedPolicy(tt.policy)
+
+			result, err := langextract.Extract("Ada wrote the first program.", opts)
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+
+			if len(result.Extractions) != tt.expectedCount {
+				t.Fatalf("Expected %d extractions, got %d", tt.expectedCount, len(result.Extractions))
+			}
+
+			if _, flagged := result.Extractions[0].GetAttribute(langextract.UngroundedAttribute); flagged {
+				t.Error("Expected grounded extraction not to be flagged")
+			}
+
+			if tt.expectedCount == 2 {
+				_, flagged := result.Extractions[1].GetAttribute(langextract.UngroundedAttribute)
+				if flagged != tt.expectFlag {
+					t.Errorf("Expected flagged=%v for ungrounded extraction, got %v", tt.expectFlag, flagged)
+				}
+			}
+		})
+	}
+}
+
+// TestExtractAlignmentOptions verifies that alignment options are honored and validated
+func TestExtractAlignmentOptions(t *testing.T) {
+	provider := &stubProvider{
+		modelID:   "stub-model",
+		responses: []string{`{"extractions": [{"extraction_class": "person", "extraction_text": "ADA"}]}`},
+	}
+
+	strict := langextract.DefaultAlignmentOptions().WithCaseSensitive(true).WithMaxDistance(0)
+	opts := newTestOptions(provider).WithAlignmentOptions(strict)
+
+	result, err := langextract.Extract("Ada wrote the first program.", opts)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if result.Extractions[0].CharInterval != nil {
+		t.Errorf("Expected case-sensitive alignment to fail, got %v", result.Extractions[0].CharInterval)
+	}
+
+	invalid := langextract.DefaultAlignmentOptions().WithMinConfidence(2.0)
+	if _, err := langextract.Extract("Ada", newTestOptions(provider).WithAlignmentOptions(invalid)); err == nil {
+		t.Error("Expected error for invalid alignment options")
+	}
+	if _, err := langextract.Extract("Ada", newTestOptions(provider).WithUngroundedPolicy("ignore")); err == nil {
+		t.Error("Expected error for unknown ungrounded policy")
+	}
+}
+
+// TestExtractRegisteredProvider verifies that Extract uses providers registered by the application
+func TestExtractRegisteredProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"choices": [{"message": {"role": "assistant", "content": "{\"extractions\": [{\"extraction_class\": \"person\", \"extraction_text\": \"Ada\"}]}"}}]}`)
+	}))
+	defer server.Close()
+
+	err := providers.RegisterOpenAICompatible(providers.OpenAICompatibleConfig{
+		Name:    "test-local-server",
+		BaseURL: server.URL + "/v1",
+		Models:  []string{"test-local-model"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterOpenAICompatible() error = %v", err)
+	}
+	t.Cleanup(func() { providers.Unregister("test-local-server") })
+
+	opts := langextract.NewExtractOptions().
+		WithPromptDescription("Extract people").
+		WithModelID("test-local-model").
+		WithRetryCount(0)
+
+	result, err := langextract.Extract("Ada wrote the first program.", opts)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if len(result.Extractions) != 1 || result.Extractions[0].ExtractionText != "Ada" {
+		t.Errorf("Unexpected extractions %v", result.Extractions)
+	}
+}
+
+// TestExtractRetryCount verifies that RetryCount is the only retry layer for
+// providers created from the options
+func TestExtractRetryCount(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		http.Error(w, `{"error": {"message": "overloaded"}}`, http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	config := providers.NewModelConfig("gpt-4o-mini").
+		WithProvider("openai").
+		WithProviderKwargs(map[string]any{"api_key": "test-key", "base_url": server.URL})
+	opts := langextract.NewExtractOptions().
+		WithPromptDescription("Extract people").
+		WithModelConfig(config).
+		WithRetryCount(1)
+
+	if _, err := langextract.Extract("Ada wrote the first program.", opts); err == nil || !errors.Is(err, providers.ErrServerError) {
+		t.Fatalf("Expected a server error, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if requests != 2 {
+		t.Errorf("Expected one attempt and one retry, got %d requests", requests)
+	}
+}
+
+// TestExtractUsesGlobalHTTPConfig verifies that the proxy of the global
+// configuration reaches the provider created by Extract
+func TestExtractUsesGlobalHTTPConfig(t *testing.T) {
+	var mu sync.Mutex
+	var hosts []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hosts = append(hosts, r.URL.Host)
+		mu.Unlock()
+		fmt.Fprint(w, `{"choices": [{"message": {"role": "assistant", "content": "{\"extractions\": [{\"extraction_class\": \"person\", \"extraction_text\": \"Ada\"}]}"}}]}`)
+	}))
+	defer proxy.Close()
+
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("LANGEXTRACT_PROXY_URL", proxy.URL)
+	langextract.ResetGlobalConfig()
+	t.Cleanup(langextract.ResetGlobalConfig)
+
+	config := providers.NewModelConfig("gpt-4o-mini").
+		WithProvider("openai").
+		WithProviderKwargs(map[string]any{"api_key": "test-key", "base_url": "http://llm.invalid/v1"})
+	opts := langextract.NewExtractOptions().
+		WithPromptDescription("Extract people").
+		WithModelConfig(config).
+		WithRetryCount(0)
+
+	result, err := langextract.Extract("Ada wrote the first program.", opts)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if len(result.Extractions) != 1 {
+		t.Errorf("Unexpected extractions %v", result.Extractions)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(hosts) == 0 || hosts[0] != "llm.invalid" {
+		t.Errorf("Expected the request to go through the proxy, got hosts %v", hosts)
+	}
+}
@@ -0,0 +1,168 @@
+package langextract_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/sehwan505/langextract-go/pkg/document"
+	"github.com/sehwan505/langextract-go/pkg/langextract"
+)
+
+// closingProvider records whether Close was called
+type closingProvider struct {
+	stubProvider
+	closed bool
+}
+
+func (p *closingProvider) Close() error {
+	p.closed = true
+	return nil
+}
+
+// TestExtractorReuse verifies that an Extractor serves repeated calls from one provider and cache
+func TestExtractorReuse(t *testing.T) {
+	provider := &stubProvider{
+		modelID:   "stub-model",
+		responses: []string{`{"extractions": [{"extraction_class": "person", "extraction_text": "Alice"}]}`},
+	}
+
+	config := langextract.DefaultConfig()
+	config.EnableCaching = true
+	extractor, err := langextract.NewExtractor(config, newTestOptions(provider))
+	if err != nil {
+		t.Fatalf("NewExtractor() error = %v", err)
+	}
+	defer extractor.Close()
+
+	for i := 0; i < 3; i++ {
+		result, err := extractor.Extract(context.Background(), document.NewDocument("Alice met Bob."))
+		if err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		if len(result.Document.Extractions) != 1 || result.Document.Extractions[0].ExtractionText != "Alice" {
+			t.Errorf("Unexpected extractions %v", result.Document.Extractions)
+		}
+	}
+
+	if provider.callCount() != 1 {
+		t.Errorf("Expected repeated documents to be served from the shared cache, got %d provider calls", provider.callCount())
+	}
+
+	// The additional context is part of the prompt, so it is not a cache hit
+	doc := document.NewDocument("Alice met Bob.")
+	doc.AdditionalContext = "Alice is a doctor."
+	if _, err := extractor.Extract(context.Background(), doc); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if provider.callCount() != 2 {
+		t.Errorf("Expected a document with additional context to reach the provider, got %d provider calls", provider.callCount())
+	}
+}
+
+// TestExtractorCachingDisabled verifies that responses are not cached unless
+// the configuration enables caching
+func TestExtractorCachingDisabled(t *testing.T) {
+	provider := &stubProvider{
+		modelID:   "stub-model",
+		responses: []string{`{"extractions": []}`},
+	}
+
+	extractor, err := langextract.NewExtractor(langextract.DefaultConfig(), newTestOptions(provider))
+	if err != nil {
+		t.Fatalf("NewExtractor() error = %v", err)
+	}
+	defer extractor.Close()
+
+	for i := 0; i < 2; i++ {
+		if _, err := extractor.Extract(context.Background(), document.NewDocument("Alice met Bob.")); err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+	}
+	if provider.callCount() != 2 {
+		t.Errorf("Expected every document to reach the provider, got %d provider calls", provider.callCount())
+	}
+}
+
+// TestExtractorConcurrentUse verifies that an Extractor can be used from many goroutines
+func TestExtractorConcurrentUse(t *testing.T) {
+	provider := &trackingProvider{stubProvider: stubProvider{modelID: "stub-model"}}
+
+	extractor, err := langextract.NewExtractor(langextract.DefaultConfig(), newTestOptions(provider))
+	if err != nil {
+		t.Fatalf("NewExtractor() error = %v", err)
+	}
+	defer extractor.Close()
+
+	const workers = 16
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			word := fmt.Sprintf("Worker%02d", i)
+			result, err := extractor.Extract(context.Background(), document.NewDocument(word+" finished the task."))
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if len(result.Document.Extractions) != 1 || result.Document.Extractions[0].ExtractionText != word {
+				errs[i] = fmt.Errorf("unexpected extractions %v", result.Document.Extractions)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Worker %d: %v", i, err)
+		}
+	}
+}
+
+// TestExtractorClose verifies extraction after Close and ownership of supplied providers
+func TestExtractorClose(t *testing.T) {
+	provider := &closingProvider{stubProvider: stubProvider{
+		modelID:   "stub-model",
+		responses: []string{`{"extractions": []}`},
+	}}
+
+	extractor, err := langextract.NewExtractor(langextract.DefaultConfig(), newTestOptions(provider))
+	if err != nil {
+		t.Fatalf("NewExtractor() error = %v", err)
+	}
+
+	if err := extractor.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := extractor.Close(); err != nil {
+		t.Errorf("Second Close() error = %v", err)
+	}
+	if provider.closed {
+		t.Error("Expected a supplied provider not to be closed")
+	}
+
+	_, err = extractor.Extract(context.Background(), document.NewDocument("Alice met Bob."))
+	if !errors.Is(err, langextract.ErrExtractorClosed) {
+		t.Errorf("Expected ErrExtractorClosed, got %v", err)
+	}
+}
+
+// TestNewExtractorValidation verifies that invalid configuration is rejected up front
+func TestNewExtractorValidation(t *testing.T) {
+	provider := &stubProvider{modelID: "stub-model"}
+
+	config := langextract.DefaultConfig()
+	config.MaxConcurrency = 0
+	if _, err := langextract.NewExtractor(config, newTestOptions(provider)); err == nil {
+		t.Error("Expected error for invalid configuration")
+	}
+
+	if _, err := langextract.NewExtractor(langextract.DefaultConfig(), newTestOptions(provider).WithPromptDescription("")); err == nil {
+		t.Error("Expected error for invalid options")
+	}
+}
@@ -0,0 +1,134 @@
+package langextract_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sehwan505/langextract-go/pkg/langextract"
+)
+
+// TestExtractMultiplePasses verifies that later passes only add non-overlapping extractions
+func TestExtractMultiplePasses(t *testing.T) {
+	provider := &stubProvider{
+		modelID: "stub-model",
+		responses: []string{
+			`{"extractions": [{"extraction_class": "person", "extraction_text": "Alice"}]}`,
+			`{"extractions": [
+				{"extraction_class": "person", "extraction_text": "Alice Smith"},
+				{"extraction_class": "person", "extraction_text": "Bob"}
+			]}`,
+		},
+	}
+
+	text := "Alice Smith met Bob in Paris."
+	result, err := langextract.ExtractWithMetadata(text, newTestOptions(provider).WithExtractionPasses(2))
+	if err != nil {
+		t.Fatalf("ExtractWithMetadata() error = %v", err)
+	}
+
+	// The second pass must not be served from the first pass's cached response
+	if provider.callCount() != 2 {
+		t.Errorf("Expected 2 provider calls, got %d", provider.callCount())
+	}
+	if result.Metadata.PassesCompleted != 2 {
+		t.Errorf("Expected 2 passes completed, got %d", result.Metadata.PassesCompleted)
+	}
+
+	var texts []string
+	for _, ext := range result.Document.Extractions {
+		texts = append(texts, ext.ExtractionText)
+	}
+	if strings.Join(texts, ",") != "Alice,Bob" {
+		t.Errorf("Expected extractions [Alice Bob], got %v", texts)
+	}
+}
+
+// TestExtractSinglePass verifies that the default makes a single provider call
+func TestExtractSinglePass(t *testing.T) {
+	provider := &stubProvider{
+		modelID:   "stub-model",
+		responses: []string{`{"extractions": [{"extraction_class": "person", "extraction_text": "Alice"}]}`},
+	}
+
+	result, err := langextract.ExtractWithMetadata("Alice Smith met Bob.", newTestOptions(provider))
+	if err != nil {
+		t.Fatalf("ExtractWithMetadata() error = %v", err)
+	}
+
+	if provider.callCount() != 1 || result.Metadata.PassesCompleted != 1 {
+		t.Errorf("Expected 1 call and 1 pass, got %d calls and %d passes", provider.callCount(), result.Metadata.PassesCompleted)
+	}
+}
+
+func parallelTestOptions(provider *trackingProvider, parallel bool) *langextract.ExtractOptions {
+	chunkingOpts := langextract.DefaultChunkingOptions()
+	chunkingOpts.MaxCharBuffer = 30
+	chunkingOpts.MinChunkSize = 5
+	chunkingOpts.OverlapRatio = 0
+
+	return newTestOptions(provider).
+		WithChunking(langextract.ChunkingSimple, chunkingOpts).
+		WithParallelProcessing(parallel)
+}
+
+func parallelTestText() (string, []string) {
+	words := []string{"Alpha", "Bravo", "Charlie", "Delta", "Echo", "Foxtrot", "Golf", "Hotel"}
+	var sentences []string
+	for _, word := range words {
+		sentences = append(sentences, fmt.Sprintf("%s opened the meeting.", word))
+	}
+	return strings.Join(sentences, " "), words
+}
+
+// TestExtractParallelProcessing verifies that chunks are processed concurrently and merged in order
+func TestExtractParallelProcessing(t *testing.T) {
+	text, words := parallelTestText()
+
+	for _, parallel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("parallel=%v", parallel), func(t *testing.T) {
+			provider := &trackingProvider{stubProvider: stubProvider{modelID: "stub-model"}, delay: 20 * time.Millisecond}
+
+			result, err := langextract.ExtractWithMetadata(text, parallelTestOptions(provider, parallel))
+			if err != nil {
+				t.Fatalf("ExtractWithMetadata() error = %v", err)
+			}
+
+			if result.Metadata.ChunksProcessed != len(words) {
+				t.Fatalf("Expected %d chunks, got %d", len(words), result.Metadata.ChunksProcessed)
+			}
+
+			extractions := result.Document.Extractions
+			if len(extractions) != len(words) {
+				t.Fatalf("Expected %d extractions, got %d", len(words), len(extractions))
+			}
+			for i, ext := range extractions {
+				if ext.ExtractionText != words[i] {
+					t.Errorf("Extraction %d: expected %q, got %q", i, words[i], ext.ExtractionText)
+				}
+				if ext.CharInterval == nil || text[ext.CharInterval.StartPos:ext.CharInterval.EndPos] != words[i] {
+					t.Errorf("Extraction %d is not grounded at %q", i, words[i])
+				}
+			}
+
+			if parallel && provider.maxInFlight < 2 {
+				t.Errorf("Expected chunks to be processed concurrently, max in flight was %d", provider.maxInFlight)
+			}
+			if !parallel && provider.maxInFlight != 1 {
+				t.Errorf("Expected chunks to be processed sequentially, max in flight was %d", provider.maxInFlight)
+			}
+		})
+	}
+}
+
+// TestExtractParallelProcessingFailure verifies that a failing chunk fails the extraction
+func TestExtractParallelProcessingFailure(t *testing.T) {
+	text, _ := parallelTestText()
+	provider := &trackingProvider{stubProvider: stubProvider{modelID: "stub-model"}, failOn: "Echo"}
+
+	_, err := langextract.Extract(text, parallelTestOptions(provider, true))
+	if err == nil || !strings.Contains(err.Error(), "cannot process Echo") {
+		t.Errorf("Expected chunk failure to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+package langextract_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sehwan505/langextract-go/pkg/extraction"
+	"github.com/sehwan505/langextract-go/pkg/langextract"
+	"github.com/sehwan505/langextract-go/pkg/types"
+)
+
+type Medication struct {
+	Name      string   `extract:",text"`
+	Dosage    string   `extract:"dosage,required" description:"Amount per dose"`
+	Route     string   `extract:"route,enum=oral|iv|topical"`
+	Frequency int      `extract:"times_per_day,min=1,max=12"`
+	Generic   *bool    `json:"is_generic"`
+	Tags      []string `extract:"tags"`
+	Internal  string   `extract:"-"`
+}
+
+type labeledMedication struct {
+	Dosage string `extract:"dosage"`
+}
+
+func (labeledMedication) ExtractionClass() string { return "drug" }
+
+// TestSchemaFor verifies schema derivation from struct tags
+func TestSchemaFor(t *testing.T) {
+	schema, err := langextract.SchemaFor[Medication]()
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	class := schema.GetClass("medication")
+	if class == nil {
+		t.Fatalf("Expected class medication, got %v", schema.GetClasses())
+	}
+
+	fields := make(map[string]*extraction.FieldDefinition)
+	var names []string
+	for _, field := range class.Fields {
+		fields[field.Name] = field
+		names = append(names, field.Name)
+	}
+	if strings.Join(names, ",") != "dosage,route,times_per_day,is_generic,tags" {
+		t.Fatalf("Unexpected fields %v", names)
+	}
+
+	if !fields["dosage"].Required || fields["dosage"].Description != "Amount per dose" {
+		t.Errorf("Expected required dosage with description, got %+v", fields["dosage"])
+	}
+	if strings.Join(fields["route"].Enum, ",") != "oral,iv,topical" {
+		t.Errorf("Unexpected route enum %v", fields["route"].Enum)
+	}
+	frequency := fields["times_per_day"]
+	if frequency.Type != "number" || frequency.Minimum == nil || *frequency.Minimum != 1 || frequency.Maximum == nil || *frequency.Maximum != 12 {
+		t.Errorf("Unexpected times_per_day definition %+v", frequency)
+	}
+	if fields["is_generic"].Type != "boolean" || fields["tags"].Type != "array" {
+		t.Errorf("Unexpected field types %q and %q", fields["is_generic"].Type, fields["tags"].Type)
+	}
+
+	labeled, err := langextract.SchemaFor[labeledMedication]()
+	if err != nil || labeled.GetClass("drug") == nil {
+		t.Errorf("Expected class from ExtractionClass(), got %v (%v)", labeled, err)
+	}
+}
+
+// TestSchemaForInvalidTypes verifies rejected struct definitions
+func TestSchemaForInvalidTypes(t *testing.T) {
+	type unsupportedField struct {
+		Lookup map[string]string
+	}
+	type enumOnNumber struct {
+		Count int `extract:"count,enum=1|2"`
+	}
+	type unknownOption struct {
+		Name string `extract:"name,optional"`
+	}
+
+	if _, err := langextract.SchemaFor[string](); err == nil {
+		t.Error("Expected error for non-struct type")
+	}
+	if _, err := langextract.SchemaFor[unsupportedField](); err == nil || !strings.Contains(err.Error(), "unsupported field type") {
+		t.Errorf("Expected unsupported field type error, got %v", err)
+	}
+	if _, err := langextract.SchemaFor[enumOnNumber](); err == nil || !strings.Contains(err.Error(), "enum") {
+		t.Errorf("Expected enum error, got %v", err)
+	}
+	if _, err := langextract.SchemaFor[unknownOption](); err == nil || !strings.Contains(err.Error(), "optional") {
+		t.Errorf("Expected unknown option error, got %v", err)
+	}
+}
+
+func groundedExtraction(class, text string, start int, attributes map[string]interface{}) *extraction.Extraction {
+	ext := extraction.NewExtraction(class, text)
+	ext.CharInterval = &types.CharInterval{StartPos: start, EndPos: start + len(text)}
+	for key, value := range attributes {
+		ext.AddAttribute(key, value)
+	}
+	return ext
+}
+
+// TestDecodeExtractions verifies type coercion and per-field errors
+func TestDecodeExtractions(t *testing.T) {
+	ungrounded := extraction.NewExtraction("medication", "aspirin")
+	ungrounded.AddAttribute("dosage", "1 tablet")
+
+	extractions := []*extraction.Extraction{
+		groundedExtraction("medication", "ibuprofen", 0, map[string]interface{}{
+			"dosage":        "200 mg",
+			"route":         "oral",
+			"times_per_day": "3",
+			"is_generic":    "true",
+			"tags":          "otc",
+		}),
+		groundedExtraction("person", "Alice", 20, nil),
+		ungrounded,
+		groundedExtraction("medication", "morphine", 40, map[string]interface{}{
+			"route":         "inhaled",
+			"times_per_day": 2.5,
+		}),
+		groundedExtraction("medication", "heparin", 60, map[string]interface{}{
+			"dosage": "5000 units",
+			"tags":   []interface{}{"injectable", "anticoagulant"},
+		}),
+	}
+
+	results, err := langextract.DecodeExtractions[Medication](extractions)
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 decoded extractions, got %d", len(results))
+	}
+
+	first := results[0]
+	if first.Value.Name != "ibuprofen" || first.Value.Dosage != "200 mg" || first.Value.Frequency != 3 {
+		t.Errorf("Unexpected value %+v", first.Value)
+	}
+	if first.Value.Generic == nil || !*first.Value.Generic {
+		t.Errorf("Expected is_generic to decode from string, got %v", first.Value.Generic)
+	}
+	if strings.Join(first.Value.Tags, ",") != "otc" {
+		t.Errorf("Expected single tag to decode into a slice, got %v", first.Value.Tags)
+	}
+	if first.CharInterval == nil || first.CharInterval.StartPos != 0 || first.Extraction != extractions[0] {
+		t.Errorf("Expected the char interval and extraction to be kept, got %v", first.CharInterval)
+	}
+	if strings.Join(results[1].Value.Tags, ",") != "injectable,anticoagulant" || results[1].Value.Generic != nil {
+		t.Errorf("Unexpected value %+v", results[1].Value)
+	}
+
+	var decodeErr *langextract.DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("Expected *DecodeError, got %v", err)
+	}
+
+	got := make(map[string]*langextract.FieldError)
+	for _, fieldErr := range decodeErr.Errors {
+		if fieldErr.Index != 3 || fieldErr.Class != "medication" {
+			t.Errorf("Unexpected field error location %+v", fieldErr)
+		}
+		got[fieldErr.Field] = fieldErr
+	}
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 field errors, got %v", decodeErr.Errors)
+	}
+	if e := got["Dosage"]; e == nil || !strings.Contains(e.Error(), "required") {
+		t.Errorf("Expected missing dosage error, got %v", e)
+	}
+	if e := got["Route"]; e == nil || e.Value != "inhaled" || !strings.Contains(e.Error(), "not in allowed values") {
+		t.Errorf("Expected route enum error, got %v", e)
+	}
+	if e := got["Frequency"]; e == nil || e.Attribute != "times_per_day" || !strings.Contains(e.Error(), "does not fit in int") {
+		t.Errorf("Expected frequency coercion error, got %v", e)
+	}
+
+	var fieldErr *langextract.FieldError
+	if !errors.As(err, &fieldErr) || fieldErr != decodeErr.Errors[0] {
+		t.Errorf("Expected errors.As to find the first field error, got %v", fieldErr)
+	}
+}
+
+type Prescriber struct {
+	Doctor string `extract:"doctor"`
+}
+
+type prescription struct {
+	Name string `extract:",text"`
+	*Prescriber
+}
+
+type hiddenPrescriber struct {
+	*prescriber
+}
+
+type prescriber struct {
+	Doctor string `extract:"doctor"`
+}
+
+type Chain struct {
+	Label string `extract:"label"`
+	*Chain
+}
+
+// TestDecodeEmbeddedPointer verifies fields of embedded struct pointers
+func TestDecodeEmbeddedPointer(t *testing.T) {
+	schema, err := langextract.SchemaFor[prescription]()
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+	if class := schema.GetClass("prescription"); class == nil || len(class.Fields) != 1 || class.Fields[0].Name != "doctor" {
+		t.Fatalf("Expected the embedded field in the schema, got %v", schema.GetClasses())
+	}
+
+	results, err := langextract.DecodeExtractions[prescription]([]*extraction.Extraction{
+		groundedExtraction("prescription", "aspirin", 0, map[string]interface{}{"doctor": "Dr. Lee"}),
+		groundedExtraction("prescription", "ibuprofen", 10, nil),
+	})
+	if err != nil || len(results) != 2 {
+		t.Fatalf("DecodeExtractions() = %v, %v", results, err)
+	}
+	if results[0].Value.Prescriber == nil || results[0].Value.Doctor != "Dr. Lee" {
+		t.Errorf("Expected the embedded pointer to be allocated, got %+v", results[0].Value)
+	}
+	if results[1].Value.Prescriber != nil {
+		t.Errorf("Expected a nil embedded pointer without attributes, got %+v", results[1].Value.Prescriber)
+	}
+
+	if _, err := langextract.SchemaFor[hiddenPrescriber](); err == nil || !strings.Contains(err.Error(), "unexported") {
+		t.Errorf("Expected an error for an unexported embedded pointer, got %v", err)
+	}
+	if _, err := langextract.SchemaFor[Chain](); err == nil || !strings.Contains(err.Error(), "recursive") {
+		t.Errorf("Expected an error for recursive embedding, got %v", err)
+	}
+}
+
+type measurement struct {
+	Values []any  `extract:"values"`
+	Counts []int  `extract:"counts"`
+	Total  int64  `extract:"total"`
+	Unit   uint32 `extract:"unit"`
+}
+
+// TestDecodeNullsAndLargeNumbers verifies JSON nulls and out-of-range numbers
+func TestDecodeNullsAndLargeNumbers(t *testing.T) {
+	results, err := langextract.DecodeExtractions[measurement]([]*extraction.Extraction{
+		groundedExtraction("measurement", "3 mg", 0, map[string]interface{}{
+			"values": []interface{}{"a", nil},
+			"counts": []interface{}{1.0, nil},
+		}),
+		groundedExtraction("measurement", "lots", 10, map[string]interface{}{
+			"total": 1e20,
+			"unit":  -1e20,
+		}),
+	})
+
+	if len(results) != 1 {
+		t.Fatalf("Expected the nulls to decode, got %v, %v", results, err)
+	}
+	value := results[0].Value
+	if len(value.Values) != 2 || value.Values[0] != "a" || value.Values[1] != nil {
+		t.Errorf("Expected a nil element, got %v", value.Values)
+	}
+	if len(value.Counts) != 2 || value.Counts[0] != 1 || value.Counts[1] != 0 {
+		t.Errorf("Expected a zero element, got %v", value.Counts)
+	}
+
+	var decodeErr *langextract.DecodeError
+	if !errors.As(err, &decodeErr) || len(decodeErr.Errors) != 2 {
+		t.Fatalf("Expected errors for the out-of-range numbers, got %v", err)
+	}
+	for _, fieldErr := range decodeErr.Errors {
+		if !strings.Contains(fieldErr.Error(), "does not fit") {
+			t.Errorf("Expected an overflow error, got %v", fieldErr)
+		}
+	}
+}
+
+// TestExtractInto verifies typed extraction end to end
+func TestExtractInto(t *testing.T) {
+	provider := &stubProvider{
+		modelID: "stub-model",
+		responses: []string{`{"extractions": [
+			{"extraction_class": "medication", "extraction_text": "ibuprofen", "dosage": "400 mg", "times_per_day": 2},
+			{"extraction_class": "medication", "extraction_text": "paracetamol", "attributes": {"dosage": "1 g", "route": "oral"}},
+			{"extraction_class": "medication", "extraction_text": "warfarin", "times_per_day": 20}
+		]}`},
+	}
+
+	text := "Take ibuprofen 400 mg twice daily and paracetamol 1 g orally; stop warfarin."
+	results, err := langextract.ExtractInto[Medication](text, newTestOptions(provider))
+
+	var decodeErr *langextract.DecodeError
+	if !errors.As(err, &decodeErr) || len(decodeErr.Errors) != 2 {
+		t.Fatalf("Expected 2 field errors for warfarin, got %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	for _, result := range results {
+		if result.CharInterval == nil || text[result.CharInterval.StartPos:result.CharInterval.EndPos] != result.Value.Name {
+			t.Errorf("Expected %q to be grounded in the text, got %v", result.Value.Name, result.CharInterval)
+		}
+	}
+	if results[0].Value.Dosage != "400 mg" || results[0].Value.Frequency != 2 {
+		t.Errorf("Unexpected value %+v", results[0].Value)
+	}
+	if results[1].Value.Dosage != "1 g" || results[1].Value.Route != "oral" {
+		t.Errorf("Expected nested attributes to decode, got %+v", results[1].Value)
+	}
+
+	prompt := provider.prompts[0]
+	for _, want := range []string{"Expected extraction classes: medication", "- dosage (string, required): Amount per dose", "- route (string, one of: oral, iv, topical)"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected prompt to contain %q, got %q", want, prompt)
+		}
+	}
+}
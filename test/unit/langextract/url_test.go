@@ -0,0 +1,186 @@
+package langextract_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sehwan505/langextract-go/pkg/document"
+	"github.com/sehwan505/langextract-go/pkg/langextract"
+)
+
+const testArticle = `<!DOCTYPE html>
+<html><head><title>News</title><script>track();</script></head>
+<body><h1>Company news</h1><p>John&nbsp;Doe joined <b>Google</b> today.</p></body></html>`
+
+func newContentServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, testArticle)
+	})
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "Plain <b>text</b> stays as-is.")
+	})
+	mux.HandleFunc("/image", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte{0x89, 'P', 'N', 'G'})
+	})
+	mux.HandleFunc("/latin1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		fmt.Fprint(w, "<p>text</p>")
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, strings.Repeat("a", 2048))
+	})
+	mux.HandleFunc("/streamed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		for i := 0; i < 4; i++ {
+			fmt.Fprint(w, strings.Repeat("a", 512))
+			w.(http.Flusher).Flush()
+		}
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestURLFetcherFetch verifies content handling of fetched documents
+func TestURLFetcherFetch(t *testing.T) {
+	server := newContentServer(t)
+	fetcher := langextract.NewURLFetcher().WithClient(server.Client())
+
+	doc, err := fetcher.Fetch(context.Background(), server.URL+"/article")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if doc.Text != "Company news\n\nJohn Doe joined Google today." {
+		t.Errorf("Unexpected text %q", doc.Text)
+	}
+	if url, _ := doc.GetMetadata(document.MetadataSourceURL); url != server.URL+"/article" {
+		t.Errorf("Expected source URL metadata, got %q", url)
+	}
+	if contentType, _ := doc.GetMetadata(document.MetadataContentType); contentType != "text/html" {
+		t.Errorf("Expected content type text/html, got %q", contentType)
+	}
+	if doc.SourceMap == nil || doc.SourceMap.Source != testArticle {
+		t.Error("Expected source map with the original markup")
+	}
+
+	doc, err = fetcher.Fetch(context.Background(), server.URL+"/plain")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if doc.Text != "Plain <b>text</b> stays as-is." || doc.SourceMap != nil {
+		t.Errorf("Expected plain text to be used as-is, got %q", doc.Text)
+	}
+}
+
+// TestURLFetcherErrors verifies rejected downloads
+func TestURLFetcherErrors(t *testing.T) {
+	server := newContentServer(t)
+
+	tests := []struct {
+		name    string
+		path    string
+		fetcher *langextract.URLFetcher
+		want    string
+	}{
+		{"not found", "/missing", langextract.NewURLFetcher(), "404"},
+		{"unsupported content type", "/image", langextract.NewURLFetcher(), "unsupported content type"},
+		{"unsupported charset", "/latin1", langextract.NewURLFetcher(), "unsupported charset"},
+		{"content length over limit", "/large", langextract.NewURLFetcher().WithMaxBytes(1024), "exceeds limit"},
+		{"streamed body over limit", "/streamed", langextract.NewURLFetcher().WithMaxBytes(1024), "exceeds limit"},
+		{"timeout", "/slow", langextract.NewURLFetcher().WithTimeout(50 * time.Millisecond), "deadline exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fetcher.WithClient(server.Client())
+
+			_, err := tt.fetcher.Fetch(context.Background(), server.URL+tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Fetch() error = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestExtractURLInput verifies extraction from a URL with grounding in the converted text
+func TestExtractURLInput(t *testing.T) {
+	server := newContentServer(t)
+	provider := &stubProvider{
+		modelID:   "stub-model",
+		responses: []string{`{"extractions": [{"extraction_class": "organization", "extraction_text": "Google"}]}`},
+	}
+
+	opts := newTestOptions(provider).WithURLFetcher(langextract.NewURLFetcher().WithClient(server.Client()))
+	result, err := langextract.Extract(server.URL+"/article", opts)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	if !strings.Contains(provider.prompts[0], "John Doe joined Google today.") || strings.Contains(provider.prompts[0], "<b>") {
+		t.Errorf("Expected the provider to receive converted text, got prompt %q", provider.prompts[0])
+	}
+	if url, _ := result.GetMetadata(document.MetadataSourceURL); url != server.URL+"/article" {
+		t.Errorf("Expected source URL metadata on the result, got %q", url)
+	}
+
+	ext := result.Extractions[0]
+	if ext.CharInterval == nil || result.Text[ext.CharInterval.StartPos:ext.CharInterval.EndPos] != "Google" {
+		t.Fatalf("Expected extraction grounded in the converted text, got %v", ext.CharInterval)
+	}
+
+	interval, ok := result.SourceMap.SourceInterval(*ext.CharInterval)
+	if !ok || testArticle[interval.StartPos:interval.EndPos] != "Google" {
+		t.Errorf("Expected source map to point at the markup, got %v", interval)
+	}
+
+	_, err = langextract.Extract(server.URL+"/missing", opts)
+	if err == nil || !strings.Contains(err.Error(), "failed to fetch URL input") {
+		t.Errorf("Expected URL fetch error, got %v", err)
+	}
+}
+
+// TestExtractDocumentsURLFailure verifies that a failed download only fails
+// its own document of a batch
+func TestExtractDocumentsURLFailure(t *testing.T) {
+	server := newContentServer(t)
+	provider := &stubProvider{
+		modelID:   "stub-model",
+		responses: []string{`{"extractions": [{"extraction_class": "organization", "extraction_text": "Google"}]}`},
+	}
+
+	opts := newTestOptions(provider).WithURLFetcher(langextract.NewURLFetcher().WithClient(server.Client()))
+	inputs := []string{server.URL + "/article", server.URL + "/missing", "Google is a company."}
+	batch, err := langextract.ExtractDocuments(inputs, opts, newBatchConfig(2))
+	if err != nil {
+		t.Fatalf("ExtractDocuments() error = %v", err)
+	}
+
+	if batch.Succeeded != 2 || batch.Failed != 1 {
+		t.Fatalf("Expected 2 succeeded and 1 failed, got %d and %d", batch.Succeeded, batch.Failed)
+	}
+	if err := batch.Results[1].Error; err == nil || !strings.Contains(err.Error(), "failed to fetch URL input") {
+		t.Errorf("Expected a fetch error for the missing URL, got %v", err)
+	}
+	if url, _ := batch.Results[0].Document.GetMetadata(document.MetadataSourceURL); url != server.URL+"/article" {
+		t.Errorf("Expected source URL metadata on the fetched document, got %q", url)
+	}
+}
@@ -198,6 +198,43 @@ func TestFewShotPromptBuilder(t *testing.T) {
 		}
 	})
 
+	t.Run("BuildMessages", func(t *testing.T) {
+		options := prompt.DefaultPromptOptions().WithCustomTemplate(&prompt.PromptTemplate{
+			SystemMessage: "You extract people.",
+			Description:   "Extract person names",
+		})
+		builder := prompt.NewFewShotPromptBuilder(options)
+
+		task := &prompt.ExtractionTask{Description: "Extract person names", Classes: []string{"person"}}
+		examples := []*extraction.ExampleData{
+			extraction.NewExampleDataWithExtractions("Alice Johnson works at Google.", []*extraction.Extraction{
+				extraction.NewExtraction("person", "Alice Johnson"),
+			}),
+		}
+
+		messages, err := builder.BuildMessages(context.Background(), task, "John Smith visited Paris.", examples)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		roles := make([]string, len(messages))
+		for i, message := range messages {
+			roles[i] = message.Role
+		}
+		if strings.Join(roles, ",") != "system,user,assistant,user" {
+			t.Fatalf("Expected system, example and text turns, got %v", roles)
+		}
+		if !strings.HasPrefix(messages[0].Content, "You extract people.") {
+			t.Errorf("Expected the template's system message, got %q", messages[0].Content)
+		}
+		if !strings.Contains(messages[2].Content, "Alice Johnson") {
+			t.Errorf("Expected the example's extractions in the assistant turn, got %q", messages[2].Content)
+		}
+		if !strings.Contains(messages[3].Content, "John Smith visited Paris.") {
+			t.Errorf("Expected the text in the last turn, got %q", messages[3].Content)
+		}
+	})
+
 	t.Run("ErrorHandling", func(t *testing.T) {
 		builder := prompt.NewFewShotPromptBuilder(prompt.DefaultPromptOptions())
 
@@ -0,0 +1,227 @@
+package providers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// fakeMessagesAPI is an httptest fake of the Anthropic Messages endpoint
+type fakeMessagesAPI struct {
+	mu       sync.Mutex
+	requests []map[string]any
+	headers  []http.Header
+	status   int
+	response string
+}
+
+func (f *fakeMessagesAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/v1/messages" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, body)
+	f.headers = append(f.headers, r.Header.Clone())
+	status, response := f.status, f.response
+	f.mu.Unlock()
+
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write([]byte(response))
+}
+
+func newAnthropicTestProvider(t *testing.T, api *fakeMessagesAPI, kwargs map[string]any) *providers.AnthropicProvider {
+	t.Helper()
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("ANTHROPIC_BASE_URL", "")
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	allKwargs := map[string]any{"api_key": "test-key", "base_url": server.URL + "/v1"}
+	for key, value := range kwargs {
+		allKwargs[key] = value
+	}
+
+	config := providers.NewModelConfig("claude-sonnet-4-5").
+		WithProvider("anthropic").
+		WithTemperature(0.2).
+		WithMaxTokens(512).
+		WithProviderKwargs(allKwargs)
+
+	model, err := providers.NewAnthropicProvider(config)
+	if err != nil {
+		t.Fatalf("NewAnthropicProvider() error = %v", err)
+	}
+	return model.(*providers.AnthropicProvider)
+}
+
+func TestAnthropicProviderInfer(t *testing.T) {
+	api := &fakeMessagesAPI{response: `{
+		"id": "msg_01", "type": "message", "role": "assistant", "model": "claude-sonnet-4-5",
+		"content": [{"type": "text", "text": "Hello, "}, {"type": "text", "text": "world"}],
+		"stop_reason": "end_turn",
+		"usage": {"input_tokens": 12, "output_tokens": 5}
+	}`}
+	provider := newAnthropicTestProvider(t, api, map[string]any{
+		"system":         "You extract entities.",
+		"stop_sequences": []any{"END"},
+	})
+
+	results, err := provider.Infer(context.Background(), []string{"first", "second"}, nil)
+	if err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	if len(results) != 2 || results[0][0].Output != "Hello, world" {
+		t.Fatalf("Unexpected results %v", results)
+	}
+
+	request, header := api.requests[0], api.headers[0]
+	if header.Get("x-api-key") != "test-key" || header.Get("anthropic-version") == "" {
+		t.Errorf("Missing authentication headers: %v", header)
+	}
+	if request["model"] != "claude-sonnet-4-5" || request["max_tokens"] != 512.0 || request["temperature"] != 0.2 {
+		t.Errorf("Unexpected model parameters %v", request)
+	}
+	if request["system"] != "You extract entities." {
+		t.Errorf("Expected system prompt, got %v", request["system"])
+	}
+	if stops, _ := request["stop_sequences"].([]any); len(stops) != 1 || stops[0] != "END" {
+		t.Errorf("Expected stop sequences, got %v", request["stop_sequences"])
+	}
+	// Prompts are sent concurrently, so requests may arrive in any order
+	contents := map[any]bool{}
+	for _, request := range api.requests[:2] {
+		messages, _ := request["messages"].([]any)
+		if len(messages) != 1 {
+			t.Fatalf("Unexpected messages %v", request["messages"])
+		}
+		contents[messages[0].(map[string]any)["content"]] = true
+	}
+	if !contents["first"] || !contents["second"] {
+		t.Errorf("Expected one request per prompt, got %v", contents)
+	}
+	if _, ok := request["tools"]; ok {
+		t.Error("Expected no tools without a schema")
+	}
+
+	usage := provider.Usage()
+	if usage.InputTokens != 24 || usage.OutputTokens != 10 || usage.TotalTokens() != 34 {
+		t.Errorf("Unexpected usage %+v", usage)
+	}
+
+	// Infer options override the configured system prompt for one call
+	if _, err := provider.Infer(context.Background(), []string{"third"}, map[string]any{"system": "Be brief."}); err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	if api.requests[2]["system"] != "Be brief." {
+		t.Errorf("Expected system prompt override, got %v", api.requests[2]["system"])
+	}
+}
+
+func TestAnthropicProviderToolUse(t *testing.T) {
+	api := &fakeMessagesAPI{response: `{
+		"id": "msg_02", "type": "message", "role": "assistant",
+		"content": [{"type": "tool_use", "id": "toolu_01", "name": "record_extractions",
+			"input": {"extractions": [{"extraction_class": "person", "extraction_text": "Alice"}]}}],
+		"stop_reason": "tool_use",
+		"usage": {"input_tokens": 40, "output_tokens": 20}
+	}`}
+	provider := newAnthropicTestProvider(t, api, nil)
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"extractions": map[string]any{"type": "array"},
+		},
+	}
+	provider.ApplySchema(schema)
+
+	results, err := provider.Infer(context.Background(), []string{"Alice met Bob."}, nil)
+	if err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+
+	parsed, err := provider.ParseOutput(results[0][0].Output)
+	if err != nil {
+		t.Fatalf("ParseOutput() error = %v", err)
+	}
+	extractions, _ := parsed.(map[string]any)["extractions"].([]any)
+	if len(extractions) != 1 {
+		t.Fatalf("Expected tool input as output, got %q", results[0][0].Output)
+	}
+
+	request := api.requests[0]
+	tools, _ := request["tools"].([]any)
+	if len(tools) != 1 {
+		t.Fatalf("Expected one tool, got %v", request["tools"])
+	}
+	tool := tools[0].(map[string]any)
+	if tool["name"] != "record_extractions" || tool["input_schema"].(map[string]any)["type"] != "object" {
+		t.Errorf("Unexpected tool %v", tool)
+	}
+	choice, _ := request["tool_choice"].(map[string]any)
+	if choice["type"] != "tool" || choice["name"] != "record_extractions" {
+		t.Errorf("Expected the extraction tool to be forced, got %v", request["tool_choice"])
+	}
+
+	// A text answer without the tool call is an error
+	api.response = `{"content": [{"type": "text", "text": "I cannot help."}], "stop_reason": "end_turn"}`
+	if _, err := provider.Infer(context.Background(), []string{"Alice met Bob."}, nil); err == nil || !strings.Contains(err.Error(), "did not call") {
+		t.Errorf("Expected missing tool call error, got %v", err)
+	}
+}
+
+func TestAnthropicProviderErrors(t *testing.T) {
+	api := &fakeMessagesAPI{
+		status:   http.StatusBadRequest,
+		response: `{"type": "error", "error": {"type": "invalid_request_error", "message": "max_tokens: field required"}}`,
+	}
+	provider := newAnthropicTestProvider(t, api, nil)
+
+	_, err := provider.Infer(context.Background(), []string{"prompt"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid_request_error: max_tokens: field required") {
+		t.Errorf("Expected API error message, got %v", err)
+	}
+
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	if _, err := providers.NewAnthropicProvider(providers.NewModelConfig("claude-sonnet-4-5")); err == nil {
+		t.Error("Expected error without an API key")
+	}
+}
+
+func TestAnthropicProviderRegistration(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+
+	registry := providers.NewProviderRegistry()
+	providers.RegisterDefaultProviders(registry)
+
+	if !registry.HasProvider("anthropic") {
+		t.Fatal("Expected anthropic to be a default provider")
+	}
+
+	model, err := registry.CreateModel(providers.NewModelConfig("claude-sonnet-4-5"))
+	if err != nil {
+		t.Fatalf("CreateModel() error = %v", err)
+	}
+	if _, ok := model.(*providers.AnthropicProvider); !ok || model.GetModelID() != "claude-sonnet-4-5" {
+		t.Errorf("Expected Anthropic provider for Claude alias, got %T", model)
+	}
+}
@@ -0,0 +1,130 @@
+package providers_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+func TestModelCatalogLookup(t *testing.T) {
+	tests := []struct {
+		modelID string
+		want    string
+	}{
+		{"gpt-4o", "gpt-4o"},
+		{"gpt-4o-mini", "gpt-4o-mini"},
+		{"gpt-4o-2024-08-06", "gpt-4o"},
+		{"gpt-4o-mini-2024-07-18", "gpt-4o-mini"},
+		{"llama3.1:8b", "llama3.1"},
+		{"claude-sonnet-4-5-20250929", "claude-sonnet-4-5"},
+		{"gpt-4ox", ""},
+		{"unknown-model", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.modelID, func(t *testing.T) {
+			model, ok := providers.LookupModel(tt.modelID)
+			if tt.want == "" {
+				if ok {
+					t.Errorf("Expected no catalog entry, got %s", model.ID)
+				}
+				return
+			}
+			if !ok || model.ID != tt.want {
+				t.Errorf("Expected catalog entry %s, got %+v", tt.want, model)
+			}
+		})
+	}
+}
+
+func TestModelCatalogLoad(t *testing.T) {
+	catalog := providers.NewModelCatalog()
+	err := catalog.Load([]byte(`
+version: 1
+updated: "2026-01-02"
+models:
+  - id: my-model
+    provider: openai
+    aliases: [mine]
+    context_window: 1000
+    max_output_tokens: 200
+    input_price: 1.5
+    output_price: 3
+`))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if catalog.Updated() != "2026-01-02" {
+		t.Errorf("Expected the catalog date, got %q", catalog.Updated())
+	}
+
+	model, ok := catalog.Lookup("mine")
+	if !ok || model.ID != "my-model" || model.Provider != "openai" {
+		t.Fatalf("Expected the aliased model, got %+v", model)
+	}
+	if got := catalog.Models("gemini"); len(got) != 0 {
+		t.Errorf("Expected no gemini models, got %d", len(got))
+	}
+
+	invalid := map[string]string{
+		"version":  "version: 2\nmodels: []",
+		"id":       "version: 1\nmodels:\n  - provider: openai",
+		"provider": "version: 1\nmodels:\n  - id: my-model",
+		"negative": "version: 1\nmodels:\n  - id: my-model\n    provider: openai\n    input_price: -1",
+	}
+	for name, data := range invalid {
+		if err := catalog.Load([]byte(data)); err == nil {
+			t.Errorf("Expected an error loading a catalog with an invalid %s", name)
+		}
+	}
+}
+
+func TestModelInfoCostAndBudget(t *testing.T) {
+	model := &providers.ModelInfo{ContextWindow: 1000, MaxOutputTokens: 200, InputPrice: 2, OutputPrice: 8}
+
+	cost := model.Cost(providers.Usage{PromptTokens: 1000000, CompletionTokens: 500000, TotalTokens: 1500000})
+	if math.Abs(cost-6) > 1e-9 {
+		t.Errorf("Expected a cost of $6, got %v", cost)
+	}
+	if cost := model.Cost(providers.Usage{TotalTokens: 500000}); math.Abs(cost-1) > 1e-9 {
+		t.Errorf("Expected a total-only usage to be billed at the input price, got %v", cost)
+	}
+
+	budgets := map[int]int{0: 800, 100: 900, 5000: 800}
+	for maxTokens, want := range budgets {
+		if got := model.InputTokenBudget(maxTokens); got != want {
+			t.Errorf("InputTokenBudget(%d) = %d, want %d", maxTokens, got, want)
+		}
+	}
+	if got := (&providers.ModelInfo{}).InputTokenBudget(100); got != 0 {
+		t.Errorf("Expected no budget for an unknown context window, got %d", got)
+	}
+}
+
+func TestCreateModelFromCatalog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "models.yaml")
+	data := "version: 1\nmodels:\n  - id: catalog-test-model\n    provider: fake\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := providers.LoadCatalogFile(path); err != nil {
+		t.Fatalf("LoadCatalogFile() error = %v", err)
+	}
+	if err := providers.LoadCatalogFile(filepath.Join(t.TempDir(), "missing.yaml")); err != nil {
+		t.Errorf("Expected a missing catalog file to be ignored, got %v", err)
+	}
+
+	registry := providers.NewProviderRegistry()
+	providers.RegisterDefaultProviders(registry)
+	registry.Register(providers.FakeProviderName, providers.NewFakeProvider)
+	model, err := registry.CreateModel(providers.NewModelConfig("catalog-test-model-v2"))
+	if err != nil {
+		t.Fatalf("CreateModel() error = %v", err)
+	}
+	if _, ok := model.(*providers.FakeLanguageModel); !ok {
+		t.Errorf("Expected the catalog provider to create the model, got %T", model)
+	}
+}
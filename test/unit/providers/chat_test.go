@@ -0,0 +1,171 @@
+package providers_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// chatTestConversation is a conversation with a system prompt and a
+// few-shot example
+var chatTestConversation = []providers.Message{
+	{Role: providers.RoleSystem, Content: "Extract people."},
+	{Role: providers.RoleUser, Content: "Alice is here."},
+	{Role: providers.RoleAssistant, Content: `{"extractions": [{"extraction_class": "person", "extraction_text": "Alice"}]}`},
+	{Role: providers.RoleUser, Content: "Bob is there."},
+}
+
+// chatTestTurns are the non-system messages of chatTestConversation as
+// decoded JSON
+var chatTestTurns = []any{
+	map[string]any{"role": "user", "content": "Alice is here."},
+	map[string]any{"role": "assistant", "content": `{"extractions": [{"extraction_class": "person", "extraction_text": "Alice"}]}`},
+	map[string]any{"role": "user", "content": "Bob is there."},
+}
+
+func TestInferMessagesOpenAI(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	api, baseURL := newChatServer(t, `{"extractions": []}`)
+
+	model, err := providers.NewOpenAIProvider(providers.NewModelConfig("gpt-4o-mini").
+		WithProviderKwargs(map[string]any{"api_key": "test-key", "base_url": baseURL}))
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() error = %v", err)
+	}
+
+	results, err := providers.InferMessages(context.Background(), model, [][]providers.Message{chatTestConversation}, nil)
+	if err != nil {
+		t.Fatalf("InferMessages() error = %v", err)
+	}
+	if results[0].Outputs[0].Output != `{"extractions": []}` {
+		t.Errorf("Unexpected output %q", results[0].Outputs[0].Output)
+	}
+
+	want := append([]any{map[string]any{"role": "system", "content": "Extract people."}}, chatTestTurns...)
+	if got := api.requests[0]["messages"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the messages with their roles, got %v", got)
+	}
+}
+
+func TestInferMessagesGemini(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv("GOOGLE_API_KEY", "")
+
+	api := &fakeGeminiAPI{reply: `{"extractions": []}`}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	model, err := providers.NewGeminiProvider(providers.NewModelConfig("gemini-2.5-flash").WithProviderKwargs(map[string]any{
+		"api_key":  "test-key",
+		"base_url": server.URL + "/v1beta",
+	}))
+	if err != nil {
+		t.Fatalf("NewGeminiProvider() error = %v", err)
+	}
+
+	if _, err := providers.InferMessages(context.Background(), model, [][]providers.Message{chatTestConversation}, nil); err != nil {
+		t.Fatalf("InferMessages() error = %v", err)
+	}
+
+	request := api.requests[0]
+	system := map[string]any{"parts": []any{map[string]any{"text": "Extract people."}}}
+	if !reflect.DeepEqual(request["systemInstruction"], system) {
+		t.Errorf("Expected the system message as systemInstruction, got %v", request["systemInstruction"])
+	}
+	contents, _ := request["contents"].([]any)
+	var roles []string
+	for _, content := range contents {
+		roles = append(roles, content.(map[string]any)["role"].(string))
+	}
+	if !reflect.DeepEqual(roles, []string{"user", "model", "user"}) {
+		t.Errorf("Expected user and model turns, got %v", roles)
+	}
+}
+
+func TestInferMessagesAnthropic(t *testing.T) {
+	api := &fakeMessagesAPI{response: `{
+		"id": "msg_1",
+		"type": "message",
+		"role": "assistant",
+		"content": [{"type": "text", "text": "{\"extractions\": []}"}],
+		"stop_reason": "end_turn",
+		"usage": {"input_tokens": 10, "output_tokens": 5}
+	}`}
+	model := newAnthropicTestProvider(t, api, map[string]any{"system": "Default system prompt."})
+
+	if _, err := model.InferMessages(context.Background(), [][]providers.Message{chatTestConversation}, nil); err != nil {
+		t.Fatalf("InferMessages() error = %v", err)
+	}
+	request := api.requests[0]
+	if request["system"] != "Extract people." {
+		t.Errorf("Expected the system message to replace the system kwarg, got %v", request["system"])
+	}
+	if !reflect.DeepEqual(request["messages"], chatTestTurns) {
+		t.Errorf("Expected the turns without the system message, got %v", request["messages"])
+	}
+}
+
+func TestInferMessagesOllama(t *testing.T) {
+	api := &fakeOllamaAPI{version: "0.5.7"}
+	model := newOllamaTestProvider(t, api, nil)
+
+	results, err := model.InferMessages(context.Background(), [][]providers.Message{chatTestConversation}, nil)
+	if err != nil {
+		t.Fatalf("InferMessages() error = %v", err)
+	}
+	if results[0].Outputs[0].Output != `{"extractions": []}` || results[0].Usage.TotalTokens != 42 {
+		t.Errorf("Unexpected result %+v", results[0])
+	}
+
+	request := api.requests[0]
+	want := append([]any{map[string]any{"role": "system", "content": "Extract people."}}, chatTestTurns...)
+	if request["endpoint"] != "/api/chat" || !reflect.DeepEqual(request["messages"], want) {
+		t.Errorf("Expected a chat request with the messages, got %v", request)
+	}
+
+	// Prompts still use the generate endpoint
+	if _, err := model.Infer(context.Background(), []string{"prompt"}, nil); err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	if api.requests[1]["endpoint"] != "/api/generate" || api.requests[1]["prompt"] != "prompt" {
+		t.Errorf("Expected a generate request for the prompt, got %v", api.requests[1])
+	}
+}
+
+func TestInferMessagesFallback(t *testing.T) {
+	model := providers.NewFakeLanguageModel("").WithDefault(providers.FakeResponse{Output: `{"extractions": []}`})
+
+	conversations := [][]providers.Message{chatTestConversation, {{Role: providers.RoleUser, Content: "Just a prompt"}}}
+	results, err := providers.InferMessages(context.Background(), model, conversations, nil)
+	if err != nil {
+		t.Fatalf("InferMessages() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected a result per conversation, got %d", len(results))
+	}
+
+	prompts := model.Prompts()
+	want := "Extract people.\n\nUser: Alice is here.\n\nAssistant: " + chatTestConversation[2].Content + "\n\nUser: Bob is there."
+	if len(prompts) != 2 || prompts[0] != want || prompts[1] != "Just a prompt" {
+		t.Errorf("Expected the flattened conversations as prompts, got %q", prompts)
+	}
+}
+
+func TestValidateMessages(t *testing.T) {
+	invalid := map[string][]providers.Message{
+		"empty":        {},
+		"unknown role": {{Role: "tool", Content: "x"}, {Role: providers.RoleUser, Content: "y"}},
+		"last turn":    {{Role: providers.RoleUser, Content: "x"}, {Role: providers.RoleAssistant, Content: "y"}},
+	}
+	for name, messages := range invalid {
+		if err := providers.ValidateMessages(messages); err == nil {
+			t.Errorf("Expected the %s conversation to be rejected", name)
+		}
+	}
+	if err := providers.ValidateMessages(chatTestConversation); err != nil {
+		t.Errorf("ValidateMessages() error = %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package providers_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+func TestFakeLanguageModelResponses(t *testing.T) {
+	errOverloaded := errors.New("overloaded")
+	model := providers.NewFakeLanguageModel("fake-model").
+		WithScript(providers.FakeResponse{Output: "first"}, providers.FakeResponse{Err: errOverloaded}).
+		WithOutput(`(?i)patient`, `{"extractions": []}`).
+		WithRule(`usage`, providers.FakeResponse{Output: "counted", Usage: providers.Usage{PromptTokens: 7, TotalTokens: 7}}).
+		WithDefault(providers.FakeResponse{Output: "default"})
+
+	prompts := []string{"anything", "anything", "The Patient was seen", "usage", "other"}
+	results, err := providers.InferDetailed(context.Background(), model, prompts, nil)
+
+	var batchErr *providers.BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 || !errors.Is(err, errOverloaded) {
+		t.Fatalf("Expected the injected error for one prompt, got %v", err)
+	}
+	if results[1] != nil {
+		t.Errorf("Expected no result for the failed prompt, got %+v", results[1])
+	}
+
+	expected := map[int]string{0: "first", 2: `{"extractions": []}`, 3: "counted", 4: "default"}
+	for i, output := range expected {
+		if got := results[i].Outputs[0].Output; got != output {
+			t.Errorf("Prompt %d: expected %q, got %q", i, output, got)
+		}
+	}
+	if usage := results[3].Usage; usage.TotalTokens != 7 {
+		t.Errorf("Expected the configured usage, got %+v", usage)
+	}
+	if usage := results[4].Usage; usage.TotalTokens == 0 || usage.PromptTokens == 0 {
+		t.Errorf("Expected estimated usage, got %+v", usage)
+	}
+	if got := model.Prompts(); len(got) != len(prompts) {
+		t.Errorf("Expected %d recorded prompts, got %v", len(prompts), got)
+	}
+}
+
+func TestFakeLanguageModelUnmatchedPrompt(t *testing.T) {
+	model := providers.NewFakeLanguageModel("").WithOutput(`^known$`, "ok")
+
+	_, err := model.Infer(context.Background(), []string{"unknown"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "no response") {
+		t.Errorf("Expected an error for an unmatched prompt, got %v", err)
+	}
+}
+
+func TestFakeLanguageModelLatency(t *testing.T) {
+	model := providers.NewFakeLanguageModel("slow").
+		WithLatency(time.Second).
+		WithDefault(providers.FakeResponse{Output: "late"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := model.Infer(ctx, []string{"prompt"}, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the latency to be cut short by the context, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected cancellation to stop waiting, waited %v", elapsed)
+	}
+}
+
+func TestFakeProviderFromRegistry(t *testing.T) {
+	config := providers.NewModelConfig("fake-model").
+		WithProvider(providers.FakeProviderName).
+		WithProviderKwargs(map[string]any{
+			"responses": []any{"scripted"},
+			"rules":     map[string]any{"name": `{"name": "Alice"}`},
+			"output":    "fallback",
+		})
+	registry := providers.NewProviderRegistry()
+	providers.RegisterDefaultProviders(registry)
+	if _, err := registry.CreateModel(config); err == nil {
+		t.Error("Expected the fake provider not to be registered by default")
+	}
+
+	registry.Register(providers.FakeProviderName, providers.NewFakeProvider)
+	model, err := registry.CreateModel(config)
+	if err != nil {
+		t.Fatalf("CreateModel() error = %v", err)
+	}
+
+	results, err := model.Infer(context.Background(), []string{"name?", "name?", "age?"}, nil)
+	if err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	for i, output := range []string{"scripted", `{"name": "Alice"}`, "fallback"} {
+		if got := results[i][0].Output; got != output {
+			t.Errorf("Prompt %d: expected %q, got %q", i, output, got)
+		}
+	}
+
+	parsed, err := model.ParseOutput("```json\n" + results[1][0].Output + "\n```")
+	if err != nil {
+		t.Fatalf("ParseOutput() error = %v", err)
+	}
+	if object, ok := parsed.(map[string]any); !ok || object["name"] != "Alice" {
+		t.Errorf("Expected parsed JSON, got %#v", parsed)
+	}
+}
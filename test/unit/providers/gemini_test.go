@@ -0,0 +1,206 @@
+package providers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sehwan505/langextract-go/pkg/extraction"
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// fakeGeminiAPI is an httptest fake of the Gemini generateContent endpoint
+type fakeGeminiAPI struct {
+	mu       sync.Mutex
+	reply    string
+	requests []map[string]any
+}
+
+func (f *fakeGeminiAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/v1beta/models/gemini-2.5-flash:generateContent" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, body)
+	f.mu.Unlock()
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"candidates": []any{map[string]any{
+			"index":   0,
+			"content": map[string]any{"parts": []any{map[string]any{"text": f.reply}}},
+		}},
+	})
+}
+
+func TestGeminiProviderResponseSchema(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv("GOOGLE_API_KEY", "")
+
+	api := &fakeGeminiAPI{reply: `{"extractions": []}`}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	model, err := providers.NewGeminiProvider(providers.NewModelConfig("gemini-2.5-flash").WithProviderKwargs(map[string]any{
+		"api_key":  "test-key",
+		"base_url": server.URL + "/v1beta",
+	}))
+	if err != nil {
+		t.Fatalf("NewGeminiProvider() error = %v", err)
+	}
+
+	schema := extraction.NewBasicExtractionSchema("people", "People in text")
+	schema.AddClass(&extraction.ClassDefinition{
+		Name: "person",
+		Fields: []*extraction.FieldDefinition{
+			{Name: "role", Type: "string", Description: "Job title"},
+			{Name: "age", Type: "number"},
+			{Name: "notes", Description: "Any value"},
+		},
+	})
+	schema.AddClass(&extraction.ClassDefinition{Name: "company"})
+	jsonSchema, err := schema.ToJSONSchema()
+	if err != nil {
+		t.Fatalf("ToJSONSchema() error = %v", err)
+	}
+	model.ApplySchema(jsonSchema)
+
+	if _, err := model.Infer(context.Background(), []string{"Alice is a doctor."}, nil); err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+
+	config, _ := api.requests[0]["generationConfig"].(map[string]any)
+	if config["responseMimeType"] != "application/json" {
+		t.Errorf("Expected JSON mime type, got %v", config["responseMimeType"])
+	}
+	responseSchema, _ := config["responseSchema"].(map[string]any)
+	if responseSchema["type"] != "OBJECT" {
+		t.Fatalf("Expected responseSchema, got %v", config["responseSchema"])
+	}
+	if _, ok := responseSchema["$schema"]; ok {
+		t.Error("Expected $schema to be dropped")
+	}
+	if _, ok := responseSchema["title"]; ok {
+		t.Error("Expected title to be dropped")
+	}
+
+	items := responseSchema["properties"].(map[string]any)["extractions"].(map[string]any)["items"].(map[string]any)
+	properties := items["properties"].(map[string]any)
+	class := properties["extraction_class"].(map[string]any)
+	if class["type"] != "STRING" || class["format"] != "enum" || !reflect.DeepEqual(class["enum"], []any{"person", "company"}) {
+		t.Errorf("Unexpected extraction_class schema %v", class)
+	}
+	if role := properties["role"].(map[string]any); role["type"] != "STRING" || role["description"] != "Job title" {
+		t.Errorf("Unexpected role schema %v", role)
+	}
+	if age := properties["age"].(map[string]any); age["type"] != "NUMBER" {
+		t.Errorf("Unexpected age schema %v", age)
+	}
+	if notes := properties["notes"].(map[string]any); notes["type"] != "STRING" {
+		t.Errorf("Expected a string for a field without a type, got %v", notes)
+	}
+	ordering := []any{"extraction_class", "extraction_text", "age", "confidence", "notes", "role"}
+	if !reflect.DeepEqual(items["propertyOrdering"], ordering) {
+		t.Errorf("Expected property ordering %v, got %v", ordering, items["propertyOrdering"])
+	}
+}
+
+func TestToGeminiSchema(t *testing.T) {
+	schema := map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"name":  map[string]any{"type": "string", "pattern": "^[A-Z]", "minLength": 1},
+			"score": map[string]any{"type": []any{"number", "null"}, "minimum": 0.0, "maximum": 1.0},
+			"tags":  map[string]any{"type": "array"},
+			"level": map[string]any{"anyOf": []any{map[string]any{"enum": []any{1.0, 2.0}}, map[string]any{"type": "null"}}},
+			"value": map[string]any{"description": "Any value"},
+			"extra": map[string]any{"type": "object", "properties": map[string]any{}},
+		},
+		"required": []any{"name"},
+	}
+
+	got, err := providers.ToGeminiSchema(schema)
+	if err != nil {
+		t.Fatalf("ToGeminiSchema() error = %v", err)
+	}
+
+	minimum, maximum := 0.0, 1.0
+	want := &providers.GeminiSchema{
+		Type: "OBJECT",
+		Properties: map[string]*providers.GeminiSchema{
+			"name":  {Type: "STRING"},
+			"score": {Type: "NUMBER", Nullable: true, Minimum: &minimum, Maximum: &maximum},
+			"tags":  {Type: "ARRAY", Items: &providers.GeminiSchema{Type: "STRING"}},
+			"level": {Type: "STRING", Format: "enum", Enum: []string{"1", "2"}, Nullable: true},
+			"value": {Type: "STRING", Description: "Any value"},
+			"extra": {Type: "STRING"},
+		},
+		Required:         []string{"name"},
+		PropertyOrdering: []string{"name", "extra", "level", "score", "tags", "value"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		t.Errorf("ToGeminiSchema() = %s, want %s", gotJSON, wantJSON)
+	}
+
+	unsupported := []map[string]any{
+		{"type": []any{"string", "number"}},
+		{"anyOf": []any{map[string]any{"type": "string"}, map[string]any{"type": "number"}}},
+	}
+	for _, schema := range unsupported {
+		if _, err := providers.ToGeminiSchema(schema); err == nil {
+			t.Errorf("Expected error for %v", schema)
+		}
+	}
+}
+
+func TestGeminiProviderLogsUnsupportedSchema(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv("GOOGLE_API_KEY", "")
+
+	api := &fakeGeminiAPI{reply: `{"extractions": []}`}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	model, err := providers.NewGeminiProvider(providers.NewModelConfig("gemini-2.5-flash").WithProviderKwargs(map[string]any{
+		"api_key":  "test-key",
+		"base_url": server.URL + "/v1beta",
+	}))
+	if err != nil {
+		t.Fatalf("NewGeminiProvider() error = %v", err)
+	}
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	model.ApplySchema(map[string]any{"type": []any{"string", "number"}})
+	if !strings.Contains(logs.String(), "type union") {
+		t.Errorf("Expected the translation error to be logged, got %q", logs.String())
+	}
+
+	if _, err := model.Infer(context.Background(), []string{"prompt"}, nil); err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	config, _ := api.requests[0]["generationConfig"].(map[string]any)
+	if config["responseMimeType"] != "application/json" || config["responseSchema"] != nil {
+		t.Errorf("Expected plain JSON output, got %v", config)
+	}
+}
@@ -0,0 +1,237 @@
+package providers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// plainModel implements only BaseLanguageModel
+type plainModel struct{}
+
+func (plainModel) Infer(ctx context.Context, prompts []string, options map[string]any) ([][]providers.ScoredOutput, error) {
+	time.Sleep(2 * time.Millisecond)
+	results := make([][]providers.ScoredOutput, len(prompts))
+	for i, prompt := range prompts {
+		results[i] = []providers.ScoredOutput{{Output: prompt, Score: 1.0}}
+	}
+	return results, nil
+}
+
+func (plainModel) ParseOutput(output string) (any, error) { return output, nil }
+func (plainModel) ApplySchema(schema any)                 {}
+func (plainModel) SetFenceOutput(enabled bool)            {}
+func (plainModel) GetModelID() string                     { return "plain" }
+func (plainModel) IsAvailable() bool                      { return true }
+
+func TestInferDetailed(t *testing.T) {
+	api := &fakeMessagesAPI{response: `{
+		"id": "msg_07", "type": "message", "role": "assistant",
+		"content": [{"type": "text", "text": "partial"}],
+		"stop_reason": "max_tokens",
+		"usage": {"input_tokens": 21, "output_tokens": 9}
+	}`}
+	provider := newAnthropicTestProvider(t, api, nil)
+
+	results, err := providers.InferDetailed(context.Background(), provider, []string{"prompt"}, nil)
+	if err != nil {
+		t.Fatalf("InferDetailed() error = %v", err)
+	}
+	result := results[0]
+	if result.Outputs[0].Output != "partial" || result.ResponseID != "msg_07" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if result.Usage.PromptTokens != 21 || result.Usage.CompletionTokens != 9 || result.Usage.TotalTokens != 30 {
+		t.Errorf("Unexpected usage %+v", result.Usage)
+	}
+	if result.FinishReason != providers.FinishReasonLength {
+		t.Errorf("Expected length finish reason, got %q", result.FinishReason)
+	}
+	if result.Latency <= 0 {
+		t.Error("Expected latency to be measured")
+	}
+
+	// Ollama reports usage from the evaluation counts
+	ollama := newOllamaTestProvider(t, &fakeOllamaAPI{version: "0.5.7"}, nil)
+	results, err = providers.InferDetailed(context.Background(), ollama, []string{"prompt"}, nil)
+	if err != nil {
+		t.Fatalf("InferDetailed(ollama) error = %v", err)
+	}
+	if usage := results[0].Usage; usage.PromptTokens != 30 || usage.CompletionTokens != 12 || usage.TotalTokens != 42 {
+		t.Errorf("Unexpected Ollama usage %+v", usage)
+	}
+}
+
+func TestInferDetailedFallback(t *testing.T) {
+	results, err := providers.InferDetailed(context.Background(), plainModel{}, []string{"a", "b"}, nil)
+	if err != nil {
+		t.Fatalf("InferDetailed() error = %v", err)
+	}
+	if len(results) != 2 || results[1].Outputs[0].Output != "b" {
+		t.Fatalf("Unexpected results %v", results)
+	}
+	for _, result := range results {
+		if result.Latency <= 0 || result.Usage.TotalTokens != 0 || result.FinishReason != "" {
+			t.Errorf("Unexpected fallback result %+v", result)
+		}
+	}
+}
+
+func TestInferConcurrentBatch(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []providers.Message `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		prompt := body.Messages[0].Content
+
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		if prompt == "bad" {
+			http.Error(w, `{"error": "bad prompt"}`, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"choices": [{"message": {"role": "assistant", "content": "echo %s"}}]}`, prompt)
+	}))
+	t.Cleanup(server.Close)
+
+	model, err := providers.NewOpenAIProvider(providers.NewModelConfig("gpt-4o-mini").
+		WithMaxConcurrency(2).
+		WithProviderKwargs(map[string]any{"api_key": "test-key", "base_url": server.URL}))
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() error = %v", err)
+	}
+
+	prompts := []string{"a", "bad", "c", "d", "bad", "f"}
+	results, err := model.Infer(context.Background(), prompts, nil)
+
+	var batchErr *providers.BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("Expected BatchError, got %v", err)
+	}
+	if batchErr.Total != 6 || len(batchErr.Errors) != 2 || batchErr.Errors[0].Index != 1 || batchErr.Errors[1].Index != 4 {
+		t.Errorf("Unexpected batch error %+v", batchErr)
+	}
+
+	if len(results) != len(prompts) {
+		t.Fatalf("Expected %d results, got %d", len(prompts), len(results))
+	}
+	for i, prompt := range prompts {
+		if prompt == "bad" {
+			if results[i] != nil {
+				t.Errorf("Expected no result for failed prompt %d, got %v", i, results[i])
+			}
+			continue
+		}
+		if len(results[i]) != 1 || results[i][0].Output != "echo "+prompt {
+			t.Errorf("Result %d out of order: %v", i, results[i])
+		}
+	}
+
+	if maxInFlight != 2 {
+		t.Errorf("Expected requests to run 2 at a time, got at most %d", maxInFlight)
+	}
+}
+
+func TestInferBatchCancellation(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	model, err := providers.NewOpenAIProvider(providers.NewModelConfig("gpt-4o-mini").
+		WithMaxConcurrency(1).
+		WithProviderKwargs(map[string]any{"api_key": "test-key", "base_url": server.URL}))
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = model.Infer(ctx, []string{"a", "b", "c"}, nil)
+	var batchErr *providers.BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 3 {
+		t.Fatalf("Expected every prompt to fail, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context error, got %v", err)
+	}
+}
+
+func TestInferBatchReleasesReservationWhileWaitingForSlot(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["stream"] == true {
+			fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hel\"}}]}\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+		fmt.Fprint(w, `{"choices": [{"message": {"role": "assistant", "content": "ok"}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	// One request at a time and two requests per minute, for a model of its own
+	// since rate limits are shared per model
+	model, err := providers.NewOpenAIProvider(providers.NewModelConfig(fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())).
+		WithMaxConcurrency(1).
+		WithRateLimit(2, 0).
+		WithProviderKwargs(map[string]any{"api_key": "test-key", "base_url": server.URL}))
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() error = %v", err)
+	}
+
+	// A stream holds the only slot while a batch gives up waiting for it
+	streamCtx, cancelStream := context.WithCancel(context.Background())
+	chunks, err := model.(providers.StreamingLanguageModel).InferStream(streamCtx, "prompt", nil)
+	if err != nil {
+		t.Fatalf("InferStream() error = %v", err)
+	}
+	<-chunks
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := model.Infer(ctx, []string{"a"}, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the batch to time out waiting for the slot, got %v", err)
+	}
+
+	cancelStream()
+	for range chunks {
+	}
+
+	// Neither request kept its reservation, so both requests are available
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		if _, err := model.Infer(ctx, []string{"a"}, nil); err != nil {
+			t.Fatalf("Infer() %d error = %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package providers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// fakeOllamaAPI is an httptest fake of the Ollama tags, version, generate and chat endpoints
+type fakeOllamaAPI struct {
+	mu              sync.Mutex
+	version         string
+	versionFailures int // number of version requests to fail
+	requests        []map[string]any
+}
+
+func (f *fakeOllamaAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/tags":
+		json.NewEncoder(w).Encode(map[string]any{"models": []any{}})
+	case "/api/version":
+		f.mu.Lock()
+		fail := f.versionFailures > 0
+		if fail {
+			f.versionFailures--
+		}
+		f.mu.Unlock()
+		if fail {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"version": f.version})
+	case "/api/generate", "/api/chat":
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body["endpoint"] = r.URL.Path
+		f.mu.Lock()
+		f.requests = append(f.requests, body)
+		f.mu.Unlock()
+		response := map[string]any{
+			"model":             body["model"],
+			"done":              true,
+			"prompt_eval_count": 30,
+			"eval_count":        12,
+		}
+		if r.URL.Path == "/api/chat" {
+			response["message"] = map[string]any{"role": "assistant", "content": `{"extractions": []}`}
+		} else {
+			response["response"] = `{"extractions": []}`
+		}
+		json.NewEncoder(w).Encode(response)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newOllamaTestProvider(t *testing.T, api *fakeOllamaAPI, kwargs map[string]any) *providers.OllamaProvider {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	allKwargs := map[string]any{"base_url": server.URL}
+	for key, value := range kwargs {
+		allKwargs[key] = value
+	}
+
+	model, err := providers.NewOllamaProvider(providers.NewModelConfig("llama3.2").
+		WithMaxTokens(256).
+		WithProviderKwargs(allKwargs))
+	if err != nil {
+		t.Fatalf("NewOllamaProvider() error = %v", err)
+	}
+	return model.(*providers.OllamaProvider)
+}
+
+func TestOllamaProviderFormat(t *testing.T) {
+	schema := map[string]any{"type": "object", "properties": map[string]any{"extractions": map[string]any{"type": "array"}}}
+
+	tests := []struct {
+		name       string
+		version    string
+		kwargs     map[string]any
+		wantSchema bool
+	}{
+		{"schema on new server", "0.5.7", nil, true},
+		{"json on old server", "0.4.2", nil, false},
+		{"kwarg overrides detection", "0.5.7", map[string]any{"structured_outputs": false}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeOllamaAPI{version: tt.version}
+			provider := newOllamaTestProvider(t, api, tt.kwargs)
+
+			// No format without a schema
+			if _, err := provider.Infer(context.Background(), []string{"prompt"}, nil); err != nil {
+				t.Fatalf("Infer() error = %v", err)
+			}
+			if _, ok := api.requests[0]["format"]; ok {
+				t.Errorf("Expected no format without a schema, got %v", api.requests[0]["format"])
+			}
+
+			provider.ApplySchema(schema)
+			if _, err := provider.Infer(context.Background(), []string{"prompt"}, nil); err != nil {
+				t.Fatalf("Infer() error = %v", err)
+			}
+			format := api.requests[1]["format"]
+			if tt.wantSchema {
+				if object, ok := format.(map[string]any); !ok || object["type"] != "object" {
+					t.Errorf("Expected schema format, got %v", format)
+				}
+			} else if format != "json" {
+				t.Errorf("Expected json format, got %v", format)
+			}
+		})
+	}
+}
+
+func TestOllamaProviderFormatDetectionRetries(t *testing.T) {
+	api := &fakeOllamaAPI{version: "0.5.7", versionFailures: 1}
+	provider := newOllamaTestProvider(t, api, nil)
+	provider.ApplySchema(map[string]any{"type": "object"})
+
+	// The first detection fails and falls back to JSON; the next one succeeds
+	for i := 0; i < 2; i++ {
+		if _, err := provider.Infer(context.Background(), []string{"prompt"}, nil); err != nil {
+			t.Fatalf("Infer() error = %v", err)
+		}
+	}
+	if format := api.requests[0]["format"]; format != "json" {
+		t.Errorf("Expected json format while the version is unknown, got %v", format)
+	}
+	if _, ok := api.requests[1]["format"].(map[string]any); !ok {
+		t.Errorf("Expected schema format once the version is known, got %v", api.requests[1]["format"])
+	}
+}
+
+func TestOllamaProviderOptionsAndUsage(t *testing.T) {
+	api := &fakeOllamaAPI{version: "0.5.7"}
+	provider := newOllamaTestProvider(t, api, map[string]any{
+		"num_ctx":     8192,
+		"num_predict": 1024,
+		"seed":        42,
+	})
+
+	if _, err := provider.Infer(context.Background(), []string{"first", "second"}, nil); err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+
+	options, _ := api.requests[0]["options"].(map[string]any)
+	if options["num_ctx"] != 8192.0 || options["seed"] != 42.0 {
+		t.Errorf("Expected kwargs as options, got %v", options)
+	}
+	if options["num_predict"] != 1024.0 {
+		t.Errorf("Expected num_predict kwarg to override max tokens, got %v", options["num_predict"])
+	}
+
+	usage := provider.Usage()
+	if usage.PromptTokens != 60 || usage.CompletionTokens != 24 || usage.TotalTokens != 84 {
+		t.Errorf("Unexpected usage %+v", usage)
+	}
+}
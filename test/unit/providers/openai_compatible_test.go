@@ -0,0 +1,217 @@
+package providers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// fakeChatAPI is an httptest fake of the OpenAI chat completions endpoint.
+// choice replaces the default choice built from reply when set.
+type fakeChatAPI struct {
+	mu       sync.Mutex
+	reply    string
+	choice   map[string]any
+	requests []map[string]any
+	headers  []http.Header
+}
+
+func (f *fakeChatAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/v1/chat/completions" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, body)
+	f.headers = append(f.headers, r.Header.Clone())
+	choice := f.choice
+	if choice == nil {
+		choice = map[string]any{"index": 0, "message": map[string]any{"role": "assistant", "content": f.reply}}
+	}
+	f.mu.Unlock()
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"id":      "chatcmpl-1",
+		"object":  "chat.completion",
+		"choices": []any{choice},
+	})
+}
+
+func newChatServer(t *testing.T, reply string) (*fakeChatAPI, string) {
+	t.Helper()
+	api := &fakeChatAPI{reply: reply}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return api, server.URL + "/v1"
+}
+
+func TestOpenAICompatibleMultipleInstances(t *testing.T) {
+	vllm, vllmURL := newChatServer(t, "from vllm")
+	studio, studioURL := newChatServer(t, "from lm studio")
+	t.Setenv("TEST_VLLM_KEY", "vllm-secret")
+
+	registry := providers.NewProviderRegistry()
+	err := registry.RegisterOpenAICompatible(providers.OpenAICompatibleConfig{
+		Name:             "vllm",
+		BaseURL:          vllmURL,
+		APIKeyEnv:        "TEST_VLLM_KEY",
+		Headers:          map[string]string{"X-Team": "extraction"},
+		Models:           []string{"qwen2.5-7b-instruct"},
+		SupportsJSONMode: true,
+	})
+	if err != nil {
+		t.Fatalf("RegisterOpenAICompatible(vllm) error = %v", err)
+	}
+	err = registry.RegisterOpenAICompatible(providers.OpenAICompatibleConfig{
+		Name:    "lmstudio",
+		BaseURL: studioURL,
+		Models:  []string{"llama-3.2-3b"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterOpenAICompatible(lmstudio) error = %v", err)
+	}
+
+	schema := map[string]any{"type": "object"}
+
+	// The model list routes model IDs to the right server
+	vllmModel, err := registry.CreateModel(providers.NewModelConfig("qwen2.5-7b-instruct"))
+	if err != nil {
+		t.Fatalf("CreateModel(vllm) error = %v", err)
+	}
+	vllmModel.ApplySchema(schema)
+	results, err := vllmModel.Infer(context.Background(), []string{"prompt"}, nil)
+	if err != nil || results[0][0].Output != "from vllm" {
+		t.Fatalf("Infer(vllm) = %v, %v", results, err)
+	}
+
+	studioModel, err := registry.CreateModel(providers.NewModelConfig("llama-3.2-3b"))
+	if err != nil {
+		t.Fatalf("CreateModel(lmstudio) error = %v", err)
+	}
+	if !studioModel.IsAvailable() {
+		t.Error("Expected a server without an API key to be available")
+	}
+	studioModel.ApplySchema(schema)
+	results, err = studioModel.Infer(context.Background(), []string{"prompt"}, nil)
+	if err != nil || results[0][0].Output != "from lm studio" {
+		t.Fatalf("Infer(lmstudio) = %v, %v", results, err)
+	}
+
+	if got := vllm.headers[0].Get("Authorization"); got != "Bearer vllm-secret" {
+		t.Errorf("Expected API key from environment, got %q", got)
+	}
+	if got := vllm.headers[0].Get("X-Team"); got != "extraction" {
+		t.Errorf("Expected custom header, got %q", got)
+	}
+	if format, _ := vllm.requests[0]["response_format"].(map[string]any); format["type"] != "json_object" {
+		t.Errorf("Expected JSON mode for vllm, got %v", vllm.requests[0]["response_format"])
+	}
+
+	if got := studio.headers[0].Get("Authorization"); got != "" {
+		t.Errorf("Expected no Authorization header without an API key, got %q", got)
+	}
+	if _, ok := studio.requests[0]["response_format"]; ok {
+		t.Error("Expected no response_format for a server without JSON mode")
+	}
+
+	// Models outside the list are rejected
+	_, err = registry.CreateModel(providers.NewModelConfig("gpt-4").WithProvider("vllm"))
+	if err == nil || !strings.Contains(err.Error(), "not served by provider vllm") {
+		t.Errorf("Expected unknown model error, got %v", err)
+	}
+
+	// A model cannot move to another server, but a server can be registered again
+	err = registry.RegisterOpenAICompatible(providers.OpenAICompatibleConfig{
+		Name:    "ollama-openai",
+		BaseURL: studioURL,
+		Models:  []string{"mistral-7b", "llama-3.2-3b"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "already served by provider lmstudio") {
+		t.Errorf("Expected an alias conflict, got %v", err)
+	}
+	if registry.HasProvider("ollama-openai") {
+		t.Error("Expected nothing to be registered on a conflict")
+	}
+	err = registry.RegisterOpenAICompatible(providers.OpenAICompatibleConfig{
+		Name:    "lmstudio",
+		BaseURL: studioURL,
+		Models:  []string{"llama-3.2-3b"},
+	})
+	if err != nil {
+		t.Errorf("Expected a server to be registered again, got %v", err)
+	}
+}
+
+func TestOpenAICompatibleKwargs(t *testing.T) {
+	api, baseURL := newChatServer(t, `{"extractions": []}`)
+
+	registry := providers.NewProviderRegistry()
+	providers.RegisterDefaultProviders(registry)
+
+	config := providers.NewModelConfig("local-model").
+		WithProvider(providers.OpenAICompatibleType).
+		WithProviderKwargs(map[string]any{
+			"base_url":  baseURL,
+			"api_key":   "kwarg-key",
+			"headers":   map[string]any{"X-Request-Source": "tests"},
+			"json_mode": true,
+		})
+
+	model, err := registry.CreateModel(config)
+	if err != nil {
+		t.Fatalf("CreateModel() error = %v", err)
+	}
+	model.ApplySchema(map[string]any{"type": "object"})
+	if _, err := model.Infer(context.Background(), []string{"prompt"}, nil); err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+
+	header := api.headers[0]
+	if header.Get("Authorization") != "Bearer kwarg-key" || header.Get("X-Request-Source") != "tests" {
+		t.Errorf("Unexpected headers %v", header)
+	}
+	if api.requests[0]["model"] != "local-model" || api.requests[0]["response_format"] == nil {
+		t.Errorf("Unexpected request %v", api.requests[0])
+	}
+
+	_, err = registry.CreateModel(providers.NewModelConfig("local-model").WithProvider(providers.OpenAICompatibleType))
+	if err == nil || !strings.Contains(err.Error(), "base_url") {
+		t.Errorf("Expected missing base_url error, got %v", err)
+	}
+}
+
+func TestOpenAICompatibleConfigValidation(t *testing.T) {
+	registry := providers.NewProviderRegistry()
+
+	tests := []struct {
+		name   string
+		config providers.OpenAICompatibleConfig
+		want   string
+	}{
+		{"missing name", providers.OpenAICompatibleConfig{BaseURL: "http://localhost:8000/v1"}, "name is required"},
+		{"missing base URL", providers.OpenAICompatibleConfig{Name: "vllm"}, "base URL is required"},
+		{"invalid base URL", providers.OpenAICompatibleConfig{Name: "vllm", BaseURL: "localhost:8000"}, "must start with"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registry.RegisterOpenAICompatible(tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("RegisterOpenAICompatible() error = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package providers_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sehwan505/langextract-go/pkg/extraction"
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+func newOpenAITestProvider(t *testing.T, api *fakeChatAPI) providers.BaseLanguageModel {
+	t.Helper()
+	t.Setenv("OPENAI_API_KEY", "")
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	config := providers.NewModelConfig("gpt-4o-mini").WithProviderKwargs(map[string]any{
+		"api_key":  "test-key",
+		"base_url": server.URL + "/v1",
+	})
+	model, err := providers.NewOpenAIProvider(config)
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() error = %v", err)
+	}
+	return model
+}
+
+func extractionJSONSchema(t *testing.T) map[string]any {
+	t.Helper()
+	schema := extraction.NewBasicExtractionSchema("People Schema", "People in text")
+	schema.AddClass(&extraction.ClassDefinition{
+		Name: "person",
+		Fields: []*extraction.FieldDefinition{
+			{Name: "role", Type: "string"},
+		},
+	})
+	jsonSchema, err := schema.ToJSONSchema()
+	if err != nil {
+		t.Fatalf("ToJSONSchema() error = %v", err)
+	}
+	return jsonSchema
+}
+
+func TestOpenAIProviderStructuredOutputs(t *testing.T) {
+	api := &fakeChatAPI{reply: `{"extractions": [{"extraction_class": "person", "extraction_text": "Alice", "confidence": 0.9, "role": null}]}`}
+	model := newOpenAITestProvider(t, api)
+	model.ApplySchema(extractionJSONSchema(t))
+
+	results, err := model.Infer(context.Background(), []string{"Alice met Bob."}, nil)
+	if err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	if len(results) != 1 || len(results[0]) != 1 {
+		t.Fatalf("Unexpected results %v", results)
+	}
+
+	format, _ := api.requests[0]["response_format"].(map[string]any)
+	if format["type"] != "json_schema" {
+		t.Fatalf("Expected json_schema response format, got %v", api.requests[0]["response_format"])
+	}
+	jsonSchema := format["json_schema"].(map[string]any)
+	if jsonSchema["name"] != "People_Schema" || jsonSchema["strict"] != true {
+		t.Errorf("Unexpected json_schema %v", jsonSchema)
+	}
+
+	root := jsonSchema["schema"].(map[string]any)
+	if _, ok := root["$schema"]; ok {
+		t.Error("Expected $schema to be removed in strict mode")
+	}
+	if root["additionalProperties"] != false {
+		t.Errorf("Expected additionalProperties false on the root, got %v", root["additionalProperties"])
+	}
+	items := root["properties"].(map[string]any)["extractions"].(map[string]any)["items"].(map[string]any)
+	if items["additionalProperties"] != false {
+		t.Errorf("Expected additionalProperties false on items, got %v", items["additionalProperties"])
+	}
+	if required, _ := items["required"].([]any); len(required) != 4 {
+		t.Errorf("Expected all item properties to be required, got %v", items["required"])
+	}
+	role := items["properties"].(map[string]any)["role"].(map[string]any)
+	if types, _ := role["type"].([]any); len(types) != 2 || types[1] != "null" {
+		t.Errorf("Expected optional field to be nullable, got %v", role)
+	}
+
+	// The prompt asks for a confidence, so strict mode must allow one
+	confidence, _ := items["properties"].(map[string]any)["confidence"].(map[string]any)
+	if types, _ := confidence["type"].([]any); len(types) != 2 || types[0] != "number" {
+		t.Errorf("Expected a nullable number confidence, got %v", confidence)
+	}
+	if !strings.Contains(results[0][0].Output, `"confidence": 0.9`) {
+		t.Errorf("Expected the confidence to pass validation, got %q", results[0][0].Output)
+	}
+}
+
+func TestOpenAIProviderJSONMode(t *testing.T) {
+	api := &fakeChatAPI{reply: `{"extractions": []}`}
+	model := newOpenAITestProvider(t, api)
+
+	if _, err := model.Infer(context.Background(), []string{"Return JSON."}, nil); err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	if format, _ := api.requests[0]["response_format"].(map[string]any); format["type"] != "json_object" {
+		t.Errorf("Expected json_object without a schema, got %v", api.requests[0]["response_format"])
+	}
+
+	// JSON mode requires the prompt to ask for JSON
+	if _, err := model.Infer(context.Background(), []string{"Say hello."}, nil); err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	if _, ok := api.requests[1]["response_format"]; ok {
+		t.Errorf("Expected no response_format for a plain prompt, got %v", api.requests[1]["response_format"])
+	}
+}
+
+func TestOpenAIProviderRefusal(t *testing.T) {
+	api := &fakeChatAPI{choice: map[string]any{
+		"index":         0,
+		"message":       map[string]any{"role": "assistant", "content": nil, "refusal": "I can't help with that."},
+		"finish_reason": "stop",
+	}}
+	model := newOpenAITestProvider(t, api)
+	model.ApplySchema(extractionJSONSchema(t))
+
+	_, err := model.Infer(context.Background(), []string{"prompt"}, nil)
+	var refusal *providers.RefusalError
+	if !errors.As(err, &refusal) {
+		t.Fatalf("Expected RefusalError, got %v", err)
+	}
+	if refusal.Refusal != "I can't help with that." || refusal.Provider != "openai" {
+		t.Errorf("Unexpected refusal %+v", refusal)
+	}
+}
+
+func TestOpenAIProviderSchemaViolation(t *testing.T) {
+	tests := []struct {
+		name   string
+		choice map[string]any
+		reason string
+	}{
+		{
+			name: "wrong class",
+			choice: map[string]any{"message": map[string]any{"role": "assistant",
+				"content": `{"extractions": [{"extraction_class": "place", "extraction_text": "Paris", "confidence": null, "role": null}]}`}},
+			reason: "does not match schema",
+		},
+		{
+			name:   "invalid JSON",
+			choice: map[string]any{"message": map[string]any{"role": "assistant", "content": `{"extractions": [`}},
+			reason: "not valid JSON",
+		},
+		{
+			name: "truncated",
+			choice: map[string]any{"message": map[string]any{"role": "assistant", "content": `{"extractions": [`},
+				"finish_reason": "length"},
+			reason: "truncated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeChatAPI{choice: tt.choice}
+			model := newOpenAITestProvider(t, api)
+			model.ApplySchema(extractionJSONSchema(t))
+
+			_, err := model.Infer(context.Background(), []string{"prompt"}, nil)
+			var violation *providers.SchemaViolationError
+			if !errors.As(err, &violation) {
+				t.Fatalf("Expected SchemaViolationError, got %v", err)
+			}
+			if !strings.Contains(violation.Reason, tt.reason) || violation.Output == "" {
+				t.Errorf("Unexpected violation %+v", violation)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package providers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// testPluginEnv makes the test binary serve a fake model as a plugin. Values
+// other than "1" are the name the plugin reports.
+const testPluginEnv = "LANGEXTRACT_TEST_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if name := os.Getenv(testPluginEnv); name != "" {
+		if name == "1" {
+			name = "test-plugin"
+		}
+		model := providers.NewFakeLanguageModel("fake-plugin-model").
+			WithOutput(`^extract`, `{"name": "Alice"}`).
+			WithRule(`^fail`, providers.FakeResponse{Err: providers.ErrRateLimited}).
+			WithDefault(providers.FakeResponse{Output: "ok"})
+		if err := providers.ServePlugin(context.Background(), name, model, os.Stdin, os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// installTestPlugin installs the test binary as a plugin in a temporary
+// directory.
+func installTestPlugin(t *testing.T) (string, *providers.PluginManifest) {
+	t.Helper()
+	t.Setenv(testPluginEnv, "1")
+
+	dir := t.TempDir()
+	manifest, err := providers.InstallPlugin(context.Background(), dir, "", os.Args[0], nil, false)
+	if err != nil {
+		t.Fatalf("InstallPlugin() error = %v", err)
+	}
+	return dir, manifest
+}
+
+func TestPluginProviderInfer(t *testing.T) {
+	dir, manifest := installTestPlugin(t)
+	if manifest.Name != "test-plugin" || len(manifest.Models) != 1 || manifest.Models[0] != "fake-plugin-model" {
+		t.Fatalf("Expected the plugin capabilities in the manifest, got %+v", manifest)
+	}
+
+	registry := providers.NewProviderRegistry()
+	if err := registry.RegisterPlugins(dir); err != nil {
+		t.Fatalf("RegisterPlugins() error = %v", err)
+	}
+	model, err := registry.CreateModel(providers.NewModelConfig("fake-plugin-model"))
+	if err != nil {
+		t.Fatalf("CreateModel() error = %v", err)
+	}
+	defer model.(*providers.PluginProvider).Close()
+
+	if !model.IsAvailable() {
+		t.Fatal("Expected the plugin to be healthy")
+	}
+
+	prompts := []string{"extract names", "other", "fail please"}
+	results, err := providers.InferDetailed(context.Background(), model, prompts, nil)
+
+	var batchErr *providers.BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 || batchErr.Errors[0].Index != 2 {
+		t.Fatalf("Expected only the third prompt to fail, got %v", err)
+	}
+	var pluginErr *providers.PluginError
+	if !errors.As(err, &pluginErr) || !errors.Is(err, providers.ErrRateLimited) || !providers.IsRetryable(err) {
+		t.Errorf("Expected a retryable plugin error, got %v", err)
+	}
+
+	if got := results[0].Outputs[0].Output; got != `{"name": "Alice"}` {
+		t.Errorf("Expected the rule output, got %q", got)
+	}
+	if got := results[1].Outputs[0].Output; got != "ok" {
+		t.Errorf("Expected the default output, got %q", got)
+	}
+	if results[0].Usage.TotalTokens == 0 || results[0].FinishReason != providers.FinishReasonStop {
+		t.Errorf("Expected usage and finish reason, got %+v", results[0])
+	}
+}
+
+func TestPluginProviderRestartsAfterClose(t *testing.T) {
+	_, manifest := installTestPlugin(t)
+
+	plugin, err := providers.NewPluginProvider(manifest, providers.NewModelConfig("fake-plugin-model"))
+	if err != nil {
+		t.Fatalf("NewPluginProvider() error = %v", err)
+	}
+	defer plugin.Close()
+
+	for i := 0; i < 2; i++ {
+		results, err := plugin.Infer(context.Background(), []string{"hello"}, nil)
+		if err != nil || results[0][0].Output != "ok" {
+			t.Fatalf("Infer() = %v, %v", results, err)
+		}
+		if err := plugin.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+}
+
+func TestPluginProviderInferMessages(t *testing.T) {
+	_, manifest := installTestPlugin(t)
+
+	plugin, err := providers.NewPluginProvider(manifest, providers.NewModelConfig("fake-plugin-model"))
+	if err != nil {
+		t.Fatalf("NewPluginProvider() error = %v", err)
+	}
+	defer plugin.Close()
+
+	conversation := []providers.Message{
+		{Role: providers.RoleSystem, Content: "extract people"},
+		{Role: providers.RoleUser, Content: "Alice is here."},
+	}
+	results, err := providers.InferMessages(context.Background(), plugin, [][]providers.Message{conversation}, nil)
+	if err != nil {
+		t.Fatalf("InferMessages() error = %v", err)
+	}
+	if got := results[0].Outputs[0].Output; got != `{"name": "Alice"}` {
+		t.Errorf("Expected the conversation to reach the plugin, got %q", got)
+	}
+}
+
+func TestInstallPluginRejectsBuiltinNames(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Setenv(testPluginEnv, "1")
+	for _, name := range []string{"openai", providers.PluginType} {
+		if _, err := providers.InstallPlugin(context.Background(), dir, name, os.Args[0], nil, false); err == nil {
+			t.Errorf("Expected the name of the built-in provider %s to be rejected", name)
+		}
+	}
+
+	// A plugin reporting the name of a built-in provider
+	t.Setenv(testPluginEnv, "gemini")
+	if _, err := providers.InstallPlugin(context.Background(), dir, "", os.Args[0], nil, false); err == nil {
+		t.Error("Expected the reported name of a built-in provider to be rejected")
+	}
+
+	if manifests, err := providers.LoadPluginManifests(dir); err != nil || len(manifests) != 0 {
+		t.Errorf("Expected no manifests to be written, got %v, %v", manifests, err)
+	}
+}
+
+func TestPluginTypeNeedsCommand(t *testing.T) {
+	registry := providers.NewProviderRegistry()
+	providers.RegisterDefaultProviders(registry)
+	if registry.HasProvider(providers.PluginType) {
+		t.Error("Expected the generic plugin type not to be a default provider")
+	}
+
+	_, err := providers.CreateModel(providers.NewModelConfig("some-model").WithProvider(providers.PluginType))
+	if err == nil || !strings.Contains(err.Error(), "no command") {
+		t.Errorf("Expected an error without a command, got %v", err)
+	}
+}
+
+func TestRegisterPluginsSkipsRegisteredProviders(t *testing.T) {
+	dir := t.TempDir()
+	manifest := `{"name": "openai", "command": "/bin/false", "models": ["gpt-4"]}`
+	if err := os.WriteFile(filepath.Join(dir, "openai.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := providers.NewProviderRegistry()
+	providers.RegisterDefaultProviders(registry)
+	err := registry.RegisterPlugins(dir)
+	if err == nil || !strings.Contains(err.Error(), "conflicts") {
+		t.Errorf("Expected a conflict with the built-in provider, got %v", err)
+	}
+
+	if err := providers.UninstallPlugin(dir, "openai"); err != nil {
+		t.Errorf("UninstallPlugin() error = %v", err)
+	}
+	if err := providers.UninstallPlugin(dir, "openai"); err == nil {
+		t.Error("Expected an error uninstalling a plugin that is not installed")
+	}
+}
+
+func TestRegisterPluginsSkipsServedModels(t *testing.T) {
+	dir := t.TempDir()
+	manifest := `{"name": "shadow", "command": "/bin/false", "models": ["gpt-4", "gpt-4o", "shadow-model"]}`
+	if err := os.WriteFile(filepath.Join(dir, "shadow.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := providers.NewProviderRegistry()
+	providers.RegisterDefaultProviders(registry)
+	err := registry.RegisterPlugins(dir)
+	for _, modelID := range []string{"gpt-4", "gpt-4o"} {
+		if err == nil || !strings.Contains(err.Error(), "model "+modelID+" conflicts") {
+			t.Errorf("Expected a conflict for %s, got %v", modelID, err)
+		}
+	}
+
+	for modelID, plugin := range map[string]bool{"gpt-4": false, "gpt-4o": false, "shadow-model": true} {
+		config := providers.NewModelConfig(modelID).WithProviderKwargs(map[string]any{"api_key": "test-key"})
+		model, err := registry.CreateModel(config)
+		if err != nil {
+			t.Fatalf("CreateModel(%s) error = %v", modelID, err)
+		}
+		if _, ok := model.(*providers.PluginProvider); ok != plugin {
+			t.Errorf("Expected %s to be served by the plugin: %v, got %T", modelID, plugin, model)
+		}
+	}
+}
+
+func TestServePluginProtocol(t *testing.T) {
+	model := providers.NewFakeLanguageModel("served-model").WithDefault(providers.FakeResponse{Output: "served"})
+	in := strings.NewReader(`{"jsonrpc": "2.0", "id": 1, "method": "capabilities"}
+{"jsonrpc": "2.0", "id": "two", "method": "infer", "params": {"model_id": "served-model", "prompt": "hi"}}
+{"jsonrpc": "2.0", "id": 3, "method": "unknown"}
+{"jsonrpc": "2.0", "id": 4, "method": "infer", "params": {"model_id": "served-model", "prompt": "flattened", "messages": [{"role": "system", "content": "be brief"}, {"role": "user", "content": "hello"}]}}
+`)
+	var out bytes.Buffer
+	if err := providers.ServePlugin(context.Background(), "served", model, in, &out); err != nil {
+		t.Fatalf("ServePlugin() error = %v", err)
+	}
+
+	responses := map[string]map[string]any{}
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var response map[string]any
+		if err := decoder.Decode(&response); err != nil {
+			t.Fatalf("Invalid response: %v", err)
+		}
+		id, _ := json.Marshal(response["id"])
+		responses[string(id)] = response
+	}
+
+	capabilities, _ := responses["1"]["result"].(map[string]any)
+	if capabilities["name"] != "served" || capabilities["protocol_version"] != float64(providers.PluginProtocolVersion) {
+		t.Errorf("Unexpected capabilities response: %v", responses["1"])
+	}
+	result, _ := responses[`"two"`]["result"].(map[string]any)
+	if outputs, _ := result["outputs"].([]any); len(outputs) != 1 || outputs[0].(map[string]any)["output"] != "served" {
+		t.Errorf("Unexpected infer response: %v", responses[`"two"`])
+	}
+	rpcErr, _ := responses["3"]["error"].(map[string]any)
+	if rpcErr["code"] != float64(-32601) {
+		t.Errorf("Expected a method not found error, got %v", responses["3"])
+	}
+
+	// Messages take precedence over the prompt; requests are served
+	// concurrently, so the prompts may come in any order
+	if prompts := strings.Join(model.Prompts(), "|"); !strings.Contains(prompts, "be brief\n\nhello") || strings.Contains(prompts, "flattened") {
+		t.Errorf("Expected the conversation to be answered, got prompts %q", prompts)
+	}
+}
@@ -94,6 +94,15 @@ func TestProviderRegistryAliases(t *testing.T) {
 	if model.GetModelID() != "test-model" {
 		t.Errorf("GetModelID() = %q, want 'test-model'", model.GetModelID())
 	}
+
+	// Unregistering removes the provider and its aliases
+	registry.Unregister("mock")
+	if registry.HasProvider("mock") {
+		t.Error("Provider 'mock' should be unregistered")
+	}
+	if _, err := registry.CreateModel(config); err == nil || !strings.Contains(err.Error(), "no alias") {
+		t.Errorf("Expected the alias to be removed, got %v", err)
+	}
 }
 
 func TestCreateModelErrors(t *testing.T) {
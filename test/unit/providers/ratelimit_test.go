@@ -0,0 +1,100 @@
+package providers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// newRateLimitedProviders creates OpenAI providers for one model against a
+// server that reports usage tokens for each request. Each test uses its own
+// model ID, since rate limits are shared per model.
+func newRateLimitedProviders(t *testing.T, n, rpm, tpm, usageTokens int) ([]providers.BaseLanguageModel, *int32) {
+	t.Helper()
+	t.Setenv("OPENAI_API_KEY", "")
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		json.NewDecoder(r.Body).Decode(&map[string]any{})
+		fmt.Fprintf(w, `{"choices": [{"message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}],
+			"usage": {"prompt_tokens": %d, "completion_tokens": 0, "total_tokens": %d}}`, usageTokens, usageTokens)
+	}))
+	t.Cleanup(server.Close)
+
+	modelID := fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())
+	models := make([]providers.BaseLanguageModel, n)
+	for i := range models {
+		model, err := providers.NewOpenAIProvider(providers.NewModelConfig(modelID).
+			WithRateLimit(rpm, tpm).
+			WithProviderKwargs(map[string]any{"api_key": "test-key", "base_url": server.URL}))
+		if err != nil {
+			t.Fatalf("NewOpenAIProvider() error = %v", err)
+		}
+		models[i] = model
+	}
+	return models, &requests
+}
+
+func TestRateLimitTokensReconciledAndShared(t *testing.T) {
+	// 6000 tokens per minute refill at 100 tokens per second. The first
+	// request is estimated at one token but uses 6050, leaving the bucket
+	// 50 tokens in debt for the next request on another provider instance.
+	models, _ := newRateLimitedProviders(t, 2, 0, 6000, 6050)
+
+	if _, err := models[0].Infer(context.Background(), []string{"big"}, nil); err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+
+	start := time.Now()
+	if _, err := models[1].Infer(context.Background(), []string{"next"}, nil); err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond || elapsed > 3*time.Second {
+		t.Errorf("Expected the second request to wait about 0.5s, waited %v", elapsed)
+	}
+}
+
+func TestRateLimitTokensRefunded(t *testing.T) {
+	// A prompt estimated at the full 6000 tokens that uses only 10 returns
+	// the rest, so the next 500 token prompt does not wait 5 seconds.
+	models, _ := newRateLimitedProviders(t, 1, 0, 6000, 10)
+
+	start := time.Now()
+	prompts := []string{strings.Repeat("a", 24000), strings.Repeat("b", 2000)}
+	for _, prompt := range prompts {
+		if _, err := models[0].Infer(context.Background(), []string{prompt}, nil); err != nil {
+			t.Fatalf("Infer() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected unused tokens to be returned, waited %v", elapsed)
+	}
+}
+
+func TestRateLimitRequestsCancelled(t *testing.T) {
+	models, requests := newRateLimitedProviders(t, 1, 1, 0, 10)
+
+	if _, err := models[0].Infer(context.Background(), []string{"first"}, nil); err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := models[0].Infer(ctx, []string{"second"}, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the request to wait for the limit until cancelled, got %v", err)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("Expected 1 request to reach the server, got %d", n)
+	}
+}
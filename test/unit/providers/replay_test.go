@@ -0,0 +1,97 @@
+package providers_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+func TestReplayProviderRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	recorder, err := providers.NewReplayProvider(plainModel{}, "", providers.ReplayModeRecord, dir)
+	if err != nil {
+		t.Fatalf("NewReplayProvider() error = %v", err)
+	}
+	if _, err := recorder.Infer(context.Background(), []string{"first prompt", "second prompt"}, nil); err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.json")); len(files) != 2 {
+		t.Fatalf("Expected 2 cassettes, got %v", files)
+	}
+
+	// Replaying needs no model
+	player, err := providers.NewReplayProvider(nil, "plain", providers.ReplayModeReplay, dir)
+	if err != nil {
+		t.Fatalf("NewReplayProvider() error = %v", err)
+	}
+	results, err := player.Infer(context.Background(), []string{"second prompt \r\n", "first prompt"}, nil)
+	if err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	if results[0][0].Output != "second prompt" || results[1][0].Output != "first prompt" {
+		t.Errorf("Unexpected replayed results %v", results)
+	}
+	if !player.IsAvailable() {
+		t.Error("Expected replay to be available without a model")
+	}
+
+	// A prompt that was not recorded fails
+	results, err = player.Infer(context.Background(), []string{"first prompt", "third prompt"}, nil)
+	if !errors.Is(err, providers.ErrCassetteNotFound) {
+		t.Fatalf("Expected a cassette miss, got %v", err)
+	}
+	if results[0] == nil || results[1] != nil {
+		t.Errorf("Expected only the recorded prompt to be replayed, got %v", results)
+	}
+
+	// Cassettes are keyed by model
+	other, _ := providers.NewReplayProvider(nil, "other-model", providers.ReplayModeReplay, dir)
+	if _, err := other.Infer(context.Background(), []string{"first prompt"}, nil); !errors.Is(err, providers.ErrCassetteNotFound) {
+		t.Errorf("Expected a cassette miss for another model, got %v", err)
+	}
+}
+
+func TestReplayProviderFromModelConfig(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	dir := t.TempDir()
+
+	registry := providers.NewProviderRegistry()
+	providers.RegisterDefaultProviders(registry)
+
+	// Without an API key, the OpenAI provider can only replay
+	config := providers.NewModelConfig("gpt-4").WithReplay(providers.ReplayModeRecord, dir)
+	if _, err := registry.CreateModel(config); err == nil {
+		t.Error("Expected recording to need a working provider")
+	}
+
+	config.WithReplay(providers.ReplayModeReplay, dir)
+	model, err := registry.CreateModel(config)
+	if err != nil {
+		t.Fatalf("CreateModel() error = %v", err)
+	}
+	if _, ok := model.(*providers.ReplayProvider); !ok || model.GetModelID() != "gpt-4" {
+		t.Fatalf("Expected a replay provider for gpt-4, got %T", model)
+	}
+
+	cassette := `{"model_id": "gpt-4", "prompt": "prompt", "outputs": [{"output": "{\"extractions\": []}"}],
+		"usage": {"prompt_tokens": 5, "completion_tokens": 3, "total_tokens": 8}, "finish_reason": "stop"}`
+	if err := os.WriteFile(filepath.Join(dir, providers.CassetteKey("gpt-4", "prompt")+".json"), []byte(cassette), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := providers.InferDetailed(context.Background(), model, []string{"prompt"}, nil)
+	if err != nil {
+		t.Fatalf("InferDetailed() error = %v", err)
+	}
+	if results[0].Usage.TotalTokens != 8 || results[0].FinishReason != providers.FinishReasonStop {
+		t.Errorf("Expected replayed metadata, got %+v", results[0])
+	}
+	if parsed, err := model.ParseOutput(results[0].Outputs[0].Output); err != nil || parsed.(map[string]any)["extractions"] == nil {
+		t.Errorf("Expected parsed JSON output, got %v (%v)", parsed, err)
+	}
+}
@@ -0,0 +1,140 @@
+package providers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// statusResponse is a response of the retry test server
+type statusResponse struct {
+	status     int
+	body       string
+	retryAfter string
+}
+
+// newRetryTestProvider creates an OpenAI provider against a server that
+// answers with the given responses in turn, repeating the last one.
+func newRetryTestProvider(t *testing.T, responses ...statusResponse) (providers.BaseLanguageModel, *int32) {
+	t.Helper()
+	t.Setenv("OPENAI_API_KEY", "")
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&requests, 1))
+		if n > len(responses) {
+			n = len(responses)
+		}
+		response := responses[n-1]
+		if response.retryAfter != "" {
+			w.Header().Set("Retry-After", response.retryAfter)
+		}
+		w.WriteHeader(response.status)
+		fmt.Fprint(w, response.body)
+	}))
+	t.Cleanup(server.Close)
+
+	model, err := providers.NewOpenAIProvider(providers.NewModelConfig("gpt-4o-mini").
+		WithRetryPolicy(&providers.RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}).
+		WithProviderKwargs(map[string]any{"api_key": "test-key", "base_url": server.URL}))
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() error = %v", err)
+	}
+	return model, &requests
+}
+
+const retryTestSuccess = `{"choices": [{"message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`
+
+func TestRetryTransientErrors(t *testing.T) {
+	model, requests := newRetryTestProvider(t,
+		statusResponse{status: http.StatusServiceUnavailable, body: "overloaded"},
+		statusResponse{status: http.StatusTooManyRequests, body: `{"error": {"message": "slow down"}}`, retryAfter: "1"},
+		statusResponse{status: http.StatusOK, body: retryTestSuccess},
+	)
+
+	start := time.Now()
+	results, err := model.Infer(context.Background(), []string{"prompt"}, nil)
+	if err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	if results[0][0].Output != "ok" || atomic.LoadInt32(requests) != 3 {
+		t.Errorf("Expected success on the third attempt, got %v after %d requests", results, *requests)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("Expected Retry-After to be honored, retried after %v", elapsed)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	model, requests := newRetryTestProvider(t, statusResponse{status: http.StatusBadGateway, body: "bad gateway"})
+
+	_, err := model.Infer(context.Background(), []string{"prompt"}, nil)
+	if !errors.Is(err, providers.ErrServerError) || !providers.IsRetryable(err) {
+		t.Errorf("Expected a retryable server error, got %v", err)
+	}
+	if n := atomic.LoadInt32(requests); n != 3 {
+		t.Errorf("Expected 3 attempts, got %d", n)
+	}
+}
+
+func TestRetryTerminalErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response statusResponse
+		kind     error
+	}{
+		{
+			name:     "authentication",
+			response: statusResponse{status: http.StatusUnauthorized, body: `{"error": {"message": "Incorrect API key", "type": "invalid_request_error"}}`},
+			kind:     providers.ErrAuthentication,
+		},
+		{
+			name:     "bad request",
+			response: statusResponse{status: http.StatusBadRequest, body: `{"error": "invalid model"}`},
+			kind:     providers.ErrBadRequest,
+		},
+		{
+			name: "context length",
+			response: statusResponse{status: http.StatusBadRequest, body: `{"error": {"message": "This model's maximum context length is 8192 tokens.",
+				"type": "invalid_request_error", "code": "context_length_exceeded"}}`},
+			kind: providers.ErrContextLengthExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, requests := newRetryTestProvider(t, tt.response)
+
+			_, err := model.Infer(context.Background(), []string{"prompt"}, nil)
+			if !errors.Is(err, tt.kind) || providers.IsRetryable(err) {
+				t.Errorf("Expected terminal %v error, got %v", tt.kind, err)
+			}
+			var apiErr *providers.APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.response.status || apiErr.Message == "" {
+				t.Errorf("Expected the API error, got %#v", apiErr)
+			}
+			if n := atomic.LoadInt32(requests); n != 1 {
+				t.Errorf("Expected no retries, got %d requests", n)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &providers.RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for retry, limit := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		limit *= time.Millisecond
+		for i := 0; i < 20; i++ {
+			if backoff := policy.Backoff(retry); backoff < limit/2 || backoff > limit {
+				t.Fatalf("Backoff(%d) = %v, expected between %v and %v", retry, backoff, limit/2, limit)
+			}
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package providers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// streamServer serves the given lines with flushes in between, recording the
+// request body. If block is set, the handler waits for the client to go away
+// after writing the lines.
+func streamServer(t *testing.T, path string, lines []string, block bool) (*httptest.Server, *map[string]any) {
+	t.Helper()
+	var request map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if r.URL.RawQuery != "" {
+			request["query"] = r.URL.RawQuery
+		}
+
+		flusher := w.(http.Flusher)
+		for _, line := range lines {
+			fmt.Fprint(w, line)
+			flusher.Flush()
+		}
+		if block {
+			<-r.Context().Done()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &request
+}
+
+// collectStream reads a stream until it is closed
+func collectStream(t *testing.T, chunks <-chan providers.StreamChunk) (string, providers.StreamChunk) {
+	t.Helper()
+	var text strings.Builder
+	var last providers.StreamChunk
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case chunk, ok := <-chunks:
+			if !ok {
+				return text.String(), last
+			}
+			text.WriteString(chunk.Delta)
+			last = chunk
+		case <-timeout:
+			t.Fatal("Timed out reading stream")
+		}
+	}
+}
+
+func TestOpenAIProviderInferStream(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	server, request := streamServer(t, "/v1/chat/completions", []string{
+		"data: {\"id\":\"chatcmpl-9\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"\"}}]}\n\n",
+		": keep-alive\n\n",
+		"data: {\"id\":\"chatcmpl-9\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hello\"}}]}\n\n",
+		"data: {\"id\":\"chatcmpl-9\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\", world\"}}]}\n\n",
+		"data: {\"id\":\"chatcmpl-9\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n",
+		"data: {\"id\":\"chatcmpl-9\",\"choices\":[],\"usage\":{\"prompt_tokens\":8,\"completion_tokens\":3,\"total_tokens\":11}}\n\n",
+		"data: [DONE]\n\n",
+	}, false)
+
+	model, err := providers.NewOpenAIProvider(providers.NewModelConfig("gpt-4o-mini").WithProviderKwargs(map[string]any{
+		"api_key":  "test-key",
+		"base_url": server.URL + "/v1",
+	}))
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() error = %v", err)
+	}
+
+	chunks, err := model.(providers.StreamingLanguageModel).InferStream(context.Background(), "Say hello", nil)
+	if err != nil {
+		t.Fatalf("InferStream() error = %v", err)
+	}
+	text, last := collectStream(t, chunks)
+
+	if text != "Hello, world" {
+		t.Errorf("Expected streamed text, got %q", text)
+	}
+	if !last.Done || last.Err != nil || last.FinishReason != providers.FinishReasonStop || last.ResponseID != "chatcmpl-9" {
+		t.Errorf("Unexpected final chunk %+v", last)
+	}
+	if last.Usage.TotalTokens != 11 {
+		t.Errorf("Expected usage from the last event, got %+v", last.Usage)
+	}
+	if (*request)["stream"] != true || (*request)["stream_options"] == nil {
+		t.Errorf("Expected a streaming request, got %v", *request)
+	}
+}
+
+func TestGeminiProviderInferStream(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv("GOOGLE_API_KEY", "")
+	server, request := streamServer(t, "/v1beta/models/gemini-2.5-flash:streamGenerateContent", []string{
+		"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"{\\\"extractions\\\":\"}]}}],\"responseId\":\"resp-3\"}\r\n\r\n",
+		"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\" []}\"}]},\"finishReason\":\"STOP\"}],",
+		"\"usageMetadata\":{\"promptTokenCount\":5,\"candidatesTokenCount\":4,\"totalTokenCount\":9}}\r\n\r\n",
+	}, false)
+
+	model, err := providers.NewGeminiProvider(providers.NewModelConfig("gemini-2.5-flash").WithProviderKwargs(map[string]any{
+		"api_key":  "test-key",
+		"base_url": server.URL + "/v1beta",
+	}))
+	if err != nil {
+		t.Fatalf("NewGeminiProvider() error = %v", err)
+	}
+
+	chunks, err := model.(providers.StreamingLanguageModel).InferStream(context.Background(), "prompt", nil)
+	if err != nil {
+		t.Fatalf("InferStream() error = %v", err)
+	}
+	text, last := collectStream(t, chunks)
+
+	if text != `{"extractions": []}` {
+		t.Errorf("Expected streamed text, got %q", text)
+	}
+	if !last.Done || last.FinishReason != providers.FinishReasonStop || last.ResponseID != "resp-3" || last.Usage.TotalTokens != 9 {
+		t.Errorf("Unexpected final chunk %+v", last)
+	}
+	if (*request)["query"] != "alt=sse" {
+		t.Errorf("Expected SSE query, got %v", (*request)["query"])
+	}
+}
+
+func TestOllamaProviderInferStream(t *testing.T) {
+	lines := []string{
+		`{"model":"llama3.2","response":"Hel","done":false}` + "\n",
+		`{"model":"llama3.2","response":"lo","done":false}` + "\n",
+		`{"model":"llama3.2","response":"","done":true,"done_reason":"length","prompt_eval_count":7,"eval_count":2}` + "\n",
+	}
+	api := &fakeOllamaAPI{version: "0.5.7"}
+	mux := http.NewServeMux()
+	mux.Handle("/api/tags", api)
+	mux.HandleFunc("/api/generate", func(w http.ResponseWriter, r *http.Request) {
+		for _, line := range lines {
+			fmt.Fprint(w, line)
+			w.(http.Flusher).Flush()
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	model, err := providers.NewOllamaProvider(providers.NewModelConfig("llama3.2").WithProviderKwargs(map[string]any{
+		"base_url": server.URL,
+	}))
+	if err != nil {
+		t.Fatalf("NewOllamaProvider() error = %v", err)
+	}
+
+	chunks, err := model.(providers.StreamingLanguageModel).InferStream(context.Background(), "prompt", nil)
+	if err != nil {
+		t.Fatalf("InferStream() error = %v", err)
+	}
+	text, last := collectStream(t, chunks)
+
+	if text != "Hello" {
+		t.Errorf("Expected streamed text, got %q", text)
+	}
+	if !last.Done || last.FinishReason != providers.FinishReasonLength || last.Usage.TotalTokens != 9 {
+		t.Errorf("Unexpected final chunk %+v", last)
+	}
+	if usage := model.(*providers.OllamaProvider).Usage(); usage.TotalTokens != 9 {
+		t.Errorf("Expected streamed usage to be recorded, got %+v", usage)
+	}
+}
+
+func TestInferStreamErrorsAndCancellation(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	newModel := func(t *testing.T, lines []string, block bool) providers.StreamingLanguageModel {
+		server, _ := streamServer(t, "/v1/chat/completions", lines, block)
+		model, err := providers.NewOpenAIProvider(providers.NewModelConfig("gpt-4o-mini").WithProviderKwargs(map[string]any{
+			"api_key":  "test-key",
+			"base_url": server.URL + "/v1",
+		}))
+		if err != nil {
+			t.Fatalf("NewOpenAIProvider() error = %v", err)
+		}
+		return model.(providers.StreamingLanguageModel)
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		model := newModel(t, []string{"data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hel\"}}]}\n\n"}, false)
+		chunks, err := model.InferStream(context.Background(), "prompt", nil)
+		if err != nil {
+			t.Fatalf("InferStream() error = %v", err)
+		}
+		_, last := collectStream(t, chunks)
+		if last.Err == nil || last.Done {
+			t.Errorf("Expected an error for a truncated stream, got %+v", last)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		model := newModel(t, []string{"data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hel\"}}]}\n\n"}, true)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		chunks, err := model.InferStream(ctx, "prompt", nil)
+		if err != nil {
+			t.Fatalf("InferStream() error = %v", err)
+		}
+		if first := <-chunks; first.Delta != "Hel" {
+			t.Fatalf("Expected first delta, got %+v", first)
+		}
+
+		cancel()
+		_, last := collectStream(t, chunks)
+		if last.Done {
+			t.Errorf("Expected no final chunk after cancellation, got %+v", last)
+		}
+	})
+}
+
+func TestInferStreamHoldsRequestSlot(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	lines := []string{"data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hel\"}}]}\n\n"}
+	server, _ := streamServer(t, "/v1/chat/completions", lines, true)
+
+	// One request at a time and one request per minute, for a model of its own
+	// since rate limits are shared per model
+	model, err := providers.NewOpenAIProvider(providers.NewModelConfig(fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())).
+		WithMaxConcurrency(1).
+		WithRateLimit(1, 0).
+		WithProviderKwargs(map[string]any{"api_key": "test-key", "base_url": server.URL + "/v1"}))
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() error = %v", err)
+	}
+	streaming := model.(providers.StreamingLanguageModel)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	chunks, err := streaming.InferStream(ctx, "prompt", nil)
+	if err != nil {
+		t.Fatalf("InferStream() error = %v", err)
+	}
+	<-chunks
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer waitCancel()
+	if _, err := streaming.InferStream(waitCtx, "prompt", nil); err != context.DeadlineExceeded {
+		t.Errorf("Expected a second stream to wait for the slot, got %v", err)
+	}
+
+	// A cancelled stream frees its slot and returns its reservation
+	cancel()
+	collectStream(t, chunks)
+
+	nextCtx, nextCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer nextCancel()
+	next, err := streaming.InferStream(nextCtx, "prompt", nil)
+	if err != nil {
+		t.Fatalf("Expected the slot to be freed after cancellation, got %v", err)
+	}
+	if first := <-next; first.Delta != "Hel" {
+		t.Errorf("Expected first delta, got %+v", first)
+	}
+}
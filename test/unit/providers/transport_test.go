@@ -0,0 +1,175 @@
+package providers_test
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sehwan505/langextract-go/pkg/providers"
+)
+
+// newTransportTestConfig creates an OpenAI model config against the server,
+// without retries.
+func newTransportTestConfig(t *testing.T, serverURL string) *providers.ModelConfig {
+	t.Helper()
+	t.Setenv("OPENAI_API_KEY", "")
+	return providers.NewModelConfig("gpt-4o-mini").
+		WithRetryPolicy(&providers.RetryPolicy{MaxRetries: 0}).
+		WithProviderKwargs(map[string]any{"api_key": "test-key", "base_url": serverURL})
+}
+
+func TestHTTPConfigTLSAndHeaders(t *testing.T) {
+	var header string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Team")
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("Expected the provider's authorization header, got %q", r.Header.Get("Authorization"))
+		}
+		fmt.Fprint(w, retryTestSuccess)
+	}))
+	defer server.Close()
+
+	// Without the server's CA the certificate is rejected
+	model, err := providers.NewOpenAIProvider(newTransportTestConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() error = %v", err)
+	}
+	if _, err := model.Infer(context.Background(), []string{"prompt"}, nil); err == nil {
+		t.Fatal("Expected an untrusted certificate to be rejected")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := newTransportTestConfig(t, server.URL)
+	config.ProviderKwargs["http"] = map[string]any{
+		"ca_file": caFile,
+		"headers": map[string]any{"X-Team": "extraction", "Authorization": "ignored"},
+	}
+	model, err = providers.NewOpenAIProvider(config)
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() error = %v", err)
+	}
+	results, err := model.Infer(context.Background(), []string{"prompt"}, nil)
+	if err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	if results[0][0].Output != "ok" || header != "extraction" {
+		t.Errorf("Expected the configured header to be sent, got output %v and header %q", results, header)
+	}
+}
+
+func TestHTTPConfigInvalid(t *testing.T) {
+	invalid := map[string]any{
+		"unknown setting": map[string]any{"proxy": "http://proxy"},
+		"timeout":         map[string]any{"timeout": "soon"},
+		"kwarg type":      "http://proxy",
+		"proxy URL":       &providers.HTTPConfig{ProxyURL: "://proxy"},
+		"CA file":         &providers.HTTPConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+	}
+	for name, value := range invalid {
+		t.Run(name, func(t *testing.T) {
+			config := newTransportTestConfig(t, "http://localhost")
+			config.ProviderKwargs["http"] = value
+			if _, err := providers.NewOpenAIProvider(config); err == nil {
+				t.Error("Expected an invalid HTTP configuration to be rejected")
+			}
+		})
+	}
+}
+
+func TestHTTPClientInjection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, retryTestSuccess)
+	}))
+	defer server.Close()
+
+	var requests int32
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+
+	model, err := providers.NewOpenAIProvider(newTransportTestConfig(t, server.URL).WithHTTPClient(client))
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() error = %v", err)
+	}
+	if _, err := model.Infer(context.Background(), []string{"prompt"}, nil); err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("Expected the injected client to send the request, got %d requests", requests)
+	}
+}
+
+func TestHTTPConfigTimeout(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+			return
+		}
+		fmt.Fprint(w, retryTestSuccess)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	config := newTransportTestConfig(t, server.URL).
+		WithHTTPConfig(&providers.HTTPConfig{Timeout: 100 * time.Millisecond})
+	model, err := providers.NewOpenAIProvider(config)
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() error = %v", err)
+	}
+
+	_, err = model.Infer(context.Background(), []string{"prompt"}, nil)
+	if !errors.Is(err, providers.ErrTimeout) || !providers.IsRetryable(err) {
+		t.Errorf("Expected a retryable timeout, got %v", err)
+	}
+
+	// Each attempt gets the full timeout, so a retry can succeed
+	atomic.StoreInt32(&requests, 0)
+	config.Retry = &providers.RetryPolicy{MaxRetries: 1, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	model, err = providers.NewOpenAIProvider(config)
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() error = %v", err)
+	}
+	if _, err := model.Infer(context.Background(), []string{"prompt"}, nil); err != nil {
+		t.Errorf("Expected the retry to succeed, got %v", err)
+	}
+
+	// A deadline of the caller takes precedence over the timeout
+	atomic.StoreInt32(&requests, 0)
+	config.Retry = &providers.RetryPolicy{MaxRetries: 0}
+	config.HTTP.Timeout = time.Minute
+	model, err = providers.NewOpenAIProvider(config)
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := model.Infer(ctx, []string{"prompt"}, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the caller's deadline to expire, got %v", err)
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}